@@ -0,0 +1,123 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	gh "github.com/mrsimonemms/golang-helpers"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var compileOpts struct {
+	FilePath string
+	Output   string
+}
+
+// compileCmd represents the compile command
+var compileCmd = &cobra.Command{
+	Use:     "compile",
+	Aliases: []string{"dry-run"},
+	Short:   "Print the Temporal workflows generated from the workflow file",
+	Long: `Builds the workflow file without connecting to Temporal and prints the
+workflows that would be registered, their tasks and any child workflows`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workflowDefinition, err := zigflow.LoadFromFile(compileOpts.FilePath)
+		if err != nil {
+			return gh.FatalError{
+				Cause: err,
+				Msg:   "Unable to load workflow file",
+			}
+		}
+
+		if err := validateWorkflow(workflowDefinition); err != nil {
+			return err
+		}
+
+		graph, err := zigflow.Compile(workflowDefinition)
+		if err != nil {
+			return gh.FatalError{
+				Cause: err,
+				Msg:   "Unable to build workflow from DSL",
+			}
+		}
+
+		switch compileOpts.Output {
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(graph)
+		case "tree":
+			printGraphTree(cmd.OutOrStdout(), graph)
+			return nil
+		default:
+			return gh.FatalError{
+				Msg: "Unknown output format",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Str("output", compileOpts.Output)
+				},
+			}
+		}
+	},
+}
+
+func printGraphTree(w io.Writer, graph *zigflow.WorkflowGraph) {
+	if graph.Description != "" {
+		_, _ = fmt.Fprintln(w, graph.Description)
+		_, _ = fmt.Fprintln(w)
+	}
+
+	for _, wf := range graph.Workflows {
+		_, _ = fmt.Fprintln(w, wf.Name)
+		for i, t := range wf.Tasks {
+			branch := "├──"
+			if i == len(wf.Tasks)-1 {
+				branch = "└──"
+			}
+
+			line := fmt.Sprintf("%s %s (%s)", branch, t.Name, t.Type)
+			if len(t.ChildWorkflows) > 0 {
+				line += " -> " + strings.Join(t.ChildWorkflows, ", ")
+			}
+			if t.Description != "" {
+				line += " - " + t.Description
+			}
+			_, _ = fmt.Fprintln(w, line)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(compileCmd)
+
+	compileCmd.Flags().StringVarP(
+		&compileOpts.FilePath, "file", "f",
+		viper.GetString("workflow_file"), "Path to workflow file",
+	)
+
+	viper.SetDefault("compile_output", "tree")
+	compileCmd.Flags().StringVarP(
+		&compileOpts.Output, "output", "o",
+		viper.GetString("compile_output"), "Output format - tree or json",
+	)
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/mrsimonemms/golang-helpers"
+	"github.com/mrsimonemms/golang-helpers/temporal"
+	"github.com/mrsimonemms/zigflow/pkg/codec"
+	"github.com/mrsimonemms/zigflow/pkg/codec/aes"
+	"github.com/mrsimonemms/zigflow/pkg/codec/rsa"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+)
+
+// Algorithms supported by the data converter
+const (
+	convertAlgorithmAES = "aes"
+	convertAlgorithmRSA = "rsa"
+)
+
+// newDataConverter encrypts the payloads with the chosen algorithm. The key
+// file must match the algorithm. Payloads that can't be decrypted are logged
+// with their workflow and key.
+func newDataConverter() (converter.DataConverter, error) {
+	if !rootOpts.ConvertData {
+		return nil, nil
+	}
+
+	source := keySource()
+	dataConverter, err := loadDataConverter(source)
+	if err != nil {
+		return nil, gh.FatalError{
+			Cause: err,
+			Msg:   "Unable to get conversion keys",
+			WithParams: func(l *zerolog.Event) *zerolog.Event {
+				return l.Str("algorithm", rootOpts.ConvertAlgorithm).Stringer("source", source)
+			},
+		}
+	}
+
+	return codec.NewLoggingDataConverter(dataConverter), nil
+}
+
+// keySource loads the keys from the environment variable, if set, otherwise
+// from the key file
+func keySource() codec.KeySource {
+	if rootOpts.ConvertKeyEnv != "" {
+		return codec.NewEnvKeySource(rootOpts.ConvertKeyEnv)
+	}
+
+	return codec.NewFileKeySource(rootOpts.ConvertKeyPath)
+}
+
+func loadDataConverter(source codec.KeySource) (converter.DataConverter, error) {
+	data, err := source.Load(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	switch rootOpts.ConvertAlgorithm {
+	case convertAlgorithmAES:
+		keys, err := codec.ParseAESKeys(data)
+		if err != nil {
+			return nil, err
+		}
+		return aes.DataConverter(keys), nil
+	case convertAlgorithmRSA:
+		keys, err := rsa.ParseKeys(data)
+		if err != nil {
+			return nil, err
+		}
+		return rsa.DataConverter(keys), nil
+	default:
+		return nil, fmt.Errorf("unknown algorithm")
+	}
+}
+
+// newTemporalClient connects to Temporal using the connection flags. Any
+// additional options are applied after the defaults.
+func newTemporalClient(opts ...temporal.Options) (client.Client, error) {
+	converter, err := newDataConverter()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().Msg("Connecting to Temporal")
+	c, err := temporal.NewConnection(append([]temporal.Options{
+		temporal.WithHostPort(rootOpts.TemporalAddress),
+		temporal.WithNamespace(rootOpts.TemporalNamespace),
+		temporal.WithTLS(rootOpts.TemporalTLSEnabled),
+		temporal.WithAuthDetection(
+			rootOpts.TemporalAPIKey,
+			rootOpts.TemporalMTLSCertPath,
+			rootOpts.TemporalMTLSKeyPath,
+		),
+		temporal.WithDataConverter(converter),
+		temporal.WithZerolog(&log.Logger),
+	}, opts...)...)
+	if err != nil {
+		return nil, gh.FatalError{
+			Cause: err,
+			Msg:   "Unable to create client",
+		}
+	}
+
+	return c, nil
+}
+
+// addConnectionFlags adds the flags used to connect to Temporal. This is
+// called from the commands' init functions, so runs after the envvars are
+// configured.
+func addConnectionFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&rootOpts.ConvertData, "convert-data",
+		viper.GetBool("convert_data"), "Enable data conversion",
+	)
+
+	viper.SetDefault("convert_algorithm", convertAlgorithmAES)
+	flags.StringVar(
+		&rootOpts.ConvertAlgorithm, "convert-algorithm",
+		viper.GetString("convert_algorithm"), "Data conversion algorithm - aes or rsa",
+	)
+
+	flags.StringVar(
+		&rootOpts.ConvertKeyEnv, "converter-key-env",
+		viper.GetString("converter_key_env"), "Envvar containing the base64-encoded conversion keys, used instead of the key path",
+	)
+
+	viper.SetDefault("converter_key_path", "keys.yaml")
+	flags.StringVar(
+		&rootOpts.ConvertKeyPath, "converter-key-path",
+		viper.GetString("converter_key_path"), "Path to conversion keys",
+	)
+
+	viper.SetDefault("temporal_address", client.DefaultHostPort)
+	flags.StringVarP(
+		&rootOpts.TemporalAddress, "temporal-address", "H",
+		viper.GetString("temporal_address"), "Address of the Temporal server",
+	)
+
+	flags.StringVar(
+		&rootOpts.TemporalAPIKey, "temporal-api-key",
+		viper.GetString("temporal_api_key"), "API key for Temporal authentication",
+	)
+	// Hide the default value to avoid spaffing the API to command line
+	apiKey := flags.Lookup("temporal-api-key")
+	if s := apiKey.Value; s.String() != "" {
+		apiKey.DefValue = "***"
+	}
+
+	flags.StringVar(
+		&rootOpts.TemporalMTLSCertPath, "tls-client-cert-path",
+		viper.GetString("temporal_tls_client_cert_path"), "Path to mTLS client cert, usually ending in .pem",
+	)
+
+	flags.StringVar(
+		&rootOpts.TemporalMTLSKeyPath, "tls-client-key-path",
+		viper.GetString("temporal_tls_client_key_path"), "Path to mTLS client key, usually ending in .key",
+	)
+
+	viper.SetDefault("temporal_namespace", client.DefaultNamespace)
+	flags.StringVarP(
+		&rootOpts.TemporalNamespace, "temporal-namespace", "n",
+		viper.GetString("temporal_namespace"), "Temporal namespace to use",
+	)
+
+	flags.BoolVar(
+		&rootOpts.TemporalTLSEnabled, "temporal-tls",
+		viper.GetBool("temporal_tls"), "Enable TLS Temporal connection",
+	)
+}
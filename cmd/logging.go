@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	logFormatConsole = "console"
+	logFormatJSON    = "json"
+)
+
+// configureLogger sets the format of the global logger. This is also used by
+// the Temporal SDK, so must be done before the client is created. If no format
+// is given, console output is used in a terminal and JSON otherwise.
+func configureLogger(format string) error {
+	// Mask any secrets, which are added once the workflow is loaded
+	stderr := utils.LogSecrets.Writer(os.Stderr)
+	out := stderr
+
+	if format == "" {
+		format = logFormatJSON
+		if isTerminal(os.Stderr) {
+			format = logFormatConsole
+		}
+	}
+
+	switch format {
+	case logFormatConsole:
+		out = zerolog.ConsoleWriter{Out: stderr}
+	case logFormatJSON:
+	default:
+		return fmt.Errorf("unknown log format: %s", format)
+	}
+
+	log.Logger = zerolog.New(out).With().Timestamp().Logger()
+
+	return nil
+}
+
+// isTerminal returns true if the file is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -18,39 +18,69 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 	"os"
+	"time"
 
 	gh "github.com/mrsimonemms/golang-helpers"
 	"github.com/mrsimonemms/golang-helpers/temporal"
-	"github.com/mrsimonemms/temporal-codec-server/packages/golang/algorithms/aes"
+	"github.com/mrsimonemms/zigflow/pkg/health"
 	"github.com/mrsimonemms/zigflow/pkg/utils"
 	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/tasks"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"go.temporal.io/sdk/client"
-	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/worker"
 )
 
 var rootOpts struct {
-	ConvertData          bool
-	ConvertKeyPath       string
-	EnvPrefix            string
-	FilePath             string
-	HealthListenAddress  string
-	LogLevel             string
-	MetricsListenAddress string
-	MetricsPrefix        string
-	TemporalAddress      string
-	TemporalAPIKey       string
-	TemporalMTLSCertPath string
-	TemporalMTLSKeyPath  string
-	TemporalTLSEnabled   bool
-	TemporalNamespace    string
-	Validate             bool
+	ActivityPollers              int
+	AllowContainers              bool
+	AllowScripts                 bool
+	BuildID                      string
+	ContextPropagationKeys       []string
+	ConvertAlgorithm             string
+	ConvertData                  bool
+	ConvertKeyEnv                string
+	ContainerRuntime             string
+	DisableStateQuery            bool
+	DisableTaskMetrics           bool
+	ConvertKeyPath               string
+	DefaultActivityBackoff       float64
+	DefaultActivityMaxAttempts   int32
+	DefaultActivityTimeout       time.Duration
+	EnvPrefix                    string
+	FilePath                     string
+	GracefulShutdownTimeout      time.Duration
+	HealthListenAddress          string
+	HTTPMaxBodyBytes             int64
+	LenientSearchAttributes      bool
+	LogFormat                    string
+	LogLevel                     string
+	MaxConcurrentActivities      int
+	MaxConcurrentLocalActivities int
+	MaxConcurrentWorkflowTasks   int
+	MetricsListenAddress         string
+	MetricsPrefix                string
+	NexusPollers                 int
+	OTelEndpoint                 string
+	OutputOffloadSize            int
+	OutputStorePath              string
+	PollerBehavior               string
+	TaskQueue                    string
+	TemporalAddress              string
+	TemporalAPIKey               string
+	TemporalMTLSCertPath         string
+	TemporalMTLSKeyPath          string
+	TemporalTLSEnabled           bool
+	TemporalNamespace            string
+	UnknownMetadataKeys          string
+	Validate                     bool
+	WorkerIdentity               string
+	WorkflowPollers              int
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -67,93 +97,43 @@ var rootCmd = &cobra.Command{
 		}
 		zerolog.SetGlobalLevel(level)
 
-		return nil
+		return configureLogger(rootOpts.LogFormat)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		defer func() {
-			if r := recover(); r != nil {
-				var panicMsg string
-				switch v := r.(type) {
-				case error:
-					panicMsg = v.Error()
-				case string:
-					panicMsg = v
-				default:
-					panicMsg = fmt.Sprintf("%+v", v)
-				}
-
-				log.Fatal().
-					Str("type", fmt.Sprintf("%T", r)).
-					Str("panicMsg", panicMsg).
-					Msg("Recovered from panic")
-			}
-		}()
-
 		workflowDefinition, err := zigflow.LoadFromFile(rootOpts.FilePath)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Unable to load workflow file")
+			return gh.FatalError{
+				Cause: err,
+				Msg:   "Unable to load workflow file",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Str("file", rootOpts.FilePath)
+				},
+			}
 		}
 
 		if rootOpts.Validate {
-			log.Debug().Msg("Running validation")
-
-			validator, err := utils.NewValidator()
-			if err != nil {
-				log.Fatal().Err(err).Msg("Error creating validator")
-			}
-
-			if res, err := validator.ValidateStruct(workflowDefinition); err != nil {
-				return gh.FatalError{
-					Cause: err,
-					Msg:   "Error creating validation stack",
-				}
-			} else if res != nil {
-				return gh.FatalError{
-					Cause: err,
-					Msg:   "Validation failed",
-					WithParams: func(l *zerolog.Event) *zerolog.Event {
-						return l.Interface("validationErrors", res)
-					},
-				}
+			if err := validateWorkflow(workflowDefinition); err != nil {
+				return err
 			}
-			log.Debug().Msg("Validation passed")
 		}
 
-		var converter converter.DataConverter
-		if rootOpts.ConvertData {
-			keys, err := aes.ReadKeyFile(rootOpts.ConvertKeyPath)
-			if err != nil {
-				return gh.FatalError{
-					Cause: err,
-					Msg:   "Unable to get keys from file",
-					WithParams: func(l *zerolog.Event) *zerolog.Event {
-						return l.Str("keypath", rootOpts.ConvertKeyPath)
-					},
-				}
-			}
-			converter = aes.DataConverter(keys)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		shutdownTracing, err := configureTracing(ctx)
+		if err != nil {
+			return err
 		}
+		defer shutdownTracing()
 
 		// The client and worker are heavyweight objects that should be created once per process.
-		log.Trace().Msg("Connecting to Temporal")
-		client, err := temporal.NewConnection(
-			temporal.WithHostPort(rootOpts.TemporalAddress),
-			temporal.WithNamespace(rootOpts.TemporalNamespace),
-			temporal.WithTLS(rootOpts.TemporalTLSEnabled),
-			temporal.WithAuthDetection(
-				rootOpts.TemporalAPIKey,
-				rootOpts.TemporalMTLSCertPath,
-				rootOpts.TemporalMTLSKeyPath,
-			),
-			temporal.WithDataConverter(converter),
-			temporal.WithZerolog(&log.Logger),
+		client, err := newTemporalClient(
 			temporal.WithPrometheusMetrics(rootOpts.MetricsListenAddress, rootOpts.MetricsPrefix),
+			withTracingPropagator(),
+			withHeaderPropagator(),
 		)
 		if err != nil {
-			return gh.FatalError{
-				Cause: err,
-				Msg:   "Unable to create client",
-			}
+			return err
 		}
 		defer func() {
 			log.Trace().Msg("Closing Temporal connection")
@@ -161,22 +141,38 @@ var rootCmd = &cobra.Command{
 			log.Trace().Msg("Temporal connection closed")
 		}()
 
-		taskQueue := workflowDefinition.Document.Namespace
+		taskQueue, err := resolveTaskQueue(workflowDefinition)
+		if err != nil {
+			return err
+		}
 
 		// Add underscore to the prefix
 		prefix := rootOpts.EnvPrefix
 		prefix += "_"
 
 		log.Debug().Str("prefix", prefix).Msg("Loading envvars to state")
-		envvars := utils.LoadEnvvars(prefix)
+		envvars, err := metadata.ResolveEnvvars(workflowDefinition, utils.LoadEnvvars(prefix))
+		if err != nil {
+			return gh.FatalError{
+				Cause: err,
+				Msg:   "Invalid envvars",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Str("prefix", prefix)
+				},
+			}
+		}
 
-		ctx := context.Background()
+		// The health server and the worker can both fail
+		fatalErr := make(chan error, 2)
 
 		log.Debug().Msg("Starting health check service")
-		temporal.NewHealthCheck(ctx, taskQueue, rootOpts.HealthListenAddress, client)
+		healthServer := health.New(taskQueue, client)
+		healthServer.ListenAndServe(ctx, rootOpts.HealthListenAddress, fatalErr)
+
+		configureSearchAttributes(ctx, client)
 
 		log.Info().Msg("Updating schedules")
-		if err := zigflow.UpdateSchedules(ctx, client, workflowDefinition, envvars); err != nil {
+		if err := zigflow.UpdateSchedules(ctx, client, workflowDefinition, taskQueue, envvars); err != nil {
 			return gh.FatalError{
 				Cause: err,
 				Msg:   "Error updating Temporal schedules",
@@ -185,31 +181,153 @@ var rootCmd = &cobra.Command{
 
 		log.Info().Str("task-queue", taskQueue).Msg("Starting workflow")
 
-		pollerAutoscaler := worker.NewPollerBehaviorAutoscaling(worker.PollerBehaviorAutoscalingOptions{})
-		temporalWorker := worker.New(client, taskQueue, worker.Options{
-			WorkflowTaskPollerBehavior: pollerAutoscaler,
-			ActivityTaskPollerBehavior: pollerAutoscaler,
-			NexusTaskPollerBehavior:    pollerAutoscaler,
-		})
+		opts, err := workerOptions(workflowDefinition.Document.Name)
+		if err != nil {
+			return err
+		}
+		opts.OnFatalError = func(err error) {
+			fatalErr <- err
+		}
+		temporalWorker := worker.New(client, taskQueue, opts)
 
-		if err := zigflow.NewWorkflow(temporalWorker, workflowDefinition, envvars); err != nil {
+		if err := configureOutputStore(); err != nil {
+			return err
+		}
+
+		tasks.SetTaskMetrics(!rootOpts.DisableTaskMetrics)
+		tasks.SetStateQuery(!rootOpts.DisableStateQuery)
+
+		if rootOpts.HTTPMaxBodyBytes < 0 {
+			return gh.FatalError{
+				Msg: "HTTP max body bytes must not be negative",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Int64("maxBodyBytes", rootOpts.HTTPMaxBodyBytes)
+				},
+			}
+		}
+		tasks.SetHTTPMaxBodyBytes(rootOpts.HTTPMaxBodyBytes)
+		tasks.SetAllowScripts(rootOpts.AllowScripts)
+		tasks.SetAllowContainers(rootOpts.AllowContainers)
+		tasks.SetContainerRuntime(rootOpts.ContainerRuntime)
+
+		activityDefaults := tasks.ActivityDefaults{
+			Timeout:            rootOpts.DefaultActivityTimeout,
+			MaxAttempts:        rootOpts.DefaultActivityMaxAttempts,
+			BackoffCoefficient: rootOpts.DefaultActivityBackoff,
+		}
+		if err := activityDefaults.Validate(); err != nil {
 			return gh.FatalError{
 				Cause: err,
-				Msg:   "Unable to build workflow from DSL",
+				Msg:   "Invalid default activity options",
 			}
 		}
 
-		if err := temporalWorker.Run(worker.InterruptCh()); err != nil {
+		workflows, err := zigflow.NewWorkflow(temporalWorker, workflowDefinition, envvars, activityDefaults)
+		if err != nil {
+			// The error names the task that failed to build
 			return gh.FatalError{
 				Cause: err,
-				Msg:   "Unable to start worker",
+				Msg:   "Unable to build workflow from DSL",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Str("workflowName", workflowDefinition.Document.Name)
+				},
 			}
 		}
+		// The description is validated when the workflow is built
+		description, _ := metadata.GetDescription(workflowDefinition.Document.Metadata)
+		healthServer.SetInfo(health.Info{
+			Name:        workflowDefinition.Document.Name,
+			Namespace:   workflowDefinition.Document.Namespace,
+			Version:     workflowDefinition.Document.Version,
+			DSL:         workflowDefinition.Document.DSL,
+			Description: description,
+			TaskQueue:   taskQueue,
+			Workflows:   workflows,
+		})
 
-		return nil
+		return runWorker(temporalWorker, healthServer, fatalErr)
 	},
 }
 
+func validateWorkflow(workflowDefinition *model.Workflow) error {
+	log.Debug().Msg("Running validation")
+
+	validator, err := utils.NewValidator()
+	if err != nil {
+		return gh.FatalError{
+			Cause: err,
+			Msg:   "Error creating validator",
+		}
+	}
+
+	res, err := validator.ValidateStruct(workflowDefinition)
+	if err != nil {
+		return gh.FatalError{
+			Cause: err,
+			Msg:   "Error creating validation stack",
+		}
+	}
+
+	// Report unsupported tasks, dangling flow directives and invalid runtime
+	// expressions alongside any other validation errors
+	res = append(res, tasks.ValidateTaskTypes(workflowDefinition)...)
+	res = append(res, utils.ValidateFlowDirectives(workflowDefinition)...)
+	res = append(res, utils.ValidateExpressions(workflowDefinition)...)
+	if len(res) > 0 {
+		return gh.FatalError{
+			Msg: "Validation failed",
+			WithParams: func(l *zerolog.Event) *zerolog.Event {
+				return l.Interface("validationErrors", res)
+			},
+		}
+	}
+
+	if res := metadata.ValidateKeys(workflowDefinition); len(res) > 0 {
+		switch rootOpts.UnknownMetadataKeys {
+		case metadata.UnknownKeysIgnore:
+			log.Debug().Interface("validationErrors", res).Msg("Ignoring unknown metadata keys")
+		case metadata.UnknownKeysWarn:
+			log.Warn().Interface("validationErrors", res).Msg("Unknown metadata keys found")
+		case metadata.UnknownKeysError:
+			return gh.FatalError{
+				Msg: "Unknown metadata keys found",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Interface("validationErrors", res)
+				},
+			}
+		default:
+			return gh.FatalError{
+				Msg: "Unknown metadata keys mode",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Str("mode", rootOpts.UnknownMetadataKeys)
+				},
+			}
+		}
+	}
+
+	log.Debug().Msg("Validation passed")
+
+	return nil
+}
+
+// resolveTaskQueue returns the task queue the worker polls. The flag takes
+// precedence over the document, which defaults to its name.
+func resolveTaskQueue(workflowDefinition *model.Workflow) (string, error) {
+	if rootOpts.TaskQueue != "" {
+		return rootOpts.TaskQueue, nil
+	}
+
+	taskQueue, err := metadata.GetTaskQueue(workflowDefinition)
+	if err != nil {
+		return "", gh.FatalError{
+			Cause: err,
+			Msg:   "Invalid task queue",
+		}
+	}
+
+	return taskQueue, nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -221,15 +339,22 @@ func Execute() {
 func init() {
 	viper.AutomaticEnv()
 
+	addConnectionFlags(rootCmd.Flags())
+
+	rootCmd.Flags().BoolVar(
+		&rootOpts.AllowContainers, "allow-containers",
+		viper.GetBool("allow_containers"), "Allow run tasks to run containers on the worker's host",
+	)
+
 	rootCmd.Flags().BoolVar(
-		&rootOpts.ConvertData, "convert-data",
-		viper.GetBool("convert_data"), "Enable AES data conversion",
+		&rootOpts.AllowScripts, "allow-scripts",
+		viper.GetBool("allow_scripts"), "Allow run tasks to run scripts on the worker",
 	)
 
-	viper.SetDefault("converter_key_path", "keys.yaml")
+	viper.SetDefault("container_runtime", "docker")
 	rootCmd.Flags().StringVar(
-		&rootOpts.ConvertKeyPath, "converter-key-path",
-		viper.GetString("converter_key_path"), "Path to AES conversion keys",
+		&rootOpts.ContainerRuntime, "container-runtime",
+		viper.GetString("container_runtime"), "CLI used to run containers, such as docker or podman",
 	)
 
 	rootCmd.Flags().StringVarP(
@@ -237,6 +362,21 @@ func init() {
 		viper.GetString("workflow_file"), "Path to workflow file",
 	)
 
+	rootCmd.Flags().Float64Var(
+		&rootOpts.DefaultActivityBackoff, "default-activity-backoff",
+		viper.GetFloat64("default_activity_backoff"), "Default backoff coefficient of activity retries. Overridden by the retryPolicy document metadata",
+	)
+
+	rootCmd.Flags().Int32Var(
+		&rootOpts.DefaultActivityMaxAttempts, "default-activity-max-attempts",
+		viper.GetInt32("default_activity_max_attempts"), "Default maximum attempts of an activity. Overridden by the retryPolicy document metadata",
+	)
+
+	rootCmd.Flags().DurationVar(
+		&rootOpts.DefaultActivityTimeout, "default-activity-timeout",
+		viper.GetDuration("default_activity_timeout"), "Default start-to-close timeout of an activity. Overridden by the document timeout",
+	)
+
 	viper.SetDefault("env_prefix", "ZIGGY")
 	rootCmd.Flags().StringVar(
 		&rootOpts.EnvPrefix, "env-prefix",
@@ -249,6 +389,16 @@ func init() {
 		viper.GetString("health_listen_address"), "Address of health server",
 	)
 
+	rootCmd.Flags().Int64Var(
+		&rootOpts.HTTPMaxBodyBytes, "http-max-body-bytes",
+		viper.GetInt64("http_max_body_bytes"), "Default maximum size of an HTTP response body, in bytes. Set to 0 for no limit",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&rootOpts.LogFormat, "log-format",
+		viper.GetString("log_format"), "Set log format - json or console. Defaults to console in a terminal, otherwise json",
+	)
+
 	viper.SetDefault("log_level", zerolog.InfoLevel.String())
 	rootCmd.PersistentFlags().StringVarP(
 		&rootOpts.LogLevel, "log-level", "l",
@@ -261,46 +411,30 @@ func init() {
 		viper.GetString("metrics_listen_address"), "Address of Prometheus metrics server",
 	)
 
-	rootCmd.Flags().StringVar(
-		&rootOpts.MetricsPrefix, "metrics-prefix",
-		viper.GetString("metrics_prefix"), "Prefix for metrics",
+	rootCmd.Flags().BoolVar(
+		&rootOpts.DisableTaskMetrics, "disable-task-metrics",
+		viper.GetBool("disable_task_metrics"), "Disable the per-task metrics, which are labelled with the task name",
 	)
 
-	viper.SetDefault("temporal_address", client.DefaultHostPort)
-	rootCmd.Flags().StringVarP(
-		&rootOpts.TemporalAddress, "temporal-address", "H",
-		viper.GetString("temporal_address"), "Address of the Temporal server",
+	rootCmd.Flags().BoolVar(
+		&rootOpts.DisableStateQuery, "disable-state-query",
+		viper.GetBool("disable_state_query"), "Disable the "+tasks.StateQuery+" query, which exposes the workflow data and output",
 	)
 
 	rootCmd.Flags().StringVar(
-		&rootOpts.TemporalAPIKey, "temporal-api-key",
-		viper.GetString("temporal_api_key"), "API key for Temporal authentication",
+		&rootOpts.MetricsPrefix, "metrics-prefix",
+		viper.GetString("metrics_prefix"), "Prefix for metrics",
 	)
-	// Hide the default value to avoid spaffing the API to command line
-	apiKey := rootCmd.Flags().Lookup("temporal-api-key")
-	if s := apiKey.Value; s.String() != "" {
-		apiKey.DefValue = "***"
-	}
 
 	rootCmd.Flags().StringVar(
-		&rootOpts.TemporalMTLSCertPath, "tls-client-cert-path",
-		viper.GetString("temporal_tls_client_cert_path"), "Path to mTLS client cert, usually ending in .pem",
+		&rootOpts.TaskQueue, "task-queue",
+		viper.GetString("task_queue"), "Task queue to poll. Defaults to the taskQueue document metadata, or the document name",
 	)
 
+	viper.SetDefault("unknown_metadata_keys", metadata.UnknownKeysWarn)
 	rootCmd.Flags().StringVar(
-		&rootOpts.TemporalMTLSKeyPath, "tls-client-key-path",
-		viper.GetString("temporal_tls_client_key_path"), "Path to mTLS client key, usually ending in .key",
-	)
-
-	viper.SetDefault("temporal_namespace", client.DefaultNamespace)
-	rootCmd.Flags().StringVarP(
-		&rootOpts.TemporalNamespace, "temporal-namespace", "n",
-		viper.GetString("temporal_namespace"), "Temporal namespace to use",
-	)
-
-	rootCmd.Flags().BoolVar(
-		&rootOpts.TemporalTLSEnabled, "temporal-tls",
-		viper.GetBool("temporal_tls"), "Enable TLS Temporal connection",
+		&rootOpts.UnknownMetadataKeys, "unknown-metadata-keys",
+		viper.GetString("unknown_metadata_keys"), "How to handle unknown metadata keys during validation - ignore, warn or error",
 	)
 
 	viper.SetDefault("validate", true)
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"go.temporal.io/sdk/client"
+)
+
+// configureSearchAttributes loads the search attributes registered in the
+// namespace so unregistered attributes can be skipped. If they can't be
+// loaded, nothing is skipped.
+func configureSearchAttributes(ctx context.Context, c client.Client) {
+	metadata.SetLenientSearchAttributes(rootOpts.LenientSearchAttributes)
+
+	keys, err := metadata.ListRegisteredSearchAttributes(ctx, c, rootOpts.TemporalNamespace)
+	if err != nil {
+		log.Warn().Err(err).Msg("Unable to load registered search attributes - unregistered attributes will not be skipped")
+		return
+	}
+
+	log.Debug().Strs("searchAttributes", keys).Msg("Loaded registered search attributes")
+	metadata.SetRegisteredSearchAttributes(keys)
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(
+		&rootOpts.LenientSearchAttributes, "lenient-search-attributes",
+		viper.GetBool("lenient_search_attributes"), "Skip search attributes not registered in the namespace, rather than failing the task",
+	)
+}
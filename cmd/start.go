@@ -0,0 +1,242 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	gh "github.com/mrsimonemms/golang-helpers"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.temporal.io/sdk/client"
+)
+
+var startOpts struct {
+	Detach           bool
+	ExecutionTimeout time.Duration
+	FilePath         string
+	Input            string
+	RunTimeout       time.Duration
+	Signal           string
+	SignalInput      string
+	TaskQueue        string
+	WorkflowID       string
+	Workflow         string
+}
+
+// startCmd represents the start command
+var startCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start a workflow and print the result",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := startWorkflowOptions()
+		if err != nil {
+			return err
+		}
+
+		if startOpts.Workflow == "" || opts.TaskQueue == "" {
+			return gh.FatalError{
+				Msg: "Workflow and task queue are required",
+			}
+		}
+
+		input, err := parseJSONFlag(startOpts.Input, "Input is not valid JSON")
+		if err != nil {
+			return err
+		}
+
+		signalInput, err := parseJSONFlag(startOpts.SignalInput, "Signal input is not valid JSON")
+		if err != nil {
+			return err
+		}
+
+		c, err := newTemporalClient()
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		ctx := context.Background()
+		we, err := startWorkflow(ctx, c, opts, input, signalInput)
+		if err != nil {
+			return gh.FatalError{
+				Cause: err,
+				Msg:   "Error executing workflow",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Str("workflow", startOpts.Workflow)
+				},
+			}
+		}
+
+		log.Info().Str("workflowId", we.GetID()).Str("runId", we.GetRunID()).Msg("Started workflow")
+
+		if startOpts.Detach {
+			return nil
+		}
+
+		var result any
+		if err := we.Get(ctx, &result); err != nil {
+			return gh.FatalError{
+				Cause: err,
+				Msg:   "Error getting response",
+				WithParams: func(l *zerolog.Event) *zerolog.Event {
+					return l.Str("workflowId", we.GetID()).Str("runId", we.GetRunID())
+				},
+			}
+		}
+
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	},
+}
+
+// startWorkflow starts the workflow. If a signal is given, it's sent as the
+// workflow is started, so it can't be lost if the workflow isn't yet listening.
+func startWorkflow(
+	ctx context.Context, c client.Client, opts client.StartWorkflowOptions, input, signalInput any,
+) (client.WorkflowRun, error) {
+	if startOpts.Signal == "" {
+		return c.ExecuteWorkflow(ctx, opts, startOpts.Workflow, input)
+	}
+
+	log.Debug().Str("signal", startOpts.Signal).Msg("Signalling with start")
+
+	return zigflow.SignalWithStart(ctx, c, opts, startOpts.Workflow, zigflow.Signal{
+		Name: startOpts.Signal,
+		Data: signalInput,
+	}, input)
+}
+
+// parseJSONFlag parses a flag's JSON value, or returns nil if it's not set
+func parseJSONFlag(value, msg string) (any, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, gh.FatalError{
+			Cause: err,
+			Msg:   msg,
+		}
+	}
+
+	return v, nil
+}
+
+// startWorkflowOptions builds the options from the flags. If a workflow file
+// is given, this fills in the workflow name, task queue and timeouts that
+// haven't been set by flags.
+func startWorkflowOptions() (client.StartWorkflowOptions, error) {
+	opts := client.StartWorkflowOptions{
+		ID:                       startOpts.WorkflowID,
+		TaskQueue:                startOpts.TaskQueue,
+		WorkflowExecutionTimeout: startOpts.ExecutionTimeout,
+		WorkflowRunTimeout:       startOpts.RunTimeout,
+	}
+
+	if startOpts.FilePath == "" {
+		return opts, nil
+	}
+
+	doc, err := zigflow.LoadFromFile(startOpts.FilePath)
+	if err != nil {
+		return opts, gh.FatalError{
+			Cause: err,
+			Msg:   "Unable to load workflow file",
+			WithParams: func(l *zerolog.Event) *zerolog.Event {
+				return l.Str("file", startOpts.FilePath)
+			},
+		}
+	}
+
+	if startOpts.Workflow == "" {
+		startOpts.Workflow = doc.Document.Name
+	}
+
+	opts, err = zigflow.StartWorkflowOptions(doc, opts)
+	if err != nil {
+		return opts, gh.FatalError{
+			Cause: err,
+			Msg:   "Unable to build workflow options",
+		}
+	}
+
+	return opts, nil
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd)
+
+	addConnectionFlags(startCmd.Flags())
+
+	startCmd.Flags().BoolVar(
+		&startOpts.Detach, "detach",
+		viper.GetBool("detach"), "Return once the workflow has started, without waiting for the result",
+	)
+
+	startCmd.Flags().DurationVar(
+		&startOpts.ExecutionTimeout, "execution-timeout",
+		viper.GetDuration("workflow_execution_timeout"), "Maximum time for all runs of the workflow. Overrides the document timeout",
+	)
+
+	startCmd.Flags().StringVarP(
+		&startOpts.FilePath, "file", "f",
+		viper.GetString("workflow_file"), "Path to workflow file, used for any unset workflow name, task queue and timeout",
+	)
+
+	startCmd.Flags().StringVar(
+		&startOpts.WorkflowID, "id",
+		viper.GetString("workflow_id"), "Workflow ID. Generated if not set",
+	)
+
+	startCmd.Flags().StringVarP(
+		&startOpts.Input, "input", "i",
+		viper.GetString("workflow_input"), "Workflow input as JSON",
+	)
+
+	startCmd.Flags().DurationVar(
+		&startOpts.RunTimeout, "run-timeout",
+		viper.GetDuration("workflow_run_timeout"), "Maximum time a single workflow run can run for",
+	)
+
+	startCmd.Flags().StringVar(
+		&startOpts.Signal, "signal",
+		viper.GetString("workflow_signal"), "Signal to send as the workflow starts. If the workflow is already running, it's only signalled",
+	)
+
+	startCmd.Flags().StringVar(
+		&startOpts.SignalInput, "signal-input",
+		viper.GetString("workflow_signal_input"), "Signal input as JSON",
+	)
+
+	startCmd.Flags().StringVarP(
+		&startOpts.TaskQueue, "task-queue", "q",
+		viper.GetString("task_queue"), "Task queue the worker is listening on. This is the workflow document's namespace",
+	)
+
+	startCmd.Flags().StringVarP(
+		&startOpts.Workflow, "workflow", "w",
+		viper.GetString("workflow_name"), "Name of the workflow to start",
+	)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	gh "github.com/mrsimonemms/golang-helpers"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/storage"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/tasks"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// Task results over 256KiB are offloaded when an output store is configured
+const defaultOutputOffloadSize = 256 * 1024
+
+// configureOutputStore enables offloading of large task outputs if a path to
+// the store is given
+func configureOutputStore() error {
+	if rootOpts.OutputStorePath == "" {
+		log.Debug().Msg("No output store configured")
+		return nil
+	}
+
+	store, err := storage.NewFileStore(rootOpts.OutputStorePath)
+	if err != nil {
+		return gh.FatalError{
+			Cause: err,
+			Msg:   "Unable to create output store",
+			WithParams: func(l *zerolog.Event) *zerolog.Event {
+				return l.Str("path", rootOpts.OutputStorePath)
+			},
+		}
+	}
+
+	log.Debug().
+		Str("path", rootOpts.OutputStorePath).
+		Int("threshold", rootOpts.OutputOffloadSize).
+		Msg("Offloading large task outputs")
+	tasks.SetOutputStore(store, rootOpts.OutputOffloadSize)
+
+	return nil
+}
+
+func init() {
+	viper.SetDefault("output_offload_size", defaultOutputOffloadSize)
+	rootCmd.Flags().IntVar(
+		&rootOpts.OutputOffloadSize, "output-offload-size",
+		viper.GetInt("output_offload_size"), "HTTP call, script and container results larger than this many bytes are offloaded to the output store",
+	)
+
+	rootCmd.Flags().StringVar(
+		&rootOpts.OutputStorePath, "output-store-path",
+		viper.GetString("output_store_path"), "Directory to offload large task outputs to. Must be shared between workers",
+	)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+
+	gh "github.com/mrsimonemms/golang-helpers"
+	"github.com/mrsimonemms/golang-helpers/temporal"
+	"github.com/mrsimonemms/zigflow/pkg/tracing"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"go.temporal.io/sdk/client"
+)
+
+// configureTracing exports task traces if an OpenTelemetry endpoint is given.
+// The returned function flushes any remaining spans.
+func configureTracing(ctx context.Context) (func(), error) {
+	if rootOpts.OTelEndpoint == "" {
+		log.Debug().Msg("No OpenTelemetry endpoint configured")
+		return func() {}, nil
+	}
+
+	shutdown, err := tracing.Setup(ctx, rootOpts.OTelEndpoint, "zigflow", Version)
+	if err != nil {
+		return nil, gh.FatalError{
+			Cause: err,
+			Msg:   "Unable to configure tracing",
+			WithParams: func(l *zerolog.Event) *zerolog.Event {
+				return l.Str("endpoint", rootOpts.OTelEndpoint)
+			},
+		}
+	}
+
+	log.Debug().Str("endpoint", rootOpts.OTelEndpoint).Msg("Exporting traces")
+
+	return func() {
+		// The parent context may already be cancelled
+		if err := shutdown(context.Background()); err != nil {
+			log.Error().Err(err).Msg("Error flushing traces")
+		}
+	}, nil
+}
+
+// withTracingPropagator passes the trace context between workflows and
+// activities
+func withTracingPropagator() temporal.Options {
+	return func(o *client.Options) error {
+		o.ContextPropagators = append(o.ContextPropagators, tracing.NewContextPropagator())
+		return nil
+	}
+}
+
+// withHeaderPropagator passes the configured Temporal headers from a workflow
+// to its child workflows and activities
+func withHeaderPropagator() temporal.Options {
+	return func(o *client.Options) error {
+		if len(rootOpts.ContextPropagationKeys) == 0 {
+			return nil
+		}
+
+		log.Debug().Strs("keys", rootOpts.ContextPropagationKeys).Msg("Propagating headers")
+		o.ContextPropagators = append(o.ContextPropagators, tracing.NewHeaderPropagator(rootOpts.ContextPropagationKeys...))
+		return nil
+	}
+}
+
+func init() {
+	rootCmd.Flags().StringSliceVar(
+		&rootOpts.ContextPropagationKeys, "context-propagation-key",
+		viper.GetStringSlice("context_propagation_key"), "Temporal header to pass to child workflows and activities. Can be set multiple times",
+	)
+
+	rootCmd.Flags().StringVar(
+		&rootOpts.OTelEndpoint, "otel-endpoint",
+		viper.GetString("otel_endpoint"), "OTLP HTTP endpoint to export task traces to, eg http://localhost:4318",
+	)
+}
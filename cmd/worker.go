@@ -0,0 +1,197 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	gh "github.com/mrsimonemms/golang-helpers"
+	"github.com/mrsimonemms/zigflow/pkg/health"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/tasks"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/worker"
+)
+
+const (
+	pollerBehaviorAutoscale = "autoscale"
+	pollerBehaviorFixed     = "fixed"
+)
+
+// workerOptions configures the worker. Any concurrency limits or poller counts
+// left at zero use the SDK defaults. The build ID is reported as the worker's
+// deployment version but versioning is not enabled, so workflows are not
+// pinned to it.
+func workerOptions(deploymentName string) (worker.Options, error) {
+	var pollers [3]worker.PollerBehavior
+	for i, count := range []int{rootOpts.WorkflowPollers, rootOpts.ActivityPollers, rootOpts.NexusPollers} {
+		behavior, err := pollerBehavior(rootOpts.PollerBehavior, count)
+		if err != nil {
+			return worker.Options{}, err
+		}
+		pollers[i] = behavior
+	}
+
+	opts := worker.Options{
+		WorkflowTaskPollerBehavior: pollers[0],
+		ActivityTaskPollerBehavior: pollers[1],
+		NexusTaskPollerBehavior:    pollers[2],
+
+		MaxConcurrentActivityExecutionSize:      rootOpts.MaxConcurrentActivities,
+		MaxConcurrentLocalActivityExecutionSize: rootOpts.MaxConcurrentLocalActivities,
+		MaxConcurrentWorkflowTaskExecutionSize:  rootOpts.MaxConcurrentWorkflowTasks,
+
+		// If empty, the SDK uses the client's identity
+		Identity: rootOpts.WorkerIdentity,
+
+		// On SIGTERM, the worker stops polling and gives in-flight tasks this
+		// long to finish. The worker is reported as not ready during this time.
+		WorkerStopTimeout: rootOpts.GracefulShutdownTimeout,
+
+		// Mask the secret data keys in the workflow logs
+		Interceptors: []interceptor.WorkerInterceptor{tasks.NewSecretsInterceptor()},
+	}
+
+	if rootOpts.BuildID != "" {
+		opts.DeploymentOptions = worker.DeploymentOptions{
+			Version: worker.WorkerDeploymentVersion{
+				DeploymentName: deploymentName,
+				BuildID:        rootOpts.BuildID,
+			},
+		}
+	}
+
+	return opts, nil
+}
+
+// pollerBehavior either scales the number of pollers up to the maximum, based
+// on feedback from the server, or always runs the maximum
+func pollerBehavior(behavior string, maximum int) (worker.PollerBehavior, error) {
+	if maximum < 0 {
+		return nil, gh.FatalError{
+			Msg: "Number of pollers must not be negative",
+			WithParams: func(l *zerolog.Event) *zerolog.Event {
+				return l.Int("pollers", maximum)
+			},
+		}
+	}
+
+	switch behavior {
+	case pollerBehaviorAutoscale:
+		return worker.NewPollerBehaviorAutoscaling(worker.PollerBehaviorAutoscalingOptions{
+			MaximumNumberOfPollers: maximum,
+		}), nil
+	case pollerBehaviorFixed:
+		return worker.NewPollerBehaviorSimpleMaximum(worker.PollerBehaviorSimpleMaximumOptions{
+			MaximumNumberOfPollers: maximum,
+		}), nil
+	default:
+		return nil, gh.FatalError{
+			Cause: fmt.Errorf("unknown poller behavior: %s", behavior),
+			Msg:   "Invalid poller behavior",
+		}
+	}
+}
+
+// runWorker starts the worker and blocks until it's interrupted or fails. The
+// health server is ready only while the worker is polling.
+func runWorker(temporalWorker worker.Worker, healthServer *health.Server, fatalErr <-chan error) error {
+	if err := temporalWorker.Start(); err != nil {
+		return gh.FatalError{
+			Cause: err,
+			Msg:   "Unable to start worker",
+		}
+	}
+	healthServer.SetReady(true)
+
+	var err error
+	select {
+	case sig := <-worker.InterruptCh():
+		log.Info().Interface("signal", sig).Msg("Stopping worker")
+	case err = <-fatalErr:
+		log.Error().Err(err).Msg("Worker failed")
+	}
+
+	// Stop receiving traffic before in-flight tasks are drained
+	healthServer.SetReady(false)
+	temporalWorker.Stop()
+
+	if err != nil {
+		return gh.FatalError{
+			Cause: err,
+			Msg:   "Worker stopped with an error",
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	viper.SetDefault("build_id", Version)
+	rootCmd.Flags().StringVar(
+		&rootOpts.BuildID, "build-id",
+		viper.GetString("build_id"), "Build ID reported by the worker. Defaults to the Zigflow version",
+	)
+
+	rootCmd.Flags().IntVar(
+		&rootOpts.ActivityPollers, "activity-pollers",
+		viper.GetInt("activity_pollers"), "Maximum activity task pollers. Uses the SDK default if unset",
+	)
+
+	rootCmd.Flags().DurationVar(
+		&rootOpts.GracefulShutdownTimeout, "graceful-shutdown-timeout",
+		viper.GetDuration("graceful_shutdown_timeout"), "Time to let in-flight tasks finish when the worker is stopped",
+	)
+
+	rootCmd.Flags().IntVar(
+		&rootOpts.MaxConcurrentActivities, "max-concurrent-activities",
+		viper.GetInt("max_concurrent_activities"), "Maximum concurrent activities. Uses the SDK default if unset",
+	)
+
+	rootCmd.Flags().IntVar(
+		&rootOpts.MaxConcurrentLocalActivities, "max-concurrent-local-activities",
+		viper.GetInt("max_concurrent_local_activities"), "Maximum concurrent local activities. Uses the SDK default if unset",
+	)
+
+	rootCmd.Flags().IntVar(
+		&rootOpts.MaxConcurrentWorkflowTasks, "max-concurrent-workflow-tasks",
+		viper.GetInt("max_concurrent_workflow_tasks"), "Maximum concurrent workflow tasks. Uses the SDK default if unset",
+	)
+
+	rootCmd.Flags().IntVar(
+		&rootOpts.NexusPollers, "nexus-pollers",
+		viper.GetInt("nexus_pollers"), "Maximum Nexus task pollers. Uses the SDK default if unset",
+	)
+
+	viper.SetDefault("poller_behavior", pollerBehaviorAutoscale)
+	rootCmd.Flags().StringVar(
+		&rootOpts.PollerBehavior, "poller-behavior",
+		viper.GetString("poller_behavior"), "How the number of pollers is set - autoscale up to the maximum, or fixed at the maximum",
+	)
+
+	rootCmd.Flags().IntVar(
+		&rootOpts.WorkflowPollers, "workflow-pollers",
+		viper.GetInt("workflow_pollers"), "Maximum workflow task pollers. Uses the SDK default if unset",
+	)
+
+	rootCmd.Flags().StringVar(
+		&rootOpts.WorkerIdentity, "worker-identity",
+		viper.GetString("worker_identity"), "Identity of the worker shown in Temporal. Uses the SDK default if unset",
+	)
+}
@@ -52,7 +52,7 @@ func exec(isApproved bool, delay time.Duration) error {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "authoriseChangeRequest",
 	}
 
 	ctx := context.Background()
@@ -101,7 +101,7 @@ func exec(isApproved bool, delay time.Duration) error {
 		time.Sleep(delay)
 
 		log.Info().Msg("Sending change review response")
-		workflowID := fmt.Sprintf("%s_fork_waitForApproval", we.GetID())
+		workflowID := fmt.Sprintf("%s_startReview_fork_waitForApproval", we.GetID())
 		if err := c.SignalWorkflow(ctx, workflowID, "", "review", map[string]any{
 			// Any data received here is set to the workflow's state
 			"approved": isApproved,
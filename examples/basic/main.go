@@ -41,7 +41,7 @@ func exec() error {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "basic",
 	}
 
 	ctx := context.Background()
@@ -36,7 +36,7 @@ func main() {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "childWorkflow",
 	}
 
 	ctx := context.Background()
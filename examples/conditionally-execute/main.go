@@ -42,7 +42,7 @@ func exec() error {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "conditional",
 	}
 
 	//nolint:gosec
@@ -41,7 +41,7 @@ func exec() error {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "workflow1",
 	}
 
 	// Map the workflow and the user ID
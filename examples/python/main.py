@@ -45,7 +45,7 @@ async def main() -> None:
         "basic-python",
         {"userId": 3},
         id=f"basic-{uuid.uuid4().hex}",
-        task_queue="zigflow",
+        task_queue="basic-python",
     )
 
     print(f"Started workflow: {handle.id}")
@@ -18,12 +18,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	gh "github.com/mrsimonemms/golang-helpers"
 	"github.com/mrsimonemms/golang-helpers/temporal"
 	"github.com/rs/zerolog/log"
 	"go.temporal.io/sdk/client"
+	sdktemporal "go.temporal.io/sdk/temporal"
 )
 
 func exec() error {
@@ -40,7 +42,7 @@ func exec() error {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "raise",
 	}
 
 	ctx := context.Background()
@@ -56,6 +58,15 @@ func exec() error {
 
 	var result map[string]any
 	if err := we.Get(ctx, &result); err != nil {
+		// The first detail is the Serverless Workflow error
+		var appErr *sdktemporal.ApplicationError
+		if errors.As(err, &appErr) {
+			var envelope map[string]any
+			if err := appErr.Details(&envelope); err == nil {
+				log.Info().Interface("error", envelope).Msg("Raised error")
+			}
+		}
+
 		return gh.FatalError{
 			Cause: err,
 			Msg:   "Demonstration error received",
@@ -71,7 +71,7 @@ func exec() error {
 	}
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "searchAttributes",
 	}
 
 	we, err := c.ExecuteWorkflow(ctx, workflowOptions, "searchAttributes", map[string]any{
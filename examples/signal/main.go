@@ -19,11 +19,11 @@ package main
 import (
 	"context"
 	"os"
-	"time"
 
 	"github.com/google/uuid"
 	gh "github.com/mrsimonemms/golang-helpers"
 	"github.com/mrsimonemms/golang-helpers/temporal"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
 	"github.com/rs/zerolog/log"
 	"go.temporal.io/sdk/client"
 )
@@ -48,11 +48,17 @@ func exec() error {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "signal",
 	}
 
 	ctx := context.Background()
-	we, err := c.ExecuteWorkflow(ctx, workflowOptions, "signal")
+
+	// The signal is sent as the workflow starts, so it's buffered until the
+	// workflow listens for it
+	we, err := zigflow.SignalWithStart(ctx, c, workflowOptions, "signal", zigflow.Signal{
+		Name: "approve",
+		Data: "some data",
+	})
 	if err != nil {
 		return gh.FatalError{
 			Cause: err,
@@ -62,16 +68,6 @@ func exec() error {
 
 	log.Info().Str("workflowId", we.GetID()).Str("runId", we.GetRunID()).Msg("Started workflow")
 
-	go func() {
-		// Change how long we wait before triggering the signal - times out at 10 seconds
-		time.Sleep(time.Second * 5)
-
-		if err := c.SignalWorkflow(ctx, we.GetID(), "", "approve", "some data"); err != nil {
-			// Fatal error in goroutine
-			log.Fatal().Err(err).Msg("Error signalling workflow")
-		}
-	}()
-
 	var res any
 	if err := we.Get(ctx, &res); err != nil {
 		return gh.FatalError{
@@ -40,7 +40,7 @@ func exec() error {
 	defer c.Close()
 
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: "zigflow",
+		TaskQueue: "switch",
 	}
 
 	ctx := context.Background()
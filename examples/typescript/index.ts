@@ -30,7 +30,7 @@ async function bootstrap() {
   });
 
   const handle = await client.workflow.start('basic-typescript', {
-    taskQueue: 'zigflow',
+    taskQueue: 'basic-typescript',
     workflowId: `basic-${nanoid()}`,
     args: [
       {
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import (
+	"fmt"
+
+	"github.com/mrsimonemms/temporal-codec-server/packages/golang/algorithms/aes"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseAESKeys parses the AES key file, checking each key is the length of an
+// AES-128, AES-192 or AES-256 key to catch key files for other algorithms
+func ParseAESKeys(data []byte) (aes.Keys, error) {
+	var keys aes.Keys
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("error unmarshalling key file: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one key is required")
+	}
+
+	for _, k := range keys {
+		switch len(k.Key) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("key '%s' is not a valid aes key", k.ID)
+		}
+	}
+
+	return keys, nil
+}
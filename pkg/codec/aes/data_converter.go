@@ -0,0 +1,159 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	keys "github.com/mrsimonemms/temporal-codec-server/packages/golang/algorithms/aes"
+	"github.com/mrsimonemms/zigflow/pkg/codec"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// The encoding and key ID metadata match the codec server, so payloads can
+// be decoded in the Temporal UI
+const (
+	AESMimeType   = keys.AESMimeType
+	MetadataKeyID = keys.MetadataKeyID
+)
+
+// Each payload is encrypted with AES-GCM using the first key. Any key can be
+// used to decrypt, which allows keys to be rotated.
+type aesCodec struct {
+	keys keys.Keys
+	ids  []string
+}
+
+// Decode implements converter.PayloadCodec.
+func (c *aesCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Only if it's our encoding
+		if string(p.Metadata[converter.MetadataEncoding]) != AESMimeType {
+			result[i] = p
+			continue
+		}
+
+		keyID := string(p.Metadata[MetadataKeyID])
+		plaintext, err := codec.Decrypt(keyID, c.ids, func(k int) ([]byte, error) {
+			return c.decrypt(keyID, c.keys[k], p.Data)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal proto
+		result[i] = &commonpb.Payload{}
+		if err := result[i].Unmarshal(plaintext); err != nil {
+			return nil, codec.NewDecodeError(keyID, codec.ErrCorruptPayload, fmt.Errorf("error unmarshalling payload: %w", err))
+		}
+	}
+
+	return result, nil
+}
+
+func (c *aesCodec) decrypt(keyID string, key keys.Key, data []byte) ([]byte, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, codec.NewDecodeError(keyID, codec.ErrCorruptPayload, fmt.Errorf("encrypted payload too short"))
+	}
+
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting payload: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Encode implements converter.PayloadCodec.
+func (c *aesCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	// Use the first key to encrypt
+	key := c.keys[0]
+
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Marshal proto
+		origBytes, err := p.Marshal()
+		if err != nil {
+			return payloads, err
+		}
+
+		gcm, err := newCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, fmt.Errorf("error reading random nonce: %w", err)
+		}
+
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				converter.MetadataEncoding: []byte(AESMimeType),
+				MetadataKeyID:              []byte(key.ID),
+			},
+			Data: gcm.Seal(nonce, nonce, origBytes, nil),
+		}
+	}
+
+	return result, nil
+}
+
+func newCipher(key keys.Key) (cipher.AEAD, error) {
+	a, err := aes.NewCipher([]byte(key.Key))
+	if err != nil {
+		return nil, fmt.Errorf("error creating aes cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(a)
+	if err != nil {
+		return nil, fmt.Errorf("error creating galois counter mode: %w", err)
+	}
+
+	return gcm, nil
+}
+
+func DataConverter(k keys.Keys) converter.DataConverter {
+	return NewDataConverter(converter.GetDefaultDataConverter(), k)
+}
+
+func NewPayloadCodec(k keys.Keys) converter.PayloadCodec {
+	ids := make([]string, 0, len(k))
+	for _, key := range k {
+		ids = append(ids, key.ID)
+	}
+
+	return &aesCodec{keys: k, ids: ids}
+}
+
+// NewDataConverter creates a new data converter that wraps the converter
+func NewDataConverter(underlying converter.DataConverter, k keys.Keys) converter.DataConverter {
+	return converter.NewCodecDataConverter(underlying, NewPayloadCodec(k))
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aes_test
+
+import (
+	"testing"
+
+	keys "github.com/mrsimonemms/temporal-codec-server/packages/golang/algorithms/aes"
+	"github.com/mrsimonemms/zigflow/pkg/codec"
+	"github.com/mrsimonemms/zigflow/pkg/codec/aes"
+	"github.com/stretchr/testify/assert"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+var (
+	current  = keys.Key{ID: "current", Key: "currentkeywhichneedstobe32bytes!"}
+	previous = keys.Key{ID: "previous", Key: "previouskeywhichneeds32bytes!!!!"}
+)
+
+func TestDataConverter(t *testing.T) {
+	input := map[string]any{
+		"hello": "world",
+	}
+
+	// Encrypted with the previous key before it was rotated
+	oldPayload, err := aes.DataConverter(keys.Keys{previous}).ToPayload(input)
+	assert.NoError(t, err)
+
+	dc := aes.DataConverter(keys.Keys{current, previous})
+
+	payload, err := dc.ToPayload(input)
+	assert.NoError(t, err)
+	assert.Equal(t, aes.AESMimeType, string(payload.Metadata[converter.MetadataEncoding]))
+	assert.Equal(t, "current", string(payload.Metadata[aes.MetadataKeyID]))
+	assert.NotContains(t, string(payload.Data), "world")
+
+	// Compatible with the codec server
+	var output map[string]any
+	assert.NoError(t, keys.DataConverter(keys.Keys{current}).FromPayload(payload, &output))
+	assert.Equal(t, input, output)
+
+	tamper := func(p *commonpb.Payload) *commonpb.Payload {
+		data := append([]byte{}, p.Data...)
+		data[len(data)-1] ^= 0xff
+		return &commonpb.Payload{Metadata: p.Metadata, Data: data}
+	}
+
+	tests := []struct {
+		Name    string
+		Keys    keys.Keys
+		Payload *commonpb.Payload
+		Error   error
+	}{
+		{
+			Name:    "Current key",
+			Keys:    keys.Keys{current, previous},
+			Payload: payload,
+		},
+		{
+			Name:    "Rotated key",
+			Keys:    keys.Keys{current, previous},
+			Payload: oldPayload,
+		},
+		{
+			Name: "Rotated key with a new ID",
+			Keys: keys.Keys{
+				current,
+				{ID: "renamed", Key: previous.Key},
+			},
+			Payload: oldPayload,
+		},
+		{
+			Name:    "Key removed after rotation",
+			Keys:    keys.Keys{current},
+			Payload: oldPayload,
+			Error:   codec.ErrUnknownKey,
+		},
+		{
+			Name: "Key ID reused for a new key",
+			Keys: keys.Keys{
+				{ID: "previous", Key: current.Key},
+			},
+			Payload: oldPayload,
+			Error:   codec.ErrCorruptPayload,
+		},
+		{
+			Name:    "Tampered payload",
+			Keys:    keys.Keys{current, previous},
+			Payload: tamper(payload),
+			Error:   codec.ErrCorruptPayload,
+		},
+		{
+			Name: "Truncated payload",
+			Keys: keys.Keys{current},
+			Payload: &commonpb.Payload{
+				Metadata: payload.Metadata,
+				Data:     payload.Data[:4],
+			},
+			Error: codec.ErrCorruptPayload,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var output map[string]any
+			err := aes.DataConverter(test.Keys).FromPayload(test.Payload, &output)
+
+			if test.Error != nil {
+				assert.ErrorIs(t, err, test.Error)
+
+				var decodeErr *codec.DecodeError
+				assert.ErrorAs(t, err, &decodeErr)
+				assert.Equal(t, string(test.Payload.Metadata[aes.MetadataKeyID]), decodeErr.KeyID)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, input, output)
+		})
+	}
+}
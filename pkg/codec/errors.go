@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrUnknownKey means that none of the keys can decrypt the payload and
+	// the key it was encrypted with isn't in the key file. This is usually
+	// because the key was removed from the key file too soon after rotation.
+	ErrUnknownKey = errors.New("unknown encryption key")
+
+	// ErrCorruptPayload means that the payload is malformed or that the key
+	// it was encrypted with fails to decrypt it. A key ID that's been reused
+	// for a different key looks like a corrupt payload.
+	ErrCorruptPayload = errors.New("corrupt payload")
+)
+
+// DecodeError is returned when a payload can't be decrypted. Use errors.Is
+// with ErrUnknownKey or ErrCorruptPayload to find out why.
+type DecodeError struct {
+	// ID of the key the payload was encrypted with
+	KeyID string
+
+	reason error
+	cause  error
+}
+
+func (e *DecodeError) Error() string {
+	msg := fmt.Sprintf("error decoding payload encrypted with key '%s': %s", e.KeyID, e.reason)
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+func (e *DecodeError) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.reason}
+	}
+	return []error{e.reason, e.cause}
+}
+
+// NewDecodeError creates a DecodeError, where the reason is either
+// ErrUnknownKey or ErrCorruptPayload
+func NewDecodeError(keyID string, reason, cause error) *DecodeError {
+	return &DecodeError{
+		KeyID:  keyID,
+		reason: reason,
+		cause:  cause,
+	}
+}
+
+// Decrypt decrypts the payload encrypted with the key ID. The key with that
+// ID is tried first, followed by the other keys in order, so a payload can
+// still be decrypted if its key has been given a new ID during rotation. An
+// error that's already a DecodeError, such as a payload that's too short, is
+// returned without trying the other keys.
+func Decrypt(keyID string, ids []string, decrypt func(i int) ([]byte, error)) ([]byte, error) {
+	if keyID == "" {
+		return nil, NewDecodeError(keyID, ErrCorruptPayload, errors.New("no key id provided"))
+	}
+
+	order := make([]int, 0, len(ids))
+	known := slices.Index(ids, keyID)
+	if known >= 0 {
+		order = append(order, known)
+	}
+	for i := range ids {
+		if i != known {
+			order = append(order, i)
+		}
+	}
+
+	var keyErr error
+	for _, i := range order {
+		plaintext, err := decrypt(i)
+		if err == nil {
+			return plaintext, nil
+		}
+
+		if decodeErr := (*DecodeError)(nil); errors.As(err, &decodeErr) {
+			return nil, err
+		}
+		if i == known {
+			keyErr = err
+		}
+	}
+
+	if known < 0 {
+		return nil, NewDecodeError(keyID, ErrUnknownKey, nil)
+	}
+
+	return nil, NewDecodeError(keyID, ErrCorruptPayload, keyErr)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// loggingDataConverter logs the payloads that can't be decrypted with the
+// workflow they belong to, when it's known, and the key they need
+type loggingDataConverter struct {
+	converter.DataConverter
+
+	workflowID string
+	runID      string
+}
+
+// NewLoggingDataConverter wraps the data converter to log any DecodeError
+func NewLoggingDataConverter(dc converter.DataConverter) converter.DataConverter {
+	return &loggingDataConverter{DataConverter: dc}
+}
+
+// WithWorkflowContext implements the SDK's ContextAware interface
+func (c *loggingDataConverter) WithWorkflowContext(ctx workflow.Context) converter.DataConverter {
+	execution := workflow.GetInfo(ctx).WorkflowExecution
+
+	return &loggingDataConverter{
+		DataConverter: c.DataConverter,
+		workflowID:    execution.ID,
+		runID:         execution.RunID,
+	}
+}
+
+// WithContext implements the SDK's ContextAware interface. The workflow is
+// only known in an activity.
+func (c *loggingDataConverter) WithContext(ctx context.Context) converter.DataConverter {
+	if !activity.IsActivity(ctx) {
+		return c
+	}
+
+	execution := activity.GetInfo(ctx).WorkflowExecution
+
+	return &loggingDataConverter{
+		DataConverter: c.DataConverter,
+		workflowID:    execution.ID,
+		runID:         execution.RunID,
+	}
+}
+
+func (c *loggingDataConverter) FromPayload(payload *commonpb.Payload, valuePtr any) error {
+	return c.logDecodeError(c.DataConverter.FromPayload(payload, valuePtr))
+}
+
+func (c *loggingDataConverter) FromPayloads(payloads *commonpb.Payloads, valuePtrs ...any) error {
+	return c.logDecodeError(c.DataConverter.FromPayloads(payloads, valuePtrs...))
+}
+
+func (c *loggingDataConverter) logDecodeError(err error) error {
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		return err
+	}
+
+	reason := "corrupt payload"
+	if errors.Is(err, ErrUnknownKey) {
+		reason = "unknown key"
+	}
+
+	log.Error().
+		Err(err).
+		Str("workflowId", c.workflowID).
+		Str("runId", c.runID).
+		Str("keyId", decodeErr.KeyID).
+		Str("reason", reason).
+		Msg("Unable to decrypt payload")
+
+	return err
+}
+
+// Ensure the SDK passes the workflow or activity context
+var _ workflow.ContextAware = &loggingDataConverter{}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	keys "github.com/mrsimonemms/temporal-codec-server/packages/golang/algorithms/aes"
+	"github.com/mrsimonemms/zigflow/pkg/codec"
+	"github.com/mrsimonemms/zigflow/pkg/codec/aes"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestLoggingDataConverter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() {
+		log.Logger = logger
+	}()
+
+	oldKey := keys.Key{ID: "old", Key: "passphrasewhichneedstobe32bytes!"}
+	newKey := keys.Key{ID: "new", Key: "anotherpassphraseof32bytes!!!!!!"}
+
+	payload, err := aes.DataConverter(keys.Keys{oldKey}).ToPayload("hello")
+	assert.NoError(t, err)
+
+	dc := codec.NewLoggingDataConverter(aes.DataConverter(keys.Keys{newKey}))
+	dc = dc.(workflow.ContextAware).WithContext(context.Background())
+
+	var output string
+	err = dc.FromPayload(payload, &output)
+	assert.ErrorIs(t, err, codec.ErrUnknownKey)
+
+	assert.Contains(t, buf.String(), `"keyId":"old"`)
+	assert.Contains(t, buf.String(), `"reason":"unknown key"`)
+	assert.Contains(t, buf.String(), `"message":"Unable to decrypt payload"`)
+
+	// Other errors aren't logged
+	buf.Reset()
+	var number int
+	assert.Error(t, codec.NewLoggingDataConverter(converter.GetDefaultDataConverter()).FromPayload(payload, &number))
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rsa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/mrsimonemms/zigflow/pkg/codec"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+const (
+	RSAMimeType   = "binary/encrypted-rsa"
+	MetadataKeyID = "encryption-key-id"
+
+	// Size of the AES key generated for each payload
+	dataKeySize = 32
+)
+
+// The payload is too large to encrypt with RSA directly, so each payload is
+// encrypted with a random AES-GCM key. That key is encrypted with RSA-OAEP and
+// prepended to the data.
+type rsaCodec struct {
+	keys Keys
+}
+
+// Decode implements converter.PayloadCodec.
+func (c *rsaCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Only if it's our encoding
+		if string(p.Metadata[converter.MetadataEncoding]) != RSAMimeType {
+			result[i] = p
+			continue
+		}
+
+		keyID := string(p.Metadata[MetadataKeyID])
+		plaintext, err := codec.Decrypt(keyID, c.ids(), func(k int) ([]byte, error) {
+			return decrypt(c.keys[k], p.Data)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Unmarshal proto
+		result[i] = &commonpb.Payload{}
+		if err := result[i].Unmarshal(plaintext); err != nil {
+			return nil, codec.NewDecodeError(keyID, codec.ErrCorruptPayload, fmt.Errorf("error unmarshalling payload: %w", err))
+		}
+	}
+
+	return result, nil
+}
+
+// Encode implements converter.PayloadCodec.
+func (c *rsaCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	// Use the first key to encrypt
+	key := c.keys[0]
+
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Marshal proto
+		origBytes, err := p.Marshal()
+		if err != nil {
+			return payloads, err
+		}
+
+		dataKey := make([]byte, dataKeySize)
+		if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+			return nil, fmt.Errorf("error generating data key: %w", err)
+		}
+
+		encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, &key.PrivateKey.PublicKey, dataKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting data key: %w", err)
+		}
+
+		gcm, err := newCipher(dataKey)
+		if err != nil {
+			return nil, err
+		}
+
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, fmt.Errorf("error reading random nonce: %w", err)
+		}
+
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				converter.MetadataEncoding: []byte(RSAMimeType),
+				MetadataKeyID:              []byte(key.ID),
+			},
+			Data: gcm.Seal(append(encryptedKey, nonce...), nonce, origBytes, nil),
+		}
+	}
+
+	return result, nil
+}
+
+// decrypt decrypts the data key with the RSA key, then the payload with the
+// data key
+func decrypt(key Key, data []byte) ([]byte, error) {
+	size := key.PrivateKey.Size()
+	if len(data) < size {
+		return nil, fmt.Errorf("encrypted payload too short")
+	}
+
+	dataKey, err := rsa.DecryptOAEP(sha256.New(), nil, key.PrivateKey, data[:size], nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting data key: %w", err)
+	}
+
+	gcm, err := newCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := data[size:]
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("encrypted payload too short")
+	}
+
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting payload: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func (c *rsaCodec) ids() []string {
+	ids := make([]string, 0, len(c.keys))
+	for _, k := range c.keys {
+		ids = append(ids, k.ID)
+	}
+	return ids
+}
+
+func newCipher(key []byte) (cipher.AEAD, error) {
+	a, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating aes cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(a)
+	if err != nil {
+		return nil, fmt.Errorf("error creating galois counter mode: %w", err)
+	}
+
+	return gcm, nil
+}
+
+func DataConverter(keys Keys) converter.DataConverter {
+	return NewDataConverter(converter.GetDefaultDataConverter(), keys)
+}
+
+func NewPayloadCodec(keys Keys) converter.PayloadCodec {
+	return &rsaCodec{keys: keys}
+}
+
+// NewDataConverter creates a new data converter that wraps the converter
+func NewDataConverter(underlying converter.DataConverter, keys Keys) converter.DataConverter {
+	return converter.NewCodecDataConverter(underlying, NewPayloadCodec(keys))
+}
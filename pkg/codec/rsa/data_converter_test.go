@@ -0,0 +1,146 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rsa_test
+
+import (
+	"crypto/rand"
+	gorsa "crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/codec"
+	"github.com/mrsimonemms/zigflow/pkg/codec/rsa"
+	"github.com/stretchr/testify/assert"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"sigs.k8s.io/yaml"
+)
+
+func newKey(t *testing.T) *gorsa.PrivateKey {
+	t.Helper()
+
+	key, err := gorsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	return key
+}
+
+func writeKeyFile(t *testing.T, keys []map[string]string) string {
+	t.Helper()
+
+	data, err := yaml.Marshal(keys)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func TestReadKeyFile(t *testing.T) {
+	key := newKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		Name  string
+		Keys  []map[string]string
+		Error string
+	}{
+		{
+			Name: "PKCS #1 and PKCS #8 keys",
+			Keys: []map[string]string{
+				{
+					"id":  "pkcs1",
+					"key": string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+				},
+				{
+					"id":  "pkcs8",
+					"key": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+				},
+			},
+		},
+		{
+			Name:  "No keys",
+			Keys:  []map[string]string{},
+			Error: "at least one key is required",
+		},
+		{
+			Name: "AES key",
+			Keys: []map[string]string{
+				{
+					"id":  "aes",
+					"key": "passphrasewhichneedstobe32bytes!",
+				},
+			},
+			Error: "error parsing key 'aes': not a pem-encoded rsa private key",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			keys, err := rsa.ReadKeyFile(writeKeyFile(t, test.Keys))
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, keys, len(test.Keys))
+			for _, k := range keys {
+				assert.True(t, key.Equal(k.PrivateKey))
+			}
+		})
+	}
+}
+
+func TestDataConverter(t *testing.T) {
+	current := rsa.Key{ID: "current", PrivateKey: newKey(t)}
+	previous := rsa.Key{ID: "previous", PrivateKey: newKey(t)}
+
+	input := map[string]any{
+		"hello": "world",
+	}
+
+	// Encrypted with the previous key before it was rotated
+	oldPayload, err := rsa.DataConverter(rsa.Keys{previous}).ToPayload(input)
+	assert.NoError(t, err)
+	assert.Equal(t, rsa.RSAMimeType, string(oldPayload.Metadata[converter.MetadataEncoding]))
+	assert.Equal(t, "previous", string(oldPayload.Metadata[rsa.MetadataKeyID]))
+
+	dc := rsa.DataConverter(rsa.Keys{current, previous})
+
+	payload, err := dc.ToPayload(input)
+	assert.NoError(t, err)
+	assert.Equal(t, "current", string(payload.Metadata[rsa.MetadataKeyID]))
+	assert.NotContains(t, string(payload.Data), "world")
+
+	for _, p := range []*commonpb.Payload{payload, oldPayload} {
+		var output map[string]any
+		assert.NoError(t, dc.FromPayload(p, &output))
+		assert.Equal(t, input, output)
+	}
+
+	// Unknown keys can't decrypt the payload
+	var output map[string]any
+	err = rsa.DataConverter(rsa.Keys{previous}).FromPayload(payload, &output)
+	assert.ErrorIs(t, err, codec.ErrUnknownKey)
+	assert.ErrorContains(t, err, "key 'current'")
+}
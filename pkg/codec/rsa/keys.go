@@ -0,0 +1,109 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rsa
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Keys are the RSA keys in the key file. The first key is used to encrypt and
+// all keys can be used to decrypt, which allows keys to be rotated.
+type Keys []Key
+
+type Key struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+}
+
+// keyFile is the format of the key file, which matches the AES key file with
+// the key as a PEM-encoded private key
+type keyFile []struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// ReadKeyFile loads the RSA keys from disk
+func ReadKeyFile(filepath string) (Keys, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return ParseKeys(data)
+}
+
+// ParseKeys parses the RSA key file, erroring if any key is not a PEM-encoded
+// RSA private key in either PKCS #1 or PKCS #8 form
+func ParseKeys(data []byte) (Keys, error) {
+	var file keyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error unmarshalling key file: %w", err)
+	}
+
+	if len(file) == 0 {
+		return nil, fmt.Errorf("at least one key is required")
+	}
+
+	keys := make(Keys, 0, len(file))
+	for _, k := range file {
+		if k.ID == "" {
+			return nil, fmt.Errorf("key id is required")
+		}
+
+		privateKey, err := ParsePrivateKey([]byte(k.Key))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing key '%s': %w", k.ID, err)
+		}
+
+		keys = append(keys, Key{
+			ID:         k.ID,
+			PrivateKey: privateKey,
+		})
+	}
+
+	return keys, nil
+}
+
+// ParsePrivateKey decodes a PEM-encoded RSA private key
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("not a pem-encoded rsa private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("not a pem-encoded rsa private key: %w", err)
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an rsa private key: %T", key)
+	}
+
+	return privateKey, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySource loads the raw key file used by the data converter. This allows
+// keys to be loaded from somewhere other than disk, such as a secret manager.
+type KeySource interface {
+	// Load returns the contents of the key file
+	Load(ctx context.Context) ([]byte, error)
+	// String describes where the keys are loaded from, for logging
+	String() string
+}
+
+type fileKeySource struct {
+	path string
+}
+
+// NewFileKeySource loads the keys from a file on disk
+func NewFileKeySource(path string) KeySource {
+	return &fileKeySource{path: path}
+}
+
+func (s *fileKeySource) Load(context.Context) ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return data, nil
+}
+
+func (s *fileKeySource) String() string {
+	return "file:" + s.path
+}
+
+type envKeySource struct {
+	name string
+}
+
+// NewEnvKeySource loads the keys from a base64-encoded environment variable,
+// avoiding the need to mount a key file
+func NewEnvKeySource(name string) KeySource {
+	return &envKeySource{name: name}
+}
+
+func (s *envKeySource) Load(context.Context) ([]byte, error) {
+	value, ok := os.LookupEnv(s.name)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("environment variable %s not set", s.name)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding environment variable %s: %w", s.name, err)
+	}
+
+	return data, nil
+}
+
+func (s *envKeySource) String() string {
+	return "env:" + s.name
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+)
+
+// Server exposes the liveness and readiness of the worker
+//
+//   - /livez is OK while the process is running
+//   - /readyz is OK once the worker is polling and Temporal is reachable. This
+//     becomes unavailable as soon as the worker starts to shut down
+//   - /health is kept for backwards compatibility and only checks Temporal
+//   - /info describes the workflow the worker serves
+type Server struct {
+	client    client.Client
+	taskQueue string
+	ready     atomic.Bool
+	info      atomic.Pointer[Info]
+}
+
+// Info describes the workflow the worker serves
+type Info struct {
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace"`
+	Version     string   `json:"version"`
+	DSL         string   `json:"dsl"`
+	Description string   `json:"description,omitempty"`
+	TaskQueue   string   `json:"taskQueue"`
+	Workflows   []string `json:"workflows"`
+}
+
+func New(taskQueue string, c client.Client) *Server {
+	return &Server{
+		client:    c,
+		taskQueue: taskQueue,
+	}
+}
+
+// SetReady marks whether the worker is polling the task queue
+func (s *Server) SetReady(ready bool) {
+	log.Debug().Bool("ready", ready).Msg("Setting worker readiness")
+	s.ready.Store(ready)
+}
+
+// SetInfo sets the workflow the worker serves, once it's been built
+func (s *Server) SetInfo(info Info) {
+	s.info.Store(&info)
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, true)
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !s.ready.Load() {
+			log.Debug().Msg("Worker not ready")
+			respond(w, false)
+			return
+		}
+
+		respond(w, s.isConnected(r.Context()))
+	})
+
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, s.isConnected(r.Context()))
+	})
+
+	mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		info := s.info.Load()
+		if info == nil {
+			log.Debug().Msg("Workflow not built")
+			respond(w, false)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(info); err != nil {
+			log.Error().Err(err).Msg("Error writing workflow info")
+		}
+	})
+
+	return mux
+}
+
+// ListenAndServe starts the server in the background. It's stopped when the
+// context is cancelled. If the server fails, the error is sent to fatalErr.
+func (s *Server) ListenAndServe(ctx context.Context, address string, fatalErr chan<- error) {
+	srv := &http.Server{
+		Addr:         address,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		Handler:      s.Handler(),
+	}
+
+	go func() {
+		log.Info().Str("address", address).Msg("Starting healthcheck service")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatalErr <- fmt.Errorf("error serving health check connection: %w", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+
+		log.Debug().Msg("Stopping healthcheck service")
+		if err := srv.Close(); err != nil {
+			log.Error().Err(err).Msg("Error stopping healthcheck service")
+		}
+	}()
+}
+
+func (s *Server) isConnected(ctx context.Context) bool {
+	if _, err := s.client.DescribeTaskQueue(ctx, s.taskQueue, enums.TASK_QUEUE_TYPE_ACTIVITY); err != nil {
+		log.Error().Err(err).Msg("Temporal connection unhealthy")
+		return false
+	}
+
+	log.Debug().Msg("Temporal connection healthy")
+	return true
+}
+
+func respond(w http.ResponseWriter, ok bool) {
+	statusCode := http.StatusOK
+	msg := "OK"
+	if !ok {
+		statusCode = http.StatusServiceUnavailable
+		msg = "Down"
+	}
+
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(msg))
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/mocks"
+)
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Path         string
+		Ready        bool
+		ConnectError error
+		StatusCode   int
+	}{
+		{
+			Name:       "live when not ready",
+			Path:       "/livez",
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:         "live when disconnected",
+			Path:         "/livez",
+			Ready:        true,
+			ConnectError: errors.New("unavailable"),
+			StatusCode:   http.StatusOK,
+		},
+		{
+			Name:       "not ready before polling",
+			Path:       "/readyz",
+			StatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			Name:       "ready when polling",
+			Path:       "/readyz",
+			Ready:      true,
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:         "not ready when disconnected",
+			Path:         "/readyz",
+			Ready:        true,
+			ConnectError: errors.New("unavailable"),
+			StatusCode:   http.StatusServiceUnavailable,
+		},
+		{
+			Name:       "health only checks the connection",
+			Path:       "/health",
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:         "health when disconnected",
+			Path:         "/health",
+			Ready:        true,
+			ConnectError: errors.New("unavailable"),
+			StatusCode:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			c := &mocks.Client{}
+			c.On("DescribeTaskQueue", mock.Anything, "queue", enums.TASK_QUEUE_TYPE_ACTIVITY).
+				Return(&workflowservice.DescribeTaskQueueResponse{}, test.ConnectError)
+
+			s := health.New("queue", c)
+			s.SetReady(test.Ready)
+
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.Path, nil))
+
+			assert.Equal(t, test.StatusCode, rec.Code)
+		})
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	s := health.New("queue", &mocks.Client{})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	s.SetInfo(health.Info{
+		Name:        "example",
+		Namespace:   "zigflow",
+		Version:     "0.0.1",
+		DSL:         "1.0.0",
+		Description: "An example workflow",
+		TaskQueue:   "queue",
+		Workflows:   []string{"main", "other"},
+	})
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"name": "example",
+		"namespace": "zigflow",
+		"version": "0.0.1",
+		"dsl": "1.0.0",
+		"description": "An example workflow",
+		"taskQueue": "queue",
+		"workflows": ["main", "other"]
+	}`, rec.Body.String())
+}
+
+func TestServerListenError(t *testing.T) {
+	// Take the address so the server can't listen on it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fatalErr := make(chan error, 1)
+	health.New("queue", &mocks.Client{}).ListenAndServe(ctx, l.Addr().String(), fatalErr)
+
+	select {
+	case err := <-fatalErr:
+		assert.ErrorContains(t, err, "error serving health check connection")
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the listen error to be sent")
+	}
+}
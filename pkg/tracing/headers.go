@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+type headersContextKey struct{}
+
+// The headers are stored as the raw payloads so the values don't need to be
+// decoded to be propagated
+type headers map[string]*commonpb.Payload
+
+type headerPropagator struct {
+	keys []string
+}
+
+// NewHeaderPropagator passes the Temporal headers with the given keys from a
+// workflow to its child workflows and activities. This allows values set by
+// the caller, such as a tenant or correlation ID, to follow the execution.
+func NewHeaderPropagator(keys ...string) workflow.ContextPropagator {
+	return &headerPropagator{keys: keys}
+}
+
+func (p *headerPropagator) Inject(ctx context.Context, writer workflow.HeaderWriter) error {
+	h, _ := ctx.Value(headersContextKey{}).(headers)
+	p.write(writer, h)
+
+	return nil
+}
+
+func (p *headerPropagator) InjectFromWorkflow(ctx workflow.Context, writer workflow.HeaderWriter) error {
+	h, _ := ctx.Value(headersContextKey{}).(headers)
+	p.write(writer, h)
+
+	return nil
+}
+
+func (p *headerPropagator) Extract(ctx context.Context, reader workflow.HeaderReader) (context.Context, error) {
+	h := p.read(reader)
+	if len(h) == 0 {
+		return ctx, nil
+	}
+
+	return context.WithValue(ctx, headersContextKey{}, h), nil
+}
+
+func (p *headerPropagator) ExtractToWorkflow(ctx workflow.Context, reader workflow.HeaderReader) (workflow.Context, error) {
+	h := p.read(reader)
+	if len(h) == 0 {
+		return ctx, nil
+	}
+
+	return workflow.WithValue(ctx, headersContextKey{}, h), nil
+}
+
+func (p *headerPropagator) read(reader workflow.HeaderReader) headers {
+	h := headers{}
+	for _, key := range p.keys {
+		if payload, ok := reader.Get(key); ok {
+			h[key] = payload
+		}
+	}
+
+	return h
+}
+
+func (p *headerPropagator) write(writer workflow.HeaderWriter, h headers) {
+	for _, key := range p.keys {
+		if payload, ok := h[key]; ok {
+			writer.Set(key, payload)
+		}
+	}
+}
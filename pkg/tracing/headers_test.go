@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestHeaderPropagator(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Keys     []string
+		Headers  map[string]string
+		Expected map[string]string
+	}{
+		{
+			Name: "Configured keys",
+			Keys: []string{"tenant-id", "correlation-id"},
+			Headers: map[string]string{
+				"tenant-id":      "tenant1",
+				"correlation-id": "abc123",
+				"other":          "ignored",
+			},
+			Expected: map[string]string{
+				"tenant-id":      "tenant1",
+				"correlation-id": "abc123",
+			},
+		},
+		{
+			Name: "Missing keys",
+			Keys: []string{"tenant-id", "correlation-id"},
+			Headers: map[string]string{
+				"tenant-id": "tenant1",
+			},
+			Expected: map[string]string{
+				"tenant-id": "tenant1",
+			},
+		},
+		{
+			Name: "No keys",
+			Headers: map[string]string{
+				"tenant-id": "tenant1",
+			},
+			Expected: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			p := tracing.NewHeaderPropagator(test.Keys...)
+
+			in := header{}
+			for k, v := range test.Headers {
+				payload, err := converter.GetDefaultDataConverter().ToPayload(v)
+				assert.NoError(t, err)
+				in.Set(k, payload)
+			}
+
+			ctx, err := p.Extract(context.Background(), in)
+			assert.NoError(t, err)
+
+			out := header{}
+			assert.NoError(t, p.Inject(ctx, out))
+			assert.Equal(t, test.Expected, decodeHeader(t, out))
+		})
+	}
+}
+
+func TestHeaderPropagatorWorkflow(t *testing.T) {
+	p := tracing.NewHeaderPropagator("tenant-id")
+
+	// The activity injects its context into a new header to show what would be
+	// passed on from it
+	activity := func(ctx context.Context) (map[string]string, error) {
+		out := header{}
+		if err := p.Inject(ctx, out); err != nil {
+			return nil, err
+		}
+		return decodeHeader(t, out), nil
+	}
+
+	wf := func(ctx workflow.Context) (map[string]string, error) {
+		ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+		})
+
+		var res map[string]string
+		err := workflow.ExecuteActivity(ctx, activity).Get(ctx, &res)
+		return res, err
+	}
+
+	payload, err := converter.GetDefaultDataConverter().ToPayload("tenant1")
+	assert.NoError(t, err)
+
+	var s testsuite.WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.SetContextPropagators([]workflow.ContextPropagator{p})
+	env.SetHeader(&commonpb.Header{
+		Fields: map[string]*commonpb.Payload{
+			"tenant-id": payload,
+		},
+	})
+	env.RegisterActivity(activity)
+
+	env.ExecuteWorkflow(wf)
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var res map[string]string
+	assert.NoError(t, env.GetWorkflowResult(&res))
+	assert.Equal(t, map[string]string{"tenant-id": "tenant1"}, res)
+}
+
+func decodeHeader(t *testing.T, h header) map[string]string {
+	t.Helper()
+
+	res := map[string]string{}
+	for k, v := range h {
+		var value string
+		assert.NoError(t, converter.GetDefaultDataConverter().FromPayload(v, &value))
+		res[k] = value
+	}
+	return res
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Temporal header the trace context is carried in
+const headerKey = "zigflow-tracing"
+
+type contextKey struct{}
+
+// The carrier is the serialised trace context. A map is stored in the
+// workflow context, rather than the span, so it survives being passed to child
+// workflows and activities.
+type carrier = propagation.MapCarrier
+
+type contextPropagator struct{}
+
+// NewContextPropagator passes the trace context between workflows and
+// activities in the Temporal headers. This must be added to the client's
+// ContextPropagators for task spans to be linked.
+func NewContextPropagator() workflow.ContextPropagator {
+	return &contextPropagator{}
+}
+
+func (p *contextPropagator) Inject(ctx context.Context, writer workflow.HeaderWriter) error {
+	c := carrier{}
+	otel.GetTextMapPropagator().Inject(ctx, c)
+
+	return writeHeader(writer, c)
+}
+
+func (p *contextPropagator) InjectFromWorkflow(ctx workflow.Context, writer workflow.HeaderWriter) error {
+	c, ok := ctx.Value(contextKey{}).(carrier)
+	if !ok {
+		return nil
+	}
+
+	return writeHeader(writer, c)
+}
+
+func (p *contextPropagator) Extract(ctx context.Context, reader workflow.HeaderReader) (context.Context, error) {
+	c, err := readHeader(reader)
+	if err != nil || c == nil {
+		return ctx, err
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, c), nil
+}
+
+func (p *contextPropagator) ExtractToWorkflow(ctx workflow.Context, reader workflow.HeaderReader) (workflow.Context, error) {
+	c, err := readHeader(reader)
+	if err != nil || c == nil {
+		return ctx, err
+	}
+
+	return workflow.WithValue(ctx, contextKey{}, c), nil
+}
+
+func writeHeader(writer workflow.HeaderWriter, c carrier) error {
+	if len(c) == 0 {
+		return nil
+	}
+
+	payload, err := converter.GetDefaultDataConverter().ToPayload(map[string]string(c))
+	if err != nil {
+		return fmt.Errorf("error converting trace context to payload: %w", err)
+	}
+	writer.Set(headerKey, payload)
+
+	return nil
+}
+
+func readHeader(reader workflow.HeaderReader) (carrier, error) {
+	payload, ok := reader.Get(headerKey)
+	if !ok {
+		return nil, nil
+	}
+
+	var c map[string]string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &c); err != nil {
+		return nil, fmt.Errorf("error converting payload to trace context: %w", err)
+	}
+
+	return c, nil
+}
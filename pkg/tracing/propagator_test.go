@@ -0,0 +1,95 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+type header map[string]*commonpb.Payload
+
+func (h header) Set(key string, value *commonpb.Payload) {
+	h[key] = value
+}
+
+func (h header) Get(key string) (*commonpb.Payload, bool) {
+	v, ok := h[key]
+	return v, ok
+}
+
+func (h header) ForEachKey(handler func(string, *commonpb.Payload) error) error {
+	for k, v := range h {
+		if err := handler(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestContextPropagator(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		Name    string
+		Span    trace.SpanContext
+		Headers int
+	}{
+		{
+			Name: "Span in context",
+			Span: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+			}),
+			Headers: 1,
+		},
+		{
+			Name: "No span in context",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			p := tracing.NewContextPropagator()
+			h := header{}
+
+			ctx := trace.ContextWithSpanContext(context.Background(), test.Span)
+			assert.NoError(t, p.Inject(ctx, h))
+			assert.Len(t, h, test.Headers)
+
+			extracted, err := p.Extract(context.Background(), h)
+			assert.NoError(t, err)
+
+			got := trace.SpanContextFromContext(extracted)
+			assert.Equal(t, test.Span.TraceID(), got.TraceID())
+			assert.Equal(t, test.Span.SpanID(), got.SpanID())
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/workflow"
+)
+
+// WorkflowSpan is a span started from inside a workflow
+type WorkflowSpan struct {
+	span trace.Span
+}
+
+// StartWorkflowSpan starts a span as a child of the trace context in the
+// workflow context. The returned context carries the new span to any child
+// workflows and activities. No span is created while the workflow is
+// replaying, as it was recorded when the code first ran.
+func StartWorkflowSpan(
+	ctx workflow.Context, name string, attrs ...attribute.KeyValue,
+) (workflow.Context, *WorkflowSpan) {
+	if workflow.IsReplaying(ctx) {
+		return ctx, &WorkflowSpan{span: trace.SpanFromContext(context.Background())}
+	}
+
+	parent := context.Background()
+	if c, ok := ctx.Value(contextKey{}).(carrier); ok {
+		parent = otel.GetTextMapPropagator().Extract(parent, c)
+	}
+
+	spanCtx, span := Tracer().Start(parent, name, trace.WithAttributes(attrs...))
+
+	c := carrier{}
+	otel.GetTextMapPropagator().Inject(spanCtx, c)
+
+	return workflow.WithValue(ctx, contextKey{}, c), &WorkflowSpan{span: span}
+}
+
+func (s *WorkflowSpan) SetAttributes(attrs ...attribute.KeyValue) {
+	s.span.SetAttributes(attrs...)
+}
+
+// End finishes the span, recording the error if there is one
+func (s *WorkflowSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
@@ -17,6 +17,8 @@
 package utils
 
 import (
+	"iter"
+
 	"github.com/rs/zerolog/log"
 	"go.temporal.io/sdk/workflow"
 )
@@ -27,8 +29,12 @@ type CancellableFuture struct {
 	Future  workflow.ChildWorkflowFuture
 }
 
+// CancellableFutures stores the futures in the order they're added. This
+// order must be used when iterating as map iteration is random, which isn't
+// deterministic when replaying a workflow.
 type CancellableFutures struct {
-	m map[string]CancellableFuture
+	keys []string
+	m    map[string]CancellableFuture
 }
 
 func (c *CancellableFutures) Add(key string, future CancellableFuture) {
@@ -36,11 +42,14 @@ func (c *CancellableFutures) Add(key string, future CancellableFuture) {
 		c.m = map[string]CancellableFuture{}
 	}
 
+	if _, ok := c.m[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
 	c.m[key] = future
 }
 
 func (c *CancellableFutures) CancelOthers(passedContext workflow.Context) {
-	for _, f := range c.m {
+	for _, f := range c.List() {
 		if f.Context != passedContext {
 			log.Debug().
 				Str("childWorkflowID", workflow.GetChildWorkflowOptions(f.Context).WorkflowID).
@@ -55,6 +64,13 @@ func (c *CancellableFutures) Length() int {
 	return len(c.m)
 }
 
-func (c *CancellableFutures) List() map[string]CancellableFuture {
-	return c.m
+// List returns the futures in the order they were added
+func (c *CancellableFutures) List() iter.Seq2[string, CancellableFuture] {
+	return func(yield func(string, CancellableFuture) bool) {
+		for _, key := range c.keys {
+			if !yield(key, c.m[key]) {
+				return
+			}
+		}
+	}
 }
@@ -0,0 +1,46 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancellableFuturesList(t *testing.T) {
+	futures := &utils.CancellableFutures{}
+
+	keys := []string{"zebra", "apple", "mango", "banana", "cherry"}
+	for _, key := range keys {
+		futures.Add(key, utils.CancellableFuture{})
+	}
+	// Replacing a future keeps its position
+	futures.Add("apple", utils.CancellableFuture{})
+
+	assert.Equal(t, len(keys), futures.Length())
+
+	// Run it several times as map iteration is random
+	for range 10 {
+		listed := make([]string, 0)
+		for key := range futures.List() {
+			listed = append(listed, key)
+		}
+		assert.Equal(t, keys, listed)
+	}
+}
@@ -17,14 +17,30 @@
 package utils
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 )
 
-// Convert the Serverless Workflow duration into a time Duration
-func ToDuration(v *model.Duration) time.Duration {
+// iso8601DurationParts matches the same ISO 8601 durations as the SDK's
+// validator, capturing each part
+var iso8601DurationParts = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Convert the Serverless Workflow duration into a time Duration. This can be
+// inline or an ISO 8601 duration. Years and months aren't supported as their
+// length varies.
+func ToDuration(v *model.Duration) (time.Duration, error) {
+	if v == nil {
+		return 0, nil
+	}
+
 	inline := v.AsInline()
+	if inline == nil {
+		return parseISO8601Duration(v.AsExpression())
+	}
 
 	var duration time.Duration
 	duration += time.Millisecond * time.Duration(inline.Milliseconds)
@@ -33,5 +49,32 @@ func ToDuration(v *model.Duration) time.Duration {
 	duration += time.Hour * time.Duration(inline.Hours)
 	duration += (time.Hour * 24) * time.Duration(inline.Days)
 
-	return duration
+	return duration, nil
+}
+
+// parseISO8601Duration converts an ISO 8601 duration, such as PT5M
+func parseISO8601Duration(value string) (time.Duration, error) {
+	parts := iso8601DurationParts.FindStringSubmatch(value)
+	if parts == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid duration %q: must be an ISO 8601 duration", value)
+	}
+	if parts[1] != "" || parts[2] != "" {
+		return 0, fmt.Errorf("invalid duration %q: years and months are not supported", value)
+	}
+
+	units := []time.Duration{time.Hour * 24, time.Hour, time.Minute, time.Second}
+
+	var duration time.Duration
+	for i, part := range parts[3:] {
+		if part == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		duration += time.Duration(n) * units[i]
+	}
+
+	return duration, nil
 }
@@ -28,41 +28,76 @@ import (
 func TestToDuration(t *testing.T) {
 	tests := []struct {
 		Name     string
-		Duration model.DurationInline
+		Duration *model.Duration
 		Expected time.Duration
+		Error    string
 	}{
 		{
 			Name: "10 second",
-			Duration: model.DurationInline{
+			Duration: &model.Duration{Value: model.DurationInline{
 				Seconds: 10,
-			},
+			}},
 			Expected: time.Second * 10,
 		},
 		{
 			Name: "1 minute",
-			Duration: model.DurationInline{
+			Duration: &model.Duration{Value: model.DurationInline{
 				Minutes: 1,
-			},
+			}},
 			Expected: time.Minute,
 		},
 		{
 			Name: "Complete",
-			Duration: model.DurationInline{
+			Duration: &model.Duration{Value: model.DurationInline{
 				Days:         4,
 				Hours:        6,
 				Minutes:      43,
 				Seconds:      32,
 				Milliseconds: 472,
-			},
+			}},
 			Expected: (time.Hour * 24 * 4) + (time.Hour * 6) + (time.Minute * 43) + (time.Second * 32) + (time.Millisecond * 472),
 		},
+		{
+			Name:     "Not set",
+			Expected: 0,
+		},
+		{
+			Name:     "ISO 8601",
+			Duration: model.NewDurationExpr("P1DT2H3M4S"),
+			Expected: (time.Hour * 24) + (time.Hour * 2) + (time.Minute * 3) + (time.Second * 4),
+		},
+		{
+			Name:     "ISO 8601 time only",
+			Duration: model.NewDurationExpr("PT30S"),
+			Expected: time.Second * 30,
+		},
+		{
+			Name:     "Years",
+			Duration: model.NewDurationExpr("P1Y"),
+			Error:    `invalid duration "P1Y": years and months are not supported`,
+		},
+		{
+			Name:     "Timestamp",
+			Duration: model.NewDurationExpr("2025-01-02T09:00:00Z"),
+			Error:    `invalid duration "2025-01-02T09:00:00Z": must be an ISO 8601 duration`,
+		},
+		{
+			Name:     "Empty",
+			Duration: model.NewDurationExpr("PT"),
+			Error:    `invalid duration "PT": must be an ISO 8601 duration`,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			assert.Equal(t, test.Expected, utils.ToDuration(&model.Duration{
-				Value: test.Duration,
-			}))
+			duration, err := utils.ToDuration(test.Duration)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, duration)
 		})
 	}
 }
@@ -33,7 +33,7 @@ func LoadEnvvars(prefix string) map[string]any {
 
 		if strings.HasPrefix(key, prefix) {
 			// Remove the prefix from the key
-			vars[strings.TrimLeft(key, prefix)] = value
+			vars[strings.TrimPrefix(key, prefix)] = value
 		}
 	}
 
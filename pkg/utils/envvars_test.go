@@ -0,0 +1,38 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEnvvars(t *testing.T) {
+	t.Setenv("ZIGGY_GREETING", "hello")
+	t.Setenv("ZIGGY_ZONE", "eu")
+	t.Setenv("OTHER_VALUE", "ignored")
+
+	envvars := utils.LoadEnvvars("ZIGGY_")
+
+	// Only the prefix is removed from the name
+	assert.Equal(t, "hello", envvars["GREETING"])
+	assert.Equal(t, "eu", envvars["ZONE"])
+	assert.NotContains(t, envvars, "OTHER_VALUE")
+	assert.NotContains(t, envvars, "VALUE")
+}
@@ -18,6 +18,7 @@ package utils
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/google/uuid"
@@ -43,6 +44,51 @@ var jqFuncs []jqFunc = []jqFunc{
 			return uuid.New().String()
 		},
 	},
+	{
+		// Deterministic, so is safe to use without a side effect
+		Name:    "uuid5",
+		MinArgs: 2,
+		MaxArgs: 2,
+		Func: func(_ any, args []any) any {
+			namespace, ok := args[0].(string)
+			if !ok {
+				return fmt.Errorf("uuid5 namespace must be a string")
+			}
+			name, ok := args[1].(string)
+			if !ok {
+				return fmt.Errorf("uuid5 name must be a string")
+			}
+
+			ns, err := parseUUIDNamespace(namespace)
+			if err != nil {
+				return err
+			}
+
+			return uuid.NewSHA1(ns, []byte(name)).String()
+		},
+	},
+}
+
+// Well-known UUID namespaces, as defined in RFC 4122
+var uuidNamespaces = map[string]uuid.UUID{
+	"dns":  uuid.NameSpaceDNS,
+	"url":  uuid.NameSpaceURL,
+	"oid":  uuid.NameSpaceOID,
+	"x500": uuid.NameSpaceX500,
+}
+
+// parseUUIDNamespace accepts either a well-known namespace name or a UUID
+func parseUUIDNamespace(namespace string) (uuid.UUID, error) {
+	if ns, ok := uuidNamespaces[strings.ToLower(namespace)]; ok {
+		return ns, nil
+	}
+
+	ns, err := uuid.Parse(namespace)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid uuid5 namespace: %s", namespace)
+	}
+
+	return ns, nil
 }
 
 // The return value could be any value depending upon how it's parsed
@@ -125,7 +171,7 @@ func traverseAndEvaluate(node any, state *State, evaluationWrapper ExpressionWra
 	}
 }
 
-func evaluateJQExpression(expression string, state *State) (any, error) {
+func compileJQExpression(expression string) (*gojq.Code, error) {
 	query, err := gojq.Parse(expression)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse jq expression: %s, error: %w", expression, err)
@@ -141,6 +187,37 @@ func evaluateJQExpression(expression string, state *State) (any, error) {
 		return nil, fmt.Errorf("error compiling gojq code: %w", err)
 	}
 
+	return code, nil
+}
+
+// CompileExpressions compiles every runtime expression found in the node
+// without evaluating it. The callback receives the dot separated path to each
+// expression that fails to compile.
+func CompileExpressions(path string, node any, fn func(path string, err error)) {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			CompileExpressions(path+"."+key, value, fn)
+		}
+	case []any:
+		for i, value := range v {
+			CompileExpressions(fmt.Sprintf("%s.%d", path, i), value, fn)
+		}
+	case string:
+		if model.IsStrictExpr(v) {
+			if _, err := compileJQExpression(model.SanitizeExpr(v)); err != nil {
+				fn(path, err)
+			}
+		}
+	}
+}
+
+func evaluateJQExpression(expression string, state *State) (any, error) {
+	code, err := compileJQExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
 	iter := code.Run(state.GetAsMap())
 	v, ok := iter.Next()
 	if !ok {
@@ -164,17 +241,26 @@ func CheckIfStatement(ifStatement *model.RuntimeExpression, state *State) (bool,
 		return false, temporal.NewNonRetryableApplicationError("Error parsing if statement", "If statement error", err)
 	}
 
-	// Response can be a boolean, "TRUE" (case-insensitive) or "1"
+	// Response can be a boolean, "TRUE" (case-insensitive), "1", a non-zero
+	// number or null, which is false
 	switch r := res.(type) {
+	case nil:
+		return false, nil
 	case bool:
 		return r, nil
 	case string:
 		return strings.EqualFold(r, "TRUE") || r == "1", nil
+	case int:
+		return r != 0, nil
+	case float64:
+		return r != 0, nil
+	case *big.Int:
+		return r.Sign() != 0, nil
 	default:
 		return false, temporal.NewNonRetryableApplicationError(
 			"If statement response type unknown",
 			"If statement error",
-			fmt.Errorf("response not string or bool"),
+			fmt.Errorf("response not bool, string, number or null"),
 		)
 	}
 }
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUID5(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Expression string
+		Expected   string
+		Error      bool
+	}{
+		{
+			Name:       "Well-known namespace",
+			Expression: `${ uuid5("dns"; "example.com") }`,
+			Expected:   "cfbff0d1-9375-5685-968c-48ce8b15ae17",
+		},
+		{
+			Name:       "UUID namespace",
+			Expression: `${ uuid5("6ba7b810-9dad-11d1-80b4-00c04fd430c8"; "example.com") }`,
+			Expected:   "cfbff0d1-9375-5685-968c-48ce8b15ae17",
+		},
+		{
+			Name:       "Name from state",
+			Expression: `${ uuid5("url"; .input.id) }`,
+			Expected:   uuid.NewSHA1(uuid.NameSpaceURL, []byte("some-id")).String(),
+		},
+		{
+			Name:       "Invalid namespace",
+			Expression: `${ uuid5("invalid"; "example.com") }`,
+			Error:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			state := utils.NewState()
+			state.Input = map[string]any{
+				"id": "some-id",
+			}
+
+			first, err := utils.EvaluateString(test.Expression, state)
+			if test.Error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, first)
+
+			// The same inputs must always produce the same UUID
+			second, err := utils.EvaluateString(test.Expression, state)
+			assert.NoError(t, err)
+			assert.Equal(t, first, second)
+
+			id, err := uuid.Parse(first.(string))
+			assert.NoError(t, err)
+			assert.Equal(t, uuid.Version(5), id.Version())
+		})
+	}
+}
+
+func TestUUID5DiffersFromUUID(t *testing.T) {
+	state := utils.NewState()
+
+	v4, err := utils.EvaluateString(`${ uuid }`, state)
+	assert.NoError(t, err)
+
+	v5, err := utils.EvaluateString(`${ uuid5("dns"; "example.com") }`, state)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, v4, v5)
+
+	id, err := uuid.Parse(v4.(string))
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), id.Version())
+}
+
+func TestCheckIfStatement(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Statement *model.RuntimeExpression
+		Expected  bool
+		Error     bool
+	}{
+		{
+			Name:     "No statement",
+			Expected: true,
+		},
+		{
+			Name:      "Boolean",
+			Statement: model.NewExpr("${ .input.secret == \"s3cr3t\" }"),
+			Expected:  true,
+		},
+		{
+			Name:      "String",
+			Statement: model.NewExpr("${ \"TRUE\" }"),
+			Expected:  true,
+		},
+		{
+			Name:      "Number string",
+			Statement: model.NewExpr("${ \"0\" }"),
+		},
+		{
+			Name:      "Integer",
+			Statement: model.NewExpr("${ .input.count }"),
+			Expected:  true,
+		},
+		{
+			Name:      "Zero",
+			Statement: model.NewExpr("${ .input.count - 3 }"),
+		},
+		{
+			Name:      "Conditional integer",
+			Statement: model.NewExpr("${ .input.count > 0 | if . then 1 else 0 end }"),
+			Expected:  true,
+		},
+		{
+			Name:      "Float",
+			Statement: model.NewExpr("${ .input.ratio }"),
+			Expected:  true,
+		},
+		{
+			Name:      "Zero float",
+			Statement: model.NewExpr("${ .input.ratio - 0.5 }"),
+		},
+		{
+			Name:      "Big integer",
+			Statement: model.NewExpr("${ 100000000000000000000 }"),
+			Expected:  true,
+		},
+		{
+			Name:      "Null",
+			Statement: model.NewExpr("${ .input.missing }"),
+		},
+		{
+			Name:      "Unknown type",
+			Statement: model.NewExpr("${ .input }"),
+			Error:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			state := utils.NewState()
+			state.Input = map[string]any{
+				"secret": "s3cr3t",
+				"count":  3,
+				"ratio":  0.5,
+			}
+
+			// The state may contain sensitive data, so nothing may be printed
+			var res bool
+			var err error
+			stdout := captureStdout(t, func() {
+				res, err = utils.CheckIfStatement(test.Statement, state)
+			})
+			assert.Empty(t, stdout)
+
+			if test.Error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, res)
+		})
+	}
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	fn()
+	assert.NoError(t, w.Close())
+
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+
+	return string(out)
+}
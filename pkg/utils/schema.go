@@ -0,0 +1,129 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SchemaFieldError is a single field which failed JSON schema validation
+type SchemaFieldError struct {
+	// JSON pointer to the field, which is empty for the root
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// SchemaValidationError is a validation error which lists the fields which
+// failed, so the caller can see exactly what was wrong
+type SchemaValidationError struct {
+	Cause  *model.Error
+	Errors []SchemaFieldError
+}
+
+func (e *SchemaValidationError) Error() string {
+	return e.Cause.Error()
+}
+
+func (e *SchemaValidationError) Unwrap() error {
+	return e.Cause
+}
+
+// MarshalJSON adds the fields to the validation error so it can be used as the
+// details of a Temporal error
+func (e *SchemaValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*model.Error
+		Errors []SchemaFieldError `json:"errors"`
+	}{
+		Error:  e.Cause,
+		Errors: e.Errors,
+	})
+}
+
+// ValidateSchema validates the data against the schema, returning a
+// SchemaValidationError if it's invalid. This is the same as the SDK's
+// ValidateSchema, except the errors are kept as a list rather than a string.
+func ValidateSchema(data any, schema *model.Schema, instance string) error {
+	if schema == nil {
+		return nil
+	}
+
+	schema.ApplyDefaults()
+
+	if schema.Format != model.DefaultSchema {
+		return model.NewErrValidation(fmt.Errorf("unsupported schema format: '%s'", schema.Format), instance)
+	}
+	if schema.Document == nil {
+		return model.NewErrValidation(errors.New("schema must have a document - external resources are not supported"), instance)
+	}
+
+	document, err := json.Marshal(schema.Document)
+	if err != nil {
+		return model.NewErrValidation(fmt.Errorf("failed to marshal schema document to JSON: %w", err), instance)
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(document), gojsonschema.NewGoLoader(data))
+	if err != nil {
+		return model.NewErrValidation(fmt.Errorf("failed to validate JSON schema: %w", err), instance)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	fields := make([]SchemaFieldError, 0, len(result.Errors()))
+	messages := make([]string, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		fields = append(fields, SchemaFieldError{
+			Pointer: schemaErrorPointer(e),
+			Message: e.Description(),
+		})
+		messages = append(messages, "- "+e.String())
+	}
+
+	return &SchemaValidationError{
+		Cause:  model.NewErrValidation(fmt.Errorf("JSON schema validation failed:\n%s", strings.Join(messages, "\n")), instance),
+		Errors: fields,
+	}
+}
+
+// schemaErrorPointer converts the error's context into a JSON pointer. A
+// missing required property points to the property rather than its parent.
+func schemaErrorPointer(e gojsonschema.ResultError) string {
+	// The context is rendered with a delimiter that can't appear in a key
+	const delimiter = "\x00"
+
+	segments := strings.Split(e.Context().String(delimiter), delimiter)[1:]
+	if e.Type() == "required" {
+		if property, ok := e.Details()["property"].(string); ok {
+			segments = append(segments, property)
+		}
+	}
+
+	var pointer strings.Builder
+	for _, s := range segments {
+		pointer.WriteString("/")
+		pointer.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(s))
+	}
+
+	return pointer.String()
+}
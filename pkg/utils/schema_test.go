@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchema(t *testing.T) {
+	schema := &model.Schema{
+		Format: model.DefaultSchema,
+		Document: map[string]any{
+			"type":     "object",
+			"required": []any{"a/b"},
+			"properties": map[string]any{
+				"items": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"type": "string"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		Name     string
+		Data     any
+		Pointers []string
+	}{
+		{
+			Name: "Valid",
+			Data: map[string]any{
+				"a/b":   true,
+				"items": []any{"x"},
+			},
+		},
+		{
+			Name:     "Root",
+			Data:     "hello",
+			Pointers: []string{""},
+		},
+		{
+			Name: "Escaped required property",
+			Data: map[string]any{
+				"items": []any{"x"},
+			},
+			Pointers: []string{"/a~1b"},
+		},
+		{
+			Name: "Array item",
+			Data: map[string]any{
+				"a/b":   true,
+				"items": []any{"x", 1},
+			},
+			Pointers: []string{"/items/1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := utils.ValidateSchema(test.Data, schema, "task")
+			if test.Pointers == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var vErr *utils.SchemaValidationError
+			assert.ErrorAs(t, err, &vErr)
+
+			pointers := make([]string, 0)
+			for _, e := range vErr.Errors {
+				pointers = append(pointers, e.Pointer)
+			}
+			assert.Equal(t, test.Pointers, pointers)
+		})
+	}
+
+	assert.NoError(t, utils.ValidateSchema("anything", nil, "task"))
+}
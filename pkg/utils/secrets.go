@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SecretMask replaces secret values
+const SecretMask = "***"
+
+// LogSecrets are masked in the logs, if the logger writes through its Writer
+var LogSecrets = &Secrets{}
+
+// Secrets masks secret values in strings. This is safe to use concurrently.
+type Secrets struct {
+	mu       sync.RWMutex
+	values   []string
+	replacer *strings.Replacer
+}
+
+// Add adds the secret values. Empty values are ignored, as they can't be
+// masked.
+func (s *Secrets) Add(values ...string) *Secrets {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		// A value that's written in JSON may be escaped
+		b, _ := json.Marshal(v)
+		escaped := string(b[1 : len(b)-1])
+
+		for _, value := range []string{v, escaped} {
+			if !slices.Contains(s.values, value) {
+				s.values = append(s.values, value)
+			}
+		}
+	}
+
+	// Replace the longest values first, in case one secret contains another
+	slices.SortFunc(s.values, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	pairs := make([]string, 0, len(s.values)*2)
+	for _, v := range s.values {
+		pairs = append(pairs, v, SecretMask)
+	}
+	s.replacer = strings.NewReplacer(pairs...)
+
+	return s
+}
+
+// Mask replaces any secret values in the string
+func (s *Secrets) Mask(str string) string {
+	if s == nil {
+		return str
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.replacer == nil {
+		return str
+	}
+
+	return s.replacer.Replace(str)
+}
+
+// MaskValue replaces any secret values in a string or an error. Maps and
+// slices are copied with their values masked. Anything else is returned as-is.
+func (s *Secrets) MaskValue(v any) any {
+	switch e := v.(type) {
+	case string:
+		return s.Mask(e)
+	case error:
+		if masked := s.Mask(e.Error()); masked != e.Error() {
+			return errors.New(masked)
+		}
+		return e
+	case map[string]any:
+		masked := make(map[string]any, len(e))
+		for k, value := range e {
+			masked[k] = s.MaskValue(value)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(e))
+		for i, value := range e {
+			masked[i] = s.MaskValue(value)
+		}
+		return masked
+	default:
+		return v
+	}
+}
+
+// Writer returns a writer that masks any secret values before writing to w.
+// Each write must contain whole values, as a value split across writes isn't
+// masked.
+func (s *Secrets) Writer(w io.Writer) io.Writer {
+	return secretsWriter{secrets: s, w: w}
+}
+
+type secretsWriter struct {
+	secrets *Secrets
+	w       io.Writer
+}
+
+func (s secretsWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(s.w, s.secrets.Mask(string(p))); err != nil {
+		return 0, err
+	}
+
+	// Report the original length, as that's what was consumed
+	return len(p), nil
+}
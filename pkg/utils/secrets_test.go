@@ -0,0 +1,108 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretsMask(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Secrets  []string
+		Input    string
+		Expected string
+	}{
+		{
+			Name:     "no secrets",
+			Input:    "hello world",
+			Expected: "hello world",
+		},
+		{
+			Name:     "secret",
+			Secrets:  []string{"s3cret"},
+			Input:    "token=s3cret&token2=s3cret",
+			Expected: "token=***&token2=***",
+		},
+		{
+			Name:     "longest secret first",
+			Secrets:  []string{"abc", "abcdef"},
+			Input:    "abcdef abc",
+			Expected: "*** ***",
+		},
+		{
+			Name:     "JSON escaped secret",
+			Secrets:  []string{`pa"ss`},
+			Input:    `{"password":"pa\"ss"}`,
+			Expected: `{"password":"***"}`,
+		},
+		{
+			Name:     "empty secrets ignored",
+			Secrets:  []string{""},
+			Input:    "hello world",
+			Expected: "hello world",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			s := &Secrets{}
+			s.Add(test.Secrets...)
+
+			assert.Equal(t, test.Expected, s.Mask(test.Input))
+		})
+	}
+}
+
+func TestSecretsMaskValue(t *testing.T) {
+	s := (&Secrets{}).Add("s3cret")
+
+	assert.Equal(t, map[string]any{
+		"token":  "***",
+		"nested": []any{"Bearer ***", 1},
+	}, s.MaskValue(map[string]any{
+		"token":  "s3cret",
+		"nested": []any{"Bearer s3cret", 1},
+	}))
+	assert.EqualError(t, s.MaskValue(errors.New("invalid token s3cret")).(error), "invalid token ***")
+
+	err := errors.New("not found")
+	assert.Equal(t, err, s.MaskValue(err))
+}
+
+func TestSecretsMaskNil(t *testing.T) {
+	var s *Secrets
+
+	assert.Equal(t, "s3cret", s.Mask("s3cret"))
+}
+
+func TestSecretsWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	s := (&Secrets{}).Add("s3cret")
+	w := s.Writer(&buf)
+
+	input := []byte(`{"level":"info","token":"s3cret"}`)
+	n, err := w.Write(input)
+	assert.NoError(t, err)
+	assert.Equal(t, len(input), n)
+	assert.Equal(t, `{"level":"info","token":"***"}`, buf.String())
+}
@@ -19,6 +19,8 @@ package utils
 import (
 	"context"
 	"maps"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -28,11 +30,26 @@ import (
 	"go.temporal.io/sdk/workflow"
 )
 
+// OutputReferenceKey identifies an output that has been offloaded to external
+// storage. The output is replaced in the state with a map containing this key.
+const OutputReferenceKey = "$zigflowRef"
+
 type State struct {
 	Data   map[string]any `json:"data"`            // Data stored along the way
 	Env    map[string]any `json:"env"`             // Available environment variables
 	Input  any            `json:"input,omitempty"` // The input given by the caller
 	Output map[string]any `json:"output"`          // What will be output to the caller
+
+	// The task to resume from after a continue-as-new. This is cleared once the
+	// new run has picked it up
+	ContinueFrom string `json:"continueFrom,omitempty"`
+
+	// The raw result of a task, available when transforming its output
+	result any
+
+	// Offloaded outputs that have been retrieved from external storage. These
+	// are never serialised so they don't add to the workflow history
+	resolved map[string]any
 }
 
 func (s *State) init() *State {
@@ -45,16 +62,101 @@ func (s *State) init() *State {
 	if s.Output == nil {
 		s.Output = map[string]any{}
 	}
+	if s.resolved == nil {
+		s.resolved = map[string]any{}
+	}
 
 	return s
 }
 
+// AddData shallow merges the data into the existing data. Top-level keys are
+// replaced, so an existing nested map is replaced rather than merged - use
+// MergeData to merge them. The values aren't cloned, so must not be changed by
+// the caller afterwards.
 func (s *State) AddData(data map[string]any) *State {
+	if s.Data == nil {
+		s.Data = map[string]any{}
+	}
 	maps.Copy(s.Data, data)
 
 	return s
 }
 
+// RemoveData deletes the keys from the data
+func (s *State) RemoveData(keys ...string) *State {
+	for _, key := range keys {
+		delete(s.Data, key)
+	}
+
+	return s
+}
+
+// ClearData deletes all the data
+func (s *State) ClearData() *State {
+	s.Data = map[string]any{}
+
+	return s
+}
+
+// MergeData deep merges the data into the existing data. Nested maps are
+// merged recursively, all other values (including slices) are replaced. The
+// data is cloned, so can be safely changed by the caller afterwards.
+func (s *State) MergeData(data map[string]any) *State {
+	s.Data = deepMerge(s.Data, swUtils.DeepClone(data))
+
+	return s
+}
+
+func deepMerge(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = map[string]any{}
+	}
+
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+
+		if srcIsMap && dstIsMap {
+			dst[key] = deepMerge(dstMap, srcMap)
+		} else {
+			dst[key] = value
+		}
+	}
+
+	return dst
+}
+
+// ApplyChanges copies the top-level data and output keys which were added or
+// changed between before and after into the state. Keys which were removed in
+// after are removed from the state. This is used to combine states which have
+// been changed independently of each other.
+func (s *State) ApplyChanges(before, after *State) *State {
+	s.Data = applyChanges(s.Data, before.Data, after.Data)
+	s.Output = applyChanges(s.Output, before.Output, after.Output)
+
+	return s
+}
+
+func applyChanges(dst, before, after map[string]any) map[string]any {
+	if dst == nil {
+		dst = map[string]any{}
+	}
+
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			dst[key] = value
+		}
+	}
+
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			delete(dst, key)
+		}
+	}
+
+	return dst
+}
+
 func (s *State) AddOutput(task model.Task, output any) *State {
 	if output != nil {
 		if export := task.GetBase().Export; export != nil {
@@ -147,6 +249,8 @@ func (s *State) ClearOutput() *State {
 	return s
 }
 
+// Clone returns a deep copy of the state. Anything that could outlive the
+// current task, such as a child workflow or activity, should get a clone.
 func (s *State) Clone() *State {
 	s1 := NewState()
 
@@ -154,6 +258,8 @@ func (s *State) Clone() *State {
 	s1.Env = swUtils.DeepClone(s.Env)
 	s1.Input = swUtils.DeepCloneValue(s.Input)
 	s1.Output = swUtils.DeepClone(s.Output)
+	s1.ContinueFrom = s.ContinueFrom
+	maps.Copy(s1.resolved, s.resolved)
 
 	return s1
 }
@@ -162,12 +268,99 @@ func (s *State) Clone() *State {
 func (s *State) GetAsMap() map[string]any {
 	s1 := s.Clone()
 
-	return map[string]any{
+	// Replace any offloaded outputs with their resolved values
+	s.resolveReferences(s1.Data)
+	s.resolveReferences(s1.Output)
+
+	m := map[string]any{
 		"data":   s1.Data,
 		"env":    s1.Env,
 		"input":  s1.Input,
 		"output": s1.Output,
 	}
+	if s.result != nil {
+		m["result"] = s.resolvedValue(swUtils.DeepCloneValue(s.result))
+	}
+
+	return m
+}
+
+// WithResult returns a copy of the state with the task's raw result set. This
+// is available to runtime expressions as ".result".
+func (s *State) WithResult(result any) *State {
+	s1 := s.Clone()
+	s1.result = result
+
+	return s1
+}
+
+// SetResolvedReference stores the value of an offloaded output
+func (s *State) SetResolvedReference(ref string, value any) *State {
+	if s.resolved == nil {
+		s.resolved = map[string]any{}
+	}
+	s.resolved[ref] = value
+
+	return s
+}
+
+// resolveReferences replaces the offloaded outputs in the map with their
+// resolved values
+func (s *State) resolveReferences(m map[string]any) {
+	for key, value := range m {
+		m[key] = s.resolvedValue(value)
+	}
+}
+
+// resolvedValue returns the resolved value if the value is an offloaded output
+// that has been retrieved
+func (s *State) resolvedValue(value any) any {
+	if ref, ok := GetOutputReference(value); ok {
+		if resolved, ok := s.resolved[ref]; ok {
+			return swUtils.DeepCloneValue(resolved)
+		}
+	}
+
+	return value
+}
+
+// UnresolvedReferences returns the offloaded outputs in the data, output and
+// result that have not yet been retrieved. These are sorted so that the result
+// is deterministic.
+func (s *State) UnresolvedReferences() []string {
+	values := slices.Concat(slices.Collect(maps.Values(s.Data)), slices.Collect(maps.Values(s.Output)), []any{s.result})
+
+	refs := make([]string, 0)
+	for _, value := range values {
+		if ref, ok := GetOutputReference(value); ok {
+			if _, resolved := s.resolved[ref]; !resolved && !slices.Contains(refs, ref) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	slices.Sort(refs)
+
+	return refs
+}
+
+// GetOutputReference returns the reference if the value is an offloaded output
+func GetOutputReference(value any) (string, bool) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+
+	ref, ok := m[OutputReferenceKey].(string)
+
+	return ref, ok
+}
+
+// NewOutputReference creates the value stored in place of an offloaded output
+func NewOutputReference(ref string, size int) map[string]any {
+	return map[string]any{
+		OutputReferenceKey: ref,
+		"size":             size,
+	}
 }
 
 func NewState() *State {
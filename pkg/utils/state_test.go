@@ -0,0 +1,296 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveData(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Keys     []string
+		Expected map[string]any
+	}{
+		{
+			Name: "No keys",
+			Expected: map[string]any{
+				"a": 1,
+				"b": 2,
+				"c": 3,
+			},
+		},
+		{
+			Name: "Some keys",
+			Keys: []string{"a", "c"},
+			Expected: map[string]any{
+				"b": 2,
+			},
+		},
+		{
+			Name: "Unknown key",
+			Keys: []string{"d"},
+			Expected: map[string]any{
+				"a": 1,
+				"b": 2,
+				"c": 3,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			state := utils.NewState().AddData(map[string]any{
+				"a": 1,
+				"b": 2,
+				"c": 3,
+			})
+
+			assert.Equal(t, test.Expected, state.RemoveData(test.Keys...).Data)
+		})
+	}
+}
+
+func TestClearData(t *testing.T) {
+	state := utils.NewState().AddData(map[string]any{
+		"a": 1,
+	})
+
+	assert.Equal(t, map[string]any{}, state.ClearData().Data)
+
+	// The data can still be added to after it's been cleared
+	assert.Equal(t, map[string]any{"b": 2}, state.AddData(map[string]any{"b": 2}).Data)
+}
+
+func TestAddData(t *testing.T) {
+	tests := []struct {
+		Name     string
+		State    *utils.State
+		Data     map[string]any
+		Expected map[string]any
+	}{
+		{
+			Name: "Top-level keys are replaced",
+			State: utils.NewState().AddData(map[string]any{
+				"name": "Test",
+				"address": map[string]any{
+					"country": "UK",
+				},
+			}),
+			Data: map[string]any{
+				"address": map[string]any{
+					"city": "London",
+				},
+			},
+			Expected: map[string]any{
+				"name": "Test",
+				"address": map[string]any{
+					"city": "London",
+				},
+			},
+		},
+		{
+			Name:  "Nil data in state",
+			State: &utils.State{},
+			Data: map[string]any{
+				"name": "Test",
+			},
+			Expected: map[string]any{
+				"name": "Test",
+			},
+		},
+		{
+			Name: "Nil data added",
+			State: utils.NewState().AddData(map[string]any{
+				"name": "Test",
+			}),
+			Expected: map[string]any{
+				"name": "Test",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, test.State.AddData(test.Data).Data)
+		})
+	}
+}
+
+func TestMergeData(t *testing.T) {
+	tests := []struct {
+		Name     string
+		State    *utils.State
+		Data     map[string]any
+		Expected map[string]any
+	}{
+		{
+			Name: "Nested maps are merged",
+			State: utils.NewState().AddData(map[string]any{
+				"name": "Test",
+				"address": map[string]any{
+					"country": "UK",
+					"city":    "Manchester",
+				},
+			}),
+			Data: map[string]any{
+				"address": map[string]any{
+					"city": "London",
+				},
+			},
+			Expected: map[string]any{
+				"name": "Test",
+				"address": map[string]any{
+					"country": "UK",
+					"city":    "London",
+				},
+			},
+		},
+		{
+			Name: "Slices are replaced",
+			State: utils.NewState().AddData(map[string]any{
+				"tags": []any{"a", "b"},
+			}),
+			Data: map[string]any{
+				"tags": []any{"c"},
+			},
+			Expected: map[string]any{
+				"tags": []any{"c"},
+			},
+		},
+		{
+			Name: "Map replaced by a scalar",
+			State: utils.NewState().AddData(map[string]any{
+				"address": map[string]any{
+					"country": "UK",
+				},
+			}),
+			Data: map[string]any{
+				"address": "London",
+			},
+			Expected: map[string]any{
+				"address": "London",
+			},
+		},
+		{
+			Name:  "Nil data in state",
+			State: &utils.State{},
+			Data: map[string]any{
+				"name": "Test",
+			},
+			Expected: map[string]any{
+				"name": "Test",
+			},
+		},
+		{
+			Name: "Nil data merged",
+			State: utils.NewState().AddData(map[string]any{
+				"name": "Test",
+			}),
+			Expected: map[string]any{
+				"name": "Test",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, test.State.MergeData(test.Data).Data)
+		})
+	}
+}
+
+func TestMergeDataClones(t *testing.T) {
+	data := map[string]any{
+		"address": map[string]any{
+			"city": "London",
+		},
+	}
+
+	state := utils.NewState().MergeData(data)
+
+	// Changing the merged data doesn't change the state
+	data["address"].(map[string]any)["city"] = "Manchester"
+
+	assert.Equal(t, map[string]any{
+		"address": map[string]any{
+			"city": "London",
+		},
+	}, state.Data)
+}
+
+func TestClone(t *testing.T) {
+	state := utils.NewState().AddData(map[string]any{
+		"address": map[string]any{
+			"city": "London",
+		},
+	})
+	state.Input = map[string]any{"id": 1}
+	state.Output["result"] = map[string]any{"done": true}
+
+	clone := state.Clone()
+	assert.Equal(t, state.GetAsMap(), clone.GetAsMap())
+
+	// Changing the clone doesn't change the original
+	clone.Data["address"].(map[string]any)["city"] = "Manchester"
+	clone.Input.(map[string]any)["id"] = 2
+	clone.Output["result"].(map[string]any)["done"] = false
+	clone.RemoveData("address")
+
+	assert.Equal(t, map[string]any{
+		"address": map[string]any{
+			"city": "London",
+		},
+	}, state.Data)
+	assert.Equal(t, map[string]any{"id": 1}, state.Input)
+	assert.Equal(t, map[string]any{"result": map[string]any{"done": true}}, state.Output)
+}
+
+func TestApplyChanges(t *testing.T) {
+	before := &utils.State{
+		Data: map[string]any{
+			"unchanged": 1,
+			"changed":   2,
+			"removed":   3,
+			"nested":    map[string]any{"a": 1},
+		},
+		Output: map[string]any{},
+	}
+
+	state := before.Clone()
+	state.Data["unchanged"] = "set elsewhere"
+
+	after := before.Clone()
+	after.Data["changed"] = 20
+	after.Data["added"] = 4
+	after.Data["nested"].(map[string]any)["b"] = 2
+	after.Output["result"] = "done"
+	delete(after.Data, "removed")
+
+	state.ApplyChanges(before, after)
+
+	assert.Equal(t, map[string]any{
+		"unchanged": "set elsewhere",
+		"changed":   20,
+		"added":     4,
+		"nested":    map[string]any{"a": 1, "b": 2},
+	}, state.Data)
+	assert.Equal(t, map[string]any{"result": "done"}, state.Output)
+}
@@ -17,7 +17,12 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
@@ -66,6 +71,19 @@ func NewValidator() (*Validator, error) {
 
 	validate := model.GetValidator()
 
+	// The wait task can also wait until an absolute time. The SDK validates
+	// each task both as a field of the task item and on its own.
+	validate.RegisterCustomTypeFunc(func(v reflect.Value) any {
+		task := v.Interface().(model.WaitTask)
+		return waitTaskFields(&task)
+	}, model.WaitTask{})
+	validate.RegisterStructValidation(validateTaskItem, model.TaskItem{})
+
+	// The run task's workflow name can be set at runtime
+	if err := validate.RegisterValidation("hostname_rfc1123", validateHostnameOrWorkflowName); err != nil {
+		return nil, fmt.Errorf("error registering hostname validator: %w", err)
+	}
+
 	if err := en_translations.RegisterDefaultTranslations(validate, trans); err != nil {
 		return nil, fmt.Errorf("error registering validator translations: %w", err)
 	}
@@ -74,3 +92,201 @@ func NewValidator() (*Validator, error) {
 		validate: validate,
 	}, nil
 }
+
+// ValidateExpressions checks that every runtime expression in the document
+// compiles. This catches errors before the workflow runs rather than when the
+// task executes.
+func ValidateExpressions(doc *model.Workflow) []ValidationErrors {
+	vErrs := make([]ValidationErrors, 0)
+
+	addErr := func(path string, err error) {
+		vErrs = append(vErrs, ValidationErrors{
+			Key:     path,
+			Message: err.Error(),
+		})
+	}
+
+	if doc.Input != nil {
+		CompileExpressions("input", toExpressionTree(doc.Input), addErr)
+	}
+	if doc.Output != nil {
+		CompileExpressions("output", toExpressionTree(doc.Output), addErr)
+	}
+
+	WalkTasks(doc.Do, func(path string, task *model.TaskItem) {
+		CompileExpressions(path, taskExpressionTree(task.Task), addErr)
+	})
+
+	slices.SortFunc(vErrs, func(a, b ValidationErrors) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return vErrs
+}
+
+// toExpressionTree converts the value to a JSON tree so any expressions can be
+// found
+func toExpressionTree(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil
+	}
+
+	return tree
+}
+
+// taskExpressionTree returns the task's JSON tree with any nested task lists
+// removed, as these are visited separately
+func taskExpressionTree(task model.Task) any {
+	tree, ok := toExpressionTree(task).(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	delete(tree, "do")
+	delete(tree, "try")
+	if catch, ok := tree["catch"].(map[string]any); ok {
+		delete(catch, "do")
+	}
+	if fork, ok := tree["fork"].(map[string]any); ok {
+		delete(fork, "branches")
+	}
+
+	return tree
+}
+
+// ValidateFlowDirectives checks that every "then" which names a task targets
+// one that will be reached. A do list only ever moves forwards, so the target
+// must be a later sibling. A switch may also target any do task in the
+// document, which is run as a child workflow.
+func ValidateFlowDirectives(doc *model.Workflow) []ValidationErrors {
+	vErrs := make([]ValidationErrors, 0)
+
+	workflows := make([]string, 0)
+	WalkTasks(doc.Do, func(_ string, task *model.TaskItem) {
+		if task.AsDoTask() != nil {
+			workflows = append(workflows, task.Key)
+		}
+	})
+
+	WalkTaskLists(doc.Do, func(prefix string, list *model.TaskList) {
+		for i, item := range *list {
+			path := item.Key
+			if prefix != "" {
+				path = prefix + "." + item.Key
+			}
+
+			later := make([]string, 0)
+			for _, sibling := range (*list)[i+1:] {
+				later = append(later, sibling.Key)
+			}
+
+			check := func(key string, then *model.FlowDirective, targets ...[]string) {
+				if then == nil || then.IsEnum() {
+					return
+				}
+				for _, t := range targets {
+					if slices.Contains(t, then.Value) {
+						return
+					}
+				}
+				vErrs = append(vErrs, ValidationErrors{
+					Key:     key,
+					Message: fmt.Sprintf("then directive on task '%s' references task '%s', which cannot be reached", path, then.Value),
+				})
+			}
+
+			check(path, item.Task.GetBase().Then, later)
+
+			if switchTask := item.AsSwitchTask(); switchTask != nil {
+				for _, switchItem := range switchTask.Switch {
+					for name, c := range switchItem {
+						check(path+".switch."+name, c.Then, later, workflows)
+					}
+				}
+			}
+		}
+	})
+
+	slices.SortFunc(vErrs, func(a, b ValidationErrors) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return vErrs
+}
+
+// waitTask is validated in place of a wait task
+type waitTask struct {
+	model.TaskBase
+	Wait *model.Duration `validate:"required"`
+}
+
+// validateTaskItem replaces the SDK's task item validation so that a wait task
+// is checked with waitTaskFields. Every other task is validated as before.
+func validateTaskItem(sl validator.StructLevel) {
+	item := sl.Current().Interface().(model.TaskItem)
+
+	if item.Key == "" {
+		sl.ReportError(item.Key, "Key", "Key", "required", "")
+		return
+	}
+
+	if item.Task == nil {
+		sl.ReportError(item.Task, "Task", "Task", "required", "")
+		return
+	}
+
+	var task any = item.Task
+	if wait, ok := item.Task.(*model.WaitTask); ok {
+		task = waitTaskFields(wait)
+	}
+
+	var vErrs validator.ValidationErrors
+	if err := sl.Validator().Struct(task); errors.As(err, &vErrs) {
+		for _, e := range vErrs {
+			sl.ReportError(e.Value(), "Task."+e.StructNamespace(), e.StructField(), e.Tag(), e.Param())
+		}
+	}
+}
+
+// waitTaskFields returns the wait task's fields to validate. The wait can also
+// be an RFC3339 timestamp or a runtime expression, which the SDK's duration
+// rule rejects, so anything that isn't a duration is left for the wait task to
+// check when it's built.
+func waitTaskFields(task *model.WaitTask) waitTask {
+	wait := task.Wait
+	if wait != nil {
+		if _, err := ToDuration(wait); err != nil {
+			wait = &model.Duration{Value: model.DurationInline{}}
+		}
+	}
+
+	return waitTask{TaskBase: task.TaskBase, Wait: wait}
+}
+
+// hostnameValidator performs the standard hostname validation, which is
+// replaced in the main validator
+var hostnameValidator = validator.New()
+
+// validateHostnameOrWorkflowName accepts an RFC 1123 hostname. The run task's
+// workflow name may also be a runtime expression.
+func validateHostnameOrWorkflowName(fl validator.FieldLevel) bool {
+	input, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	if model.IsStrictExpr(input) && fl.StructFieldName() == "Name" {
+		switch fl.Parent().Interface().(type) {
+		case model.RunWorkflow, *model.RunWorkflow:
+			return true
+		}
+	}
+
+	return hostnameValidator.Var(input, "hostname_rfc1123") == nil
+}
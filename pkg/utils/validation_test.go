@@ -0,0 +1,243 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestValidateWaitDuration(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Wait    string
+		Timeout string
+		Valid   bool
+	}{
+		{
+			Name:  "ISO 8601 duration",
+			Wait:  "PT5S",
+			Valid: true,
+		},
+		{
+			Name:  "RFC3339 timestamp",
+			Wait:  "2025-01-02T09:00:00Z",
+			Valid: true,
+		},
+		{
+			Name:  "Runtime expression",
+			Wait:  "${ .input.resumeAt }",
+			Valid: true,
+		},
+		{
+			Name:    "Timestamps only allowed in wait tasks",
+			Wait:    "PT5S",
+			Timeout: "2025-01-02T09:00:00Z",
+		},
+	}
+
+	v, err := utils.NewValidator()
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			timeout := "PT1M"
+			if test.Timeout != "" {
+				timeout = test.Timeout
+			}
+
+			var doc *model.Workflow
+			assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: wait
+  version: 0.0.1
+do:
+  - wait:
+      wait: "`+test.Wait+`"
+      timeout:
+        after: "`+timeout+`"`), &doc))
+
+			res, err := v.ValidateStruct(doc)
+			assert.NoError(t, err)
+
+			if test.Valid {
+				assert.Empty(t, res)
+			} else {
+				assert.NotEmpty(t, res)
+			}
+		})
+	}
+}
+
+func TestValidateRunWorkflowName(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Namespace string
+		Workflow  string
+		Valid     bool
+	}{
+		{
+			Name:      "Hostname",
+			Namespace: "default",
+			Workflow:  "child",
+			Valid:     true,
+		},
+		{
+			Name:      "Runtime expression",
+			Namespace: "default",
+			Workflow:  "${ .input.handler }",
+			Valid:     true,
+		},
+		{
+			Name:      "Invalid name",
+			Namespace: "default",
+			Workflow:  "not a hostname",
+		},
+		{
+			Name:      "Runtime expression only allowed for the name",
+			Namespace: "${ .input.namespace }",
+			Workflow:  "child",
+		},
+	}
+
+	v, err := utils.NewValidator()
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res, err := v.ValidateStruct(&model.RunWorkflow{
+				Namespace: test.Namespace,
+				Name:      test.Workflow,
+				Version:   "0.0.1",
+			})
+			assert.NoError(t, err)
+
+			if test.Valid {
+				assert.Empty(t, res)
+			} else {
+				assert.NotEmpty(t, res)
+			}
+		})
+	}
+}
+
+func TestValidateExpressions(t *testing.T) {
+	var doc *model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: expressions
+  version: 0.0.1
+do:
+  - valid:
+      if: ${ .input.run == true }
+      set:
+        id: ${ uuid }
+  - invalidSet:
+      set:
+        id: ${ .input.id | }
+  - parent:
+      do:
+        - invalidSet:
+            set:
+              nested:
+                id: ${ uuid6 }
+  - invalidHTTP:
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com
+        headers:
+          x-id: ${ .input.id) }`), &doc))
+
+	res := utils.ValidateExpressions(doc)
+
+	keys := make([]string, 0)
+	for _, r := range res {
+		keys = append(keys, r.Key)
+	}
+
+	assert.Equal(t, []string{
+		"invalidHTTP.with.headers.x-id",
+		"invalidSet.set.id",
+		"parent.invalidSet.set.nested.id",
+	}, keys)
+}
+
+func TestValidateFlowDirectives(t *testing.T) {
+	var doc *model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: flow
+  version: 0.0.1
+do:
+  - first:
+      set:
+        hello: world
+      then: third
+  - second:
+      set:
+        hello: world
+      then: missing
+  - third:
+      set:
+        hello: world
+      then: first
+  - check:
+      switch:
+        - sibling:
+            when: ${ .input.sibling }
+            then: last
+        - child:
+            when: ${ .input.child }
+            then: childWorkflow
+        - dangling:
+            when: ${ .input.dangling }
+            then: renamed
+        - finish:
+            then: end
+  - childWorkflow:
+      do:
+        - nested:
+            set:
+              hello: world
+            then: outside
+  - last:
+      set:
+        hello: world`), &doc))
+
+	res := utils.ValidateFlowDirectives(doc)
+
+	keys := make([]string, 0)
+	for _, r := range res {
+		keys = append(keys, r.Key)
+	}
+
+	assert.Equal(t, []string{
+		"check.switch.dangling",
+		"childWorkflow.nested",
+		"second",
+		"third",
+	}, keys)
+	assert.Equal(t, "then directive on task 'second' references task 'missing', which cannot be reached", res[2].Message)
+}
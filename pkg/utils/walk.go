@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// WalkTaskFunc receives every task found by WalkTasks. The path is the dot
+// separated list of keys used to reach the task.
+type WalkTaskFunc func(path string, task *model.TaskItem)
+
+// WalkTasks recursively visits each task in the list, descending into any
+// nested task lists (do, for, fork branches and try/catch)
+func WalkTasks(list *model.TaskList, fn WalkTaskFunc) {
+	walkTasks("", list, fn)
+}
+
+func walkTasks(prefix string, list *model.TaskList, fn WalkTaskFunc) {
+	if list == nil {
+		return
+	}
+
+	for _, item := range *list {
+		path := item.Key
+		if prefix != "" {
+			path = prefix + "." + item.Key
+		}
+
+		fn(path, item)
+
+		for _, child := range childTaskLists(path, item.Task) {
+			walkTasks(child.prefix, child.list, fn)
+		}
+	}
+}
+
+// WalkTaskListFunc receives every task list found by WalkTaskLists. The prefix
+// is the path of the task that owns the list, or empty for the top level.
+type WalkTaskListFunc func(prefix string, list *model.TaskList)
+
+// WalkTaskLists recursively visits the list and each nested task list. This
+// is useful where the siblings of a task are needed.
+func WalkTaskLists(list *model.TaskList, fn WalkTaskListFunc) {
+	walkTaskLists("", list, fn)
+}
+
+func walkTaskLists(prefix string, list *model.TaskList, fn WalkTaskListFunc) {
+	if list == nil {
+		return
+	}
+
+	fn(prefix, list)
+
+	for _, item := range *list {
+		path := item.Key
+		if prefix != "" {
+			path = prefix + "." + item.Key
+		}
+
+		for _, child := range childTaskLists(path, item.Task) {
+			walkTaskLists(child.prefix, child.list, fn)
+		}
+	}
+}
+
+type taskList struct {
+	prefix string
+	list   *model.TaskList
+}
+
+// childTaskLists returns the task lists nested inside the task
+func childTaskLists(path string, task model.Task) []taskList {
+	switch t := task.(type) {
+	case *model.DoTask:
+		return []taskList{{prefix: path, list: t.Do}}
+	case *model.ForTask:
+		return []taskList{{prefix: path, list: t.Do}}
+	case *model.ForkTask:
+		return []taskList{{prefix: path, list: t.Fork.Branches}}
+	case *model.TryTask:
+		lists := []taskList{{prefix: path + ".try", list: t.Try}}
+		if t.Catch != nil {
+			lists = append(lists, taskList{prefix: path + ".catch", list: t.Catch.Do})
+		}
+		return lists
+	default:
+		return nil
+	}
+}
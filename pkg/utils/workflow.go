@@ -21,8 +21,16 @@ import (
 	"strings"
 )
 
+// GenerateChildWorkflowName generates the name a child workflow is registered
+// with, which is unique to the task that runs it
 func GenerateChildWorkflowName(prefix string, prefixes ...string) string {
 	prefixes = append([]string{prefix}, prefixes...)
 
 	return fmt.Sprintf("workflow_%s", strings.Join(prefixes, "_"))
 }
+
+// GenerateChildWorkflowID generates the ID of a child workflow from its
+// parent's ID, so it's the same when the parent is replayed
+func GenerateChildWorkflowID(parentID, prefix, key string) string {
+	return fmt.Sprintf("%s_%s_%s", parentID, prefix, key)
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package zigflow turns a Serverless Workflow document into Temporal workflows.
+//
+// Load a document with LoadFromFile and register it on a worker with
+// NewWorkflow, which is the only way a document is built. The task builders in
+// the tasks package are used by NewWorkflow and aren't intended to be used
+// directly.
+//
+// The rest of the package is for working with a loaded document - Compile
+// graphs its workflows, StartWorkflowOptions and SignalWithStart start them,
+// UpdateSchedules manages its schedules and JSONSchema describes the document.
+package zigflow
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// errorReferenceKey is the key that references an error in use.errors
+const errorReferenceKey = "ref"
+
+// resolveErrorReferences replaces the error references in the raw document
+// with the errors from use.errors. The workflow model has nowhere to keep a
+// reference in a raise's error object or a catch's error filter, so they must
+// be resolved before the document is unmarshaled.
+//
+// A raise.error.ref becomes the spec's string reference, which is resolved by
+// the raise task. A catch.errors.with.ref becomes the type and status of the
+// error, so any error with that type and status is caught. Any other field set
+// on the filter is kept.
+func resolveErrorReferences(data []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		// Let the workflow unmarshal report this
+		return data, nil
+	}
+
+	var definitions map[string]any
+	if use, ok := doc["use"].(map[string]any); ok {
+		definitions, _ = use["errors"].(map[string]any)
+	}
+
+	changed := false
+	resolve := func(ref any) (map[string]any, error) {
+		name, ok := ref.(string)
+		if !ok {
+			return nil, errors.New("error reference must be a string")
+		}
+		definition, ok := definitions[name].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unknown error %s", name)
+		}
+		changed = true
+		return definition, nil
+	}
+
+	var walk func(v any) error
+	walk = func(v any) error {
+		switch obj := v.(type) {
+		case []any:
+			for _, item := range obj {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		case map[string]any:
+			if err := resolveRaiseReference(obj, resolve); err != nil {
+				return err
+			}
+			if err := resolveCatchReference(obj, resolve); err != nil {
+				return err
+			}
+			for _, item := range obj {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	// Tasks are in the do list and in the inline workflows' metadata
+	if err := walk(doc["do"]); err != nil {
+		return nil, err
+	}
+	if document, ok := doc["document"].(map[string]any); ok {
+		if err := walk(document["metadata"]); err != nil {
+			return nil, err
+		}
+	}
+
+	if !changed {
+		return data, nil
+	}
+
+	return json.Marshal(doc)
+}
+
+func resolveRaiseReference(obj map[string]any, resolve func(any) (map[string]any, error)) error {
+	raise, ok := obj["raise"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	raiseErr, ok := raise["error"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	ref, ok := raiseErr[errorReferenceKey]
+	if !ok {
+		return nil
+	}
+
+	if _, err := resolve(ref); err != nil {
+		return fmt.Errorf("invalid raise error: %w", err)
+	}
+	raise["error"] = ref
+
+	return nil
+}
+
+func resolveCatchReference(obj map[string]any, resolve func(any) (map[string]any, error)) error {
+	if _, ok := obj["try"]; !ok {
+		return nil
+	}
+	catch, ok := obj["catch"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	errs, ok := catch["errors"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	filter, ok := errs["with"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	ref, ok := filter[errorReferenceKey]
+	if !ok {
+		return nil
+	}
+
+	definition, err := resolve(ref)
+	if err != nil {
+		return fmt.Errorf("invalid catch error filter: %w", err)
+	}
+
+	delete(filter, errorReferenceKey)
+	for _, key := range []string{"type", "status"} {
+		if _, ok := filter[key]; !ok && definition[key] != nil {
+			filter[key] = definition[key]
+		}
+	}
+
+	return nil
+}
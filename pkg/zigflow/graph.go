@@ -0,0 +1,183 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/tasks"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// WorkflowGraph describes the Temporal workflows generated from a document
+type WorkflowGraph struct {
+	Description string           `json:"description,omitempty"`
+	Workflows   []*GraphWorkflow `json:"workflows"`
+}
+
+type GraphWorkflow struct {
+	Name  string       `json:"name"`
+	Tasks []*GraphTask `json:"tasks"`
+}
+
+type GraphTask struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Description    string   `json:"description,omitempty"`
+	ChildWorkflows []string `json:"childWorkflows,omitempty"`
+}
+
+// RecordingWorker is a worker.Worker that never connects to Temporal. It
+// records the workflows registered by the builders so the generated graph can
+// be inspected.
+type RecordingWorker struct {
+	// Any methods not implemented will panic
+	worker.Worker
+
+	// Registered workflow names, in the order they were registered
+	Workflows []string
+
+	tasks    map[string][]*GraphTask
+	current  []*GraphTask
+	switches map[*GraphTask][]string
+}
+
+func NewRecordingWorker() *RecordingWorker {
+	return &RecordingWorker{
+		Workflows: make([]string, 0),
+		tasks:     map[string][]*GraphTask{},
+		current:   make([]*GraphTask, 0),
+		switches:  map[*GraphTask][]string{},
+	}
+}
+
+func (r *RecordingWorker) BeginTask(workflowName, taskName string, task model.Task) {
+	// The description is validated before the task is built
+	description, _ := metadata.GetDescription(task.GetBase().Metadata)
+
+	t := &GraphTask{
+		Name:        taskName,
+		Type:        tasks.TaskType(task),
+		Description: description,
+	}
+
+	r.tasks[workflowName] = append(r.tasks[workflowName], t)
+	r.current = append(r.current, t)
+
+	if targets := switchTargets(task); len(targets) > 0 {
+		r.switches[t] = targets
+	}
+}
+
+func (r *RecordingWorker) EndTask() {
+	if len(r.current) > 0 {
+		r.current = r.current[:len(r.current)-1]
+	}
+}
+
+func (r *RecordingWorker) RegisterWorkflowWithOptions(_ any, options workflow.RegisterOptions) {
+	r.Workflows = append(r.Workflows, options.Name)
+
+	// Workflows registered while a task is being built are its children
+	if len(r.current) > 0 {
+		parent := r.current[len(r.current)-1]
+		parent.ChildWorkflows = append(parent.ChildWorkflows, options.Name)
+	}
+}
+
+func (r *RecordingWorker) RegisterActivity(_ any) {}
+
+func (r *RecordingWorker) Start() error { return nil }
+
+func (r *RecordingWorker) Run(_ <-chan any) error { return nil }
+
+func (r *RecordingWorker) Stop() {}
+
+// Graph returns the registered workflows and their tasks
+func (r *RecordingWorker) Graph() *WorkflowGraph {
+	graph := &WorkflowGraph{
+		Workflows: make([]*GraphWorkflow, 0),
+	}
+
+	// A switch doesn't build its targets, but runs any that are workflows
+	for t, targets := range r.switches {
+		for _, target := range targets {
+			if slices.Contains(r.Workflows, target) && !slices.Contains(t.ChildWorkflows, target) {
+				t.ChildWorkflows = append(t.ChildWorkflows, target)
+			}
+		}
+	}
+
+	for _, name := range r.Workflows {
+		graph.Workflows = append(graph.Workflows, &GraphWorkflow{
+			Name:  name,
+			Tasks: r.tasks[name],
+		})
+	}
+
+	return graph
+}
+
+// Compile runs the builders against a RecordingWorker and returns the
+// generated workflow graph. This does not connect to Temporal.
+func Compile(doc *model.Workflow) (*WorkflowGraph, error) {
+	recorder := NewRecordingWorker()
+
+	if _, err := NewWorkflow(recorder, doc, map[string]any{}, tasks.ActivityDefaults{}); err != nil {
+		return nil, err
+	}
+
+	description, err := metadata.GetDescription(doc.Document.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document description metadata: %w", err)
+	}
+
+	graph := recorder.Graph()
+	graph.Description = description
+
+	return graph, nil
+}
+
+// switchTargets returns the named "then" targets of a switch task. Those that
+// are registered workflows are run as child workflows.
+func switchTargets(task model.Task) []string {
+	targets := make([]string, 0)
+
+	switchTask, ok := task.(*model.SwitchTask)
+	if !ok {
+		return targets
+	}
+
+	for _, switchItem := range switchTask.Switch {
+		for _, c := range switchItem {
+			if c.Then != nil && !c.Then.IsEnum() && !slices.Contains(targets, c.Then.Value) {
+				targets = append(targets, c.Then.Value)
+			}
+		}
+	}
+
+	slices.Sort(targets)
+
+	return targets
+}
+
+// Ensure the RecordingWorker receives the build events
+var _ tasks.BuildRecorder = &RecordingWorker{}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestCompile(t *testing.T) {
+	var doc *model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: graph
+  version: 0.0.1
+  metadata:
+    description: Builds a graph
+do:
+  - main:
+      do:
+        - start:
+            metadata:
+              description: Says hello
+            set:
+              hello: world
+        - attempt:
+            try:
+              - step:
+                  set:
+                    hello: world
+            catch:
+              do:
+                - recover:
+                    set:
+                      hello: world
+        - check:
+            switch:
+              - other:
+                  when: ${ .input.other }
+                  then: other
+              - finish:
+                  then: end
+  - other:
+      do:
+        - step:
+            set:
+              hello: world`), &doc))
+
+	graph, err := zigflow.Compile(doc)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &zigflow.WorkflowGraph{
+		Description: "Builds a graph",
+		Workflows: []*zigflow.GraphWorkflow{
+			{
+				Name: "workflow_try_attempt",
+				Tasks: []*zigflow.GraphTask{
+					{Name: "step", Type: "set"},
+				},
+			},
+			{
+				Name: "workflow_catch_attempt",
+				Tasks: []*zigflow.GraphTask{
+					{Name: "recover", Type: "set"},
+				},
+			},
+			{
+				Name: "main",
+				Tasks: []*zigflow.GraphTask{
+					{Name: "start", Type: "set", Description: "Says hello"},
+					{Name: "attempt", Type: "try", ChildWorkflows: []string{"workflow_try_attempt", "workflow_catch_attempt"}},
+					{Name: "check", Type: "switch", ChildWorkflows: []string{"other"}},
+				},
+			},
+			{
+				Name: "other",
+				Tasks: []*zigflow.GraphTask{
+					{Name: "step", Type: "set"},
+				},
+			},
+		},
+	}, graph)
+}
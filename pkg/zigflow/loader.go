@@ -39,6 +39,10 @@ func LoadFromFile(file string) (*model.Workflow, error) {
 		return nil, fmt.Errorf("error converting yaml to json: %w", err)
 	}
 
+	if jsonBytes, err = resolveErrorReferences(jsonBytes); err != nil {
+		return nil, fmt.Errorf("error resolving error references: %w", err)
+	}
+
 	var wf *model.Workflow
 	if err := json.Unmarshal(jsonBytes, &wf); err != nil {
 		return nil, fmt.Errorf("error unmarshaling json to workflow: %w", err)
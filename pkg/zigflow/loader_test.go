@@ -92,3 +92,89 @@ do:
 		})
 	}
 }
+
+func TestLoadWorkflowFileErrorReferences(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Raise  string
+		Filter string
+		Status int
+		Error  string
+	}{
+		{
+			Name:   "Resolved references",
+			Raise:  "ref: paymentDeclined",
+			Filter: "ref: paymentDeclined",
+			Status: 402,
+		},
+		{
+			Name:   "Filter fields override the reference",
+			Raise:  "paymentDeclined",
+			Filter: "ref: paymentDeclined\n            status: 403",
+			Status: 403,
+		},
+		{
+			Name:   "Unknown raise reference",
+			Raise:  "ref: missing",
+			Filter: "ref: paymentDeclined",
+			Error:  "invalid raise error: unknown error missing",
+		},
+		{
+			Name:   "Unknown catch reference",
+			Raise:  "ref: paymentDeclined",
+			Filter: "ref: missing",
+			Error:  "invalid catch error filter: unknown error missing",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			filePath := filepath.Join(tmpDir, "zigflow.yaml")
+			err := os.WriteFile(filePath, []byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: test
+  version: 0.0.1
+use:
+  errors:
+    paymentDeclined:
+      type: https://example.com/errors/payment-declined
+      status: 402
+      title: Payment declined
+do:
+  - attempt:
+      try:
+        - pay:
+            raise:
+              error:
+                `+test.Raise+`
+      catch:
+        errors:
+          with:
+            `+test.Filter+`
+        do:
+          - recover:
+              set:
+                declined: true`), 0o600)
+			assert.NoError(t, err)
+
+			workflow, err := zigflow.LoadFromFile(filePath)
+			if test.Error != "" {
+				assert.ErrorContains(t, err, test.Error)
+				assert.Nil(t, workflow)
+				return
+			}
+			assert.NoError(t, err)
+
+			try := (*workflow.Do)[0].AsTryTask()
+			raise := (*try.Try)[0].AsRaiseTask()
+			assert.Equal(t, "paymentDeclined", *raise.Raise.Error.Ref)
+
+			filter := try.Catch.Errors.With
+			assert.Equal(t, "https://example.com/errors/payment-declined", filter.Type)
+			assert.Equal(t, test.Status, filter.Status)
+		})
+	}
+}
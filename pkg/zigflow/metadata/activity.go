@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetLocalActivity returns whether the task's activity runs as a local
+// activity. By default, it runs as a normal activity.
+func GetLocalActivity(m map[string]any) (bool, error) {
+	v, ok := m[MetadataLocalActivity]
+	if !ok {
+		return false, nil
+	}
+
+	local, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("local activity must be a boolean")
+	}
+
+	return local, nil
+}
+
+// GetTimeout returns the timeout metadata, or the default if not set
+func GetTimeout(m map[string]any, defaultTimeout time.Duration) (time.Duration, error) {
+	v, ok := m[MetadataTimeout]
+	if !ok {
+		return defaultTimeout, nil
+	}
+
+	timeoutStr, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("timeout must be a string")
+	}
+
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing timeout to duration: %w", err)
+	}
+
+	return timeout, nil
+}
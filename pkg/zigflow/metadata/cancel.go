@@ -0,0 +1,35 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import "fmt"
+
+// GetCancelSignal returns the name of the signal that cancels a do task. An
+// empty string means that it's not set.
+func GetCancelSignal(m map[string]any) (string, error) {
+	v, ok := m[MetadataCancelSignal]
+	if !ok {
+		return "", nil
+	}
+
+	signal, ok := v.(string)
+	if !ok || signal == "" {
+		return "", fmt.Errorf("cancel signal must be a non-empty string")
+	}
+
+	return signal, nil
+}
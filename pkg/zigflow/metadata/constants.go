@@ -16,10 +16,122 @@
 
 package metadata
 
-const MetadataSearchAttribute string = "searchAttributes"
+const (
+	MetadataCancelSignal          string = "cancelSignal"
+	MetadataDescription           string = "description"
+	MetadataDetails               string = "details"
+	MetadataIdempotencyHeader     string = "idempotencyHeader"
+	MetadataIterationDelay        string = "iterationDelay"
+	MetadataLocalActivity         string = "localActivity"
+	MetadataMaxBodyBytes          string = "maxBodyBytes"
+	MetadataMaxIterations         string = "maxIterations"
+	MetadataMerge                 string = "merge"
+	MetadataMode                  string = "mode"
+	MetadataOutputFormat          string = "outputFormat"
+	MetadataParentClosePolicy     string = "parentClosePolicy"
+	MetadataPriority              string = "priority"
+	MetadataResources             string = "resources"
+	MetadataRetryOn               string = "retryOn"
+	MetadataRetryable             string = "retryable"
+	MetadataSearchAttribute       string = "searchAttributes"
+	MetadataSummary               string = "summary"
+	MetadataTimeout               string = "timeout"
+	MetadataUnset                 string = "unset"
+	MetadataVersion               string = "version"
+	MetadataWhile                 string = "while"
+	MetadataWorkflowID            string = "workflowId"
+	MetadataWorkflowIDReusePolicy string = "workflowIdReusePolicy"
+)
+
+const (
+	MetadataContinueAsNewAfter    string = "continueAsNewAfter"
+	MetadataEnv                   string = "env"
+	MetadataOnFailure             string = "onFailure"
+	MetadataResultEnvelope        string = "resultEnvelope"
+	MetadataRetryPolicy           string = "retryPolicy"
+	MetadataSecrets               string = "secrets"
+	MetadataStartSearchAttributes string = "startSearchAttributes"
+	MetadataTaskQueue             string = "taskQueue"
+	MetadataWorkflowIDPrefix      string = "workflowIdPrefix"
+	MetadataWorkflows             string = "workflows"
+)
 
 const (
 	MetadataScheduleID           string = "scheduleId"
 	MetadataScheduleWorkflowName string = "scheduleWorkflowName"
 	MetadataScheduleInput        string = "scheduleInput"
+	MetadataScheduleEnabled      string = "scheduleEnabled"
+)
+
+// Merge strategies for the set task
+const (
+	MergeShallow string = "shallow"
+	MergeDeep    string = "deep"
 )
+
+// Execution modes for the do task
+const (
+	ModeSequential string = "sequential"
+	ModeParallel   string = "parallel"
+)
+
+// How the stdout of a run task's script or container, or the body of an HTTP
+// call's response, is parsed
+const (
+	OutputFormatAuto string = "auto"
+	OutputFormatJSON string = "json"
+	OutputFormatText string = "text"
+	// Only for HTTP calls
+	OutputFormatBytes       string = "bytes"
+	OutputFormatContentType string = "contentType"
+)
+
+// Recognised document metadata keys. Any new document metadata must be added
+// here or it will be reported as unknown
+var DocumentKeys = []string{
+	MetadataContinueAsNewAfter,
+	MetadataDescription,
+	MetadataEnv,
+	MetadataOnFailure,
+	MetadataResultEnvelope,
+	MetadataRetryPolicy,
+	MetadataScheduleID,
+	MetadataScheduleWorkflowName,
+	MetadataScheduleInput,
+	MetadataScheduleEnabled,
+	MetadataSecrets,
+	MetadataStartSearchAttributes,
+	MetadataTaskQueue,
+	MetadataWorkflowIDPrefix,
+	MetadataWorkflows,
+}
+
+// Recognised task metadata keys. Any new task metadata must be added here or
+// it will be reported as unknown
+var TaskKeys = []string{
+	MetadataCancelSignal,
+	MetadataDescription,
+	MetadataDetails,
+	MetadataIdempotencyHeader,
+	MetadataIterationDelay,
+	MetadataLocalActivity,
+	MetadataMaxBodyBytes,
+	MetadataMaxIterations,
+	MetadataMerge,
+	MetadataMode,
+	MetadataOutputFormat,
+	MetadataParentClosePolicy,
+	MetadataPriority,
+	MetadataResources,
+	MetadataRetryOn,
+	MetadataRetryPolicy,
+	MetadataRetryable,
+	MetadataSearchAttribute,
+	MetadataSummary,
+	MetadataTimeout,
+	MetadataUnset,
+	MetadataVersion,
+	MetadataWhile,
+	MetadataWorkflowID,
+	MetadataWorkflowIDReusePolicy,
+}
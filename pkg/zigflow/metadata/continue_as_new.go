@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+)
+
+// GetContinueAsNewAfter returns the history length after which the workflow
+// continues as new, or 0 if not set
+func GetContinueAsNewAfter(m map[string]any) (int, error) {
+	v, ok := m[MetadataContinueAsNewAfter]
+	if !ok {
+		return 0, nil
+	}
+
+	after, err := getWholeNumber("continue as new after", v)
+	if err != nil {
+		return 0, err
+	}
+
+	if after < 1 {
+		return 0, fmt.Errorf("continue as new after must be at least 1")
+	}
+
+	return int(after), nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// EnvVar declares an envvar the workflow expects, by its name without the
+// prefix. A required envvar must be set, otherwise the default is used if it
+// isn't set.
+type EnvVar struct {
+	Required bool    `json:"required" mapstructure:"required"`
+	Default  *string `json:"default" mapstructure:"default"`
+}
+
+// GetEnv returns the envvars declared in the document, keyed by their name
+func GetEnv(workflow *model.Workflow) (map[string]EnvVar, error) {
+	v, ok := workflow.Document.Metadata[MetadataEnv]
+	if !ok {
+		return map[string]EnvVar{}, nil
+	}
+
+	e, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("env must be an object")
+	}
+
+	// Allow defaults that YAML parses as numbers or booleans
+	env := map[string]EnvVar{}
+	if err := mapstructure.WeakDecode(e, &env); err != nil {
+		return nil, fmt.Errorf("error decoding env: %w", err)
+	}
+
+	for name, envvar := range env {
+		if name == "" {
+			return nil, fmt.Errorf("env name must not be empty")
+		}
+		if envvar.Required && envvar.Default != nil {
+			return nil, fmt.Errorf("env %s must not be required and have a default", name)
+		}
+	}
+
+	return env, nil
+}
+
+// ResolveEnvvars applies the document's declared envvars to those loaded from
+// the environment. Defaults are set for any missing envvars, and it errors if
+// any required envvars are missing. The envvars given aren't changed.
+func ResolveEnvvars(workflow *model.Workflow, envvars map[string]any) (map[string]any, error) {
+	env, err := GetEnv(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := maps.Clone(envvars)
+	if resolved == nil {
+		resolved = map[string]any{}
+	}
+
+	missing := make([]string, 0)
+	for name, envvar := range env {
+		if _, ok := resolved[name]; ok {
+			continue
+		}
+
+		if envvar.Default != nil {
+			resolved[name] = *envvar.Default
+		} else if envvar.Required {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("required env not set: %s", strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
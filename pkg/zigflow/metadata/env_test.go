@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveEnvvars(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Env      any
+		Envvars  map[string]any
+		Expected map[string]any
+		Error    string
+	}{
+		{
+			Name:     "No declarations",
+			Envvars:  map[string]any{"GREETING": "hello"},
+			Expected: map[string]any{"GREETING": "hello"},
+		},
+		{
+			Name: "Required set",
+			Env: map[string]any{
+				"API_URL": map[string]any{"required": true},
+			},
+			Envvars:  map[string]any{"API_URL": "https://example.com"},
+			Expected: map[string]any{"API_URL": "https://example.com"},
+		},
+		{
+			Name: "Missing required",
+			Env: map[string]any{
+				"API_URL": map[string]any{"required": true},
+				"API_KEY": map[string]any{"required": true},
+				"REGION":  map[string]any{"required": true},
+			},
+			Envvars: map[string]any{"REGION": "eu-west-2"},
+			Error:   "required env not set: API_KEY, API_URL",
+		},
+		{
+			Name: "Default applied",
+			Env: map[string]any{
+				"REGION":  map[string]any{"default": "eu-west-2"},
+				"RETRIES": map[string]any{"default": 3},
+			},
+			Envvars: map[string]any{},
+			Expected: map[string]any{
+				"REGION":  "eu-west-2",
+				"RETRIES": "3",
+			},
+		},
+		{
+			Name: "Set value takes precedence over default",
+			Env: map[string]any{
+				"REGION": map[string]any{"default": "eu-west-2"},
+			},
+			Envvars:  map[string]any{"REGION": "us-east-1"},
+			Expected: map[string]any{"REGION": "us-east-1"},
+		},
+		{
+			Name: "Empty default",
+			Env: map[string]any{
+				"SUFFIX": map[string]any{"default": ""},
+			},
+			Expected: map[string]any{"SUFFIX": ""},
+		},
+		{
+			Name: "Required with default",
+			Env: map[string]any{
+				"REGION": map[string]any{"required": true, "default": "eu-west-2"},
+			},
+			Error: "env REGION must not be required and have a default",
+		},
+		{
+			Name:  "Not an object",
+			Env:   []any{"REGION"},
+			Error: "env must be an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := &model.Workflow{
+				Document: model.Document{
+					Name:     "workflow",
+					Metadata: map[string]any{},
+				},
+			}
+			if test.Env != nil {
+				doc.Document.Metadata[metadata.MetadataEnv] = test.Env
+			}
+
+			envvars, err := metadata.ResolveEnvvars(doc, test.Envvars)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, envvars)
+		})
+	}
+}
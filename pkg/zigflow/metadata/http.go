@@ -0,0 +1,110 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+)
+
+// DefaultIdempotencyHeader is the header an HTTP call's idempotency key is sent
+// in, unless overridden by the task's metadata
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// GetIdempotencyHeader returns the header to send the idempotency key in, or
+// the default if not set. An empty string means the key isn't sent.
+func GetIdempotencyHeader(m map[string]any) (string, error) {
+	v, ok := m[MetadataIdempotencyHeader]
+	if !ok {
+		return DefaultIdempotencyHeader, nil
+	}
+
+	header, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("idempotency header must be a string")
+	}
+
+	return header, nil
+}
+
+// GetHTTPOutputFormat returns how the body of an HTTP call's response is
+// parsed, or auto if not set
+func GetHTTPOutputFormat(m map[string]any) (string, error) {
+	v, ok := m[MetadataOutputFormat]
+	if !ok {
+		return OutputFormatAuto, nil
+	}
+
+	switch v {
+	case OutputFormatAuto, OutputFormatJSON, OutputFormatText, OutputFormatBytes, OutputFormatContentType:
+		return v.(string), nil
+	default:
+		return "", fmt.Errorf(
+			"output format must be %s, %s, %s, %s or %s",
+			OutputFormatAuto, OutputFormatJSON, OutputFormatText, OutputFormatBytes, OutputFormatContentType,
+		)
+	}
+}
+
+// GetMaxBodyBytes returns the maximum size of an HTTP response body, or the
+// default if not set. A value of 0 means there is no limit.
+func GetMaxBodyBytes(m map[string]any, defaultMax int64) (int64, error) {
+	v, ok := m[MetadataMaxBodyBytes]
+	if !ok {
+		return defaultMax, nil
+	}
+
+	maxBytes, err := getWholeNumber("max body bytes", v)
+	if err != nil {
+		return 0, err
+	}
+
+	if maxBytes < 0 {
+		return 0, fmt.Errorf("max body bytes must not be negative")
+	}
+
+	return maxBytes, nil
+}
+
+// GetRetryOn returns the HTTP status codes which an HTTP call can be retried on,
+// or nil if not set. When set, any other unsuccessful status is not retried.
+func GetRetryOn(m map[string]any) ([]int, error) {
+	v, ok := m[MetadataRetryOn]
+	if !ok {
+		return nil, nil
+	}
+
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("retry on must be a list of status codes")
+	}
+
+	codes := make([]int, 0, len(list))
+	for _, item := range list {
+		code, err := getWholeNumber("retry on status code", item)
+		if err != nil {
+			return nil, err
+		}
+
+		if code < 300 || code > 599 {
+			return nil, fmt.Errorf("retry on status code %d must be between 300 and 599", code)
+		}
+
+		codes = append(codes, int(code))
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// How unknown metadata keys are handled during validation
+const (
+	UnknownKeysIgnore string = "ignore"
+	UnknownKeysWarn   string = "warn"
+	UnknownKeysError  string = "error"
+)
+
+// ValidateKeys reports any document or task metadata keys that are not in the
+// list of recognised keys. These are most likely typos.
+func ValidateKeys(doc *model.Workflow) []utils.ValidationErrors {
+	vErrs := make([]utils.ValidationErrors, 0)
+
+	vErrs = append(vErrs, findUnknownKeys("document", doc.Document.Metadata, DocumentKeys)...)
+
+	utils.WalkTasks(doc.Do, func(path string, task *model.TaskItem) {
+		vErrs = append(vErrs, findUnknownKeys(path, task.GetBase().Metadata, TaskKeys)...)
+	})
+
+	// Invalid inline workflows are reported when they're built
+	workflows, _ := GetWorkflows(doc)
+	for _, name := range slices.Sorted(maps.Keys(workflows)) {
+		utils.WalkTasks(workflows[name], func(path string, task *model.TaskItem) {
+			vErrs = append(vErrs, findUnknownKeys(MetadataWorkflows+"."+name+"."+path, task.GetBase().Metadata, TaskKeys)...)
+		})
+	}
+
+	return vErrs
+}
+
+func findUnknownKeys(path string, data map[string]any, known []string) []utils.ValidationErrors {
+	vErrs := make([]utils.ValidationErrors, 0)
+
+	// Sort the keys so the errors are reported in a consistent order
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		if !slices.Contains(known, key) {
+			vErrs = append(vErrs, utils.ValidationErrors{
+				Key:     fmt.Sprintf("%s.metadata.%s", path, key),
+				Message: fmt.Sprintf("unknown metadata key: %s", key),
+			})
+		}
+	}
+
+	return vErrs
+}
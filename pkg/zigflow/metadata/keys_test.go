@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKeys(t *testing.T) {
+	tests := []struct {
+		Name             string
+		DocumentMetadata map[string]any
+		TaskMetadata     map[string]any
+		Expected         []utils.ValidationErrors
+	}{
+		{
+			Name: "Known keys",
+			DocumentMetadata: map[string]any{
+				metadata.MetadataScheduleID: "some-id",
+			},
+			TaskMetadata: map[string]any{
+				metadata.MetadataSearchAttribute: map[string]any{},
+			},
+			Expected: []utils.ValidationErrors{},
+		},
+		{
+			Name: "Unknown keys",
+			DocumentMetadata: map[string]any{
+				"scheduleID": "some-id",
+			},
+			TaskMetadata: map[string]any{
+				"searchAttribute": map[string]any{},
+			},
+			Expected: []utils.ValidationErrors{
+				{
+					Key:     "document.metadata.scheduleID",
+					Message: "unknown metadata key: scheduleID",
+				},
+				{
+					Key:     "parent.child.metadata.searchAttribute",
+					Message: "unknown metadata key: searchAttribute",
+				},
+			},
+		},
+		{
+			Name: "Inline workflow keys",
+			DocumentMetadata: map[string]any{
+				metadata.MetadataWorkflows: map[string]any{
+					"greet": []any{
+						map[string]any{
+							"hello": map[string]any{
+								"metadata": map[string]any{"summry": "Say hello"},
+								"set":      map[string]any{"hello": "world"},
+							},
+						},
+					},
+				},
+			},
+			Expected: []utils.ValidationErrors{
+				{
+					Key:     "workflows.greet.hello.metadata.summry",
+					Message: "unknown metadata key: summry",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := &model.Workflow{
+				Document: model.Document{
+					Metadata: test.DocumentMetadata,
+				},
+				Do: &model.TaskList{
+					{
+						Key: "parent",
+						Task: &model.DoTask{
+							Do: &model.TaskList{
+								{
+									Key: "child",
+									Task: &model.SetTask{
+										TaskBase: model.TaskBase{
+											Metadata: test.TaskMetadata,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			assert.Equal(t, test.Expected, metadata.ValidateKeys(doc))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// DefaultMaxIterations stops a loop that never ends
+const DefaultMaxIterations = 100
+
+// GetWhile returns the runtime expression that repeats a do task while it's
+// true. An empty string means that it's not a loop.
+func GetWhile(m map[string]any) (string, error) {
+	v, ok := m[MetadataWhile]
+	if !ok {
+		return "", nil
+	}
+
+	while, ok := v.(string)
+	if !ok || !model.IsStrictExpr(while) {
+		return "", fmt.Errorf("while must be a runtime expression")
+	}
+
+	return while, nil
+}
+
+// GetMaxIterations returns the most times a loop can run, or the default if
+// not set
+func GetMaxIterations(m map[string]any) (int, error) {
+	v, ok := m[MetadataMaxIterations]
+	if !ok {
+		return DefaultMaxIterations, nil
+	}
+
+	maxIterations, err := getWholeNumber("max iterations", v)
+	if err != nil {
+		return 0, err
+	}
+
+	if maxIterations < 1 {
+		return 0, fmt.Errorf("max iterations must be at least 1")
+	}
+	if maxIterations > math.MaxInt32 {
+		return 0, fmt.Errorf("max iterations must be at most %d", math.MaxInt32)
+	}
+
+	return int(maxIterations), nil
+}
+
+// GetIterationDelay returns how long a loop waits between iterations, or 0 if
+// not set
+func GetIterationDelay(m map[string]any) (time.Duration, error) {
+	v, ok := m[MetadataIterationDelay]
+	if !ok {
+		return 0, nil
+	}
+
+	delayStr, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("iteration delay must be a string")
+	}
+
+	delay, err := time.ParseDuration(delayStr)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing iteration delay to duration: %w", err)
+	}
+	if delay < 0 {
+		return 0, fmt.Errorf("iteration delay must not be negative")
+	}
+
+	return delay, nil
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import "fmt"
+
+// GetMode returns how a do task runs its tasks, or sequential if not set
+func GetMode(m map[string]any) (string, error) {
+	v, ok := m[MetadataMode]
+	if !ok {
+		return ModeSequential, nil
+	}
+
+	switch v {
+	case ModeSequential, ModeParallel:
+		return v.(string), nil
+	default:
+		return "", fmt.Errorf("mode must be %s or %s", ModeSequential, ModeParallel)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWholeNumbers(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Get      func(v any) (any, error)
+		Value    any
+		Expected any
+		Error    string
+	}{
+		{
+			Name: "Priority from an int",
+			Get: func(v any) (any, error) {
+				return metadata.GetPriority(map[string]any{metadata.MetadataPriority: v})
+			},
+			Value:    2,
+			Expected: 2,
+		},
+		{
+			Name: "Priority from JSON",
+			Get: func(v any) (any, error) {
+				return metadata.GetPriority(map[string]any{metadata.MetadataPriority: v})
+			},
+			Value:    float64(3),
+			Expected: 3,
+		},
+		{
+			Name: "Max iterations fraction",
+			Get: func(v any) (any, error) {
+				return metadata.GetMaxIterations(map[string]any{metadata.MetadataMaxIterations: v})
+			},
+			Value: 1.5,
+			Error: "max iterations must be a whole number",
+		},
+		{
+			Name: "Max iterations too high",
+			Get: func(v any) (any, error) {
+				return metadata.GetMaxIterations(map[string]any{metadata.MetadataMaxIterations: v})
+			},
+			Value: float64(1 << 40),
+			Error: "max iterations must be at most 2147483647",
+		},
+		{
+			Name: "Continue as new after from JSON",
+			Get: func(v any) (any, error) {
+				return metadata.GetContinueAsNewAfter(map[string]any{metadata.MetadataContinueAsNewAfter: v})
+			},
+			Value:    float64(500),
+			Expected: 500,
+		},
+		{
+			Name: "Continue as new after fraction",
+			Get: func(v any) (any, error) {
+				return metadata.GetContinueAsNewAfter(map[string]any{metadata.MetadataContinueAsNewAfter: v})
+			},
+			Value: 100.5,
+			Error: "continue as new after must be a whole number",
+		},
+		{
+			Name: "Continue as new after not positive",
+			Get: func(v any) (any, error) {
+				return metadata.GetContinueAsNewAfter(map[string]any{metadata.MetadataContinueAsNewAfter: v})
+			},
+			Value: 0,
+			Error: "continue as new after must be at least 1",
+		},
+		{
+			Name: "Max body bytes from an int64",
+			Get: func(v any) (any, error) {
+				return metadata.GetMaxBodyBytes(map[string]any{metadata.MetadataMaxBodyBytes: v}, 0)
+			},
+			Value:    int64(1024),
+			Expected: int64(1024),
+		},
+		{
+			Name: "Max body bytes too large",
+			Get: func(v any) (any, error) {
+				return metadata.GetMaxBodyBytes(map[string]any{metadata.MetadataMaxBodyBytes: v}, 0)
+			},
+			Value: 1e20,
+			Error: "max body bytes must be a whole number",
+		},
+		{
+			Name: "Retry on status codes",
+			Get: func(v any) (any, error) {
+				return metadata.GetRetryOn(map[string]any{metadata.MetadataRetryOn: v})
+			},
+			Value:    []any{429, float64(503)},
+			Expected: []int{429, 503},
+		},
+		{
+			Name: "Retry on status code string",
+			Get: func(v any) (any, error) {
+				return metadata.GetRetryOn(map[string]any{metadata.MetadataRetryOn: v})
+			},
+			Value: []any{"503"},
+			Error: "retry on status code must be a number",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res, err := test.Get(test.Value)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, res)
+		})
+	}
+}
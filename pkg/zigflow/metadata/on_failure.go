@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// GetOnFailure returns the HTTP call to make when the workflow fails, or nil
+// if not set. This is defined in the same way as a call task.
+func GetOnFailure(workflow *model.Workflow) (*model.CallHTTP, error) {
+	v, ok := workflow.Document.Metadata[MetadataOnFailure]
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := json.Marshal([]map[string]any{
+		{MetadataOnFailure: v},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error converting on failure to json: %w", err)
+	}
+
+	var tasks model.TaskList
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("invalid on failure task: %w", err)
+	}
+
+	task := tasks[0].AsCallHTTPTask()
+	if task == nil {
+		return nil, fmt.Errorf("on failure must be an http call")
+	}
+
+	return task, nil
+}
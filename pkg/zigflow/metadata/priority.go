@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+)
+
+// The range of priorities in Temporal's default server configuration. A lower
+// number is a higher priority.
+const (
+	MinPriority = 1
+	MaxPriority = 5
+)
+
+// GetPriority returns the task's priority, or 0 if not set. A task without a
+// priority inherits it from its workflow.
+func GetPriority(m map[string]any) (int, error) {
+	v, ok := m[MetadataPriority]
+	if !ok {
+		return 0, nil
+	}
+
+	priority, err := getWholeNumber("priority", v)
+	if err != nil {
+		return 0, err
+	}
+
+	if priority < MinPriority || priority > MaxPriority {
+		return 0, fmt.Errorf("priority must be between %d and %d", MinPriority, MaxPriority)
+	}
+
+	return int(priority), nil
+}
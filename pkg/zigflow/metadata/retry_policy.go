@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"go.temporal.io/sdk/temporal"
+)
+
+// RetryPolicy is the retry policy for activities. The intervals are Go
+// durations. Anything not set uses Temporal's default.
+type RetryPolicy struct {
+	InitialInterval        string   `json:"initialInterval" mapstructure:"initialInterval"`
+	BackoffCoefficient     float64  `json:"backoffCoefficient" mapstructure:"backoffCoefficient"`
+	MaximumInterval        string   `json:"maximumInterval" mapstructure:"maximumInterval"`
+	MaximumAttempts        int      `json:"maximumAttempts" mapstructure:"maximumAttempts"`
+	NonRetryableErrorTypes []string `json:"nonRetryableErrorTypes" mapstructure:"nonRetryableErrorTypes"`
+}
+
+// GetRetryPolicy returns the activity retry policy, or nil if not set
+func GetRetryPolicy(m map[string]any) (*temporal.RetryPolicy, error) {
+	v, ok := m[MetadataRetryPolicy]
+	if !ok {
+		return nil, nil
+	}
+
+	e, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("retry policy must be an object")
+	}
+
+	var policy RetryPolicy
+	if err := mapstructure.Decode(e, &policy); err != nil {
+		return nil, fmt.Errorf("error decoding retry policy: %w", err)
+	}
+
+	initialInterval, err := parseRetryInterval("initialInterval", policy.InitialInterval)
+	if err != nil {
+		return nil, err
+	}
+	maximumInterval, err := parseRetryInterval("maximumInterval", policy.MaximumInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	if maximumInterval > 0 && maximumInterval < initialInterval {
+		return nil, fmt.Errorf("retry policy maximumInterval must not be less than initialInterval")
+	}
+	if policy.BackoffCoefficient != 0 && policy.BackoffCoefficient < 1 {
+		return nil, fmt.Errorf("retry policy backoffCoefficient must be at least 1")
+	}
+	if policy.MaximumAttempts < 0 || policy.MaximumAttempts > math.MaxInt32 {
+		return nil, fmt.Errorf("retry policy maximumAttempts must be between 0 and %d", math.MaxInt32)
+	}
+
+	return &temporal.RetryPolicy{
+		InitialInterval:        initialInterval,
+		BackoffCoefficient:     policy.BackoffCoefficient,
+		MaximumInterval:        maximumInterval,
+		MaximumAttempts:        int32(policy.MaximumAttempts),
+		NonRetryableErrorTypes: policy.NonRetryableErrorTypes,
+	}, nil
+}
+
+func parseRetryInterval(name, v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing retry policy %s to duration: %w", name, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("retry policy %s must be positive", name)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import "fmt"
+
+// GetRetryable returns whether the error raised by the task can be retried.
+// By default, errors are retryable.
+func GetRetryable(m map[string]any) (bool, error) {
+	v, ok := m[MetadataRetryable]
+	if !ok {
+		return true, nil
+	}
+
+	retryable, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("retryable must be a boolean")
+	}
+
+	return retryable, nil
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// Docker's memory format, which is a number with an optional unit
+var memoryPattern = regexp.MustCompile(`^[1-9][0-9]*[bkmgBKMG]?$`)
+
+// Resources are the limits of a container run by a run task. Anything not set
+// isn't limited.
+type Resources struct {
+	CPUs   string `json:"cpus" mapstructure:"cpus"`
+	Memory string `json:"memory" mapstructure:"memory"`
+}
+
+// GetOutputFormat returns how the stdout of a run task's script or container
+// is parsed, or auto if not set
+func GetOutputFormat(m map[string]any) (string, error) {
+	v, ok := m[MetadataOutputFormat]
+	if !ok {
+		return OutputFormatAuto, nil
+	}
+
+	switch v {
+	case OutputFormatAuto, OutputFormatJSON, OutputFormatText:
+		return v.(string), nil
+	default:
+		return "", fmt.Errorf("output format must be %s, %s or %s", OutputFormatAuto, OutputFormatJSON, OutputFormatText)
+	}
+}
+
+// GetResources returns the resource limits of a run task's container, or nil
+// if not set
+func GetResources(m map[string]any) (*Resources, error) {
+	v, ok := m[MetadataResources]
+	if !ok {
+		return nil, nil
+	}
+
+	e, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("resources must be an object")
+	}
+
+	var resources Resources
+	if err := mapstructure.Decode(e, &resources); err != nil {
+		return nil, fmt.Errorf("error decoding resources: %w", err)
+	}
+
+	if resources.CPUs != "" {
+		cpus, err := strconv.ParseFloat(resources.CPUs, 64)
+		if err != nil || cpus <= 0 {
+			return nil, fmt.Errorf("resources cpus must be a positive number")
+		}
+	}
+	if resources.Memory != "" && !memoryPattern.MatchString(resources.Memory) {
+		return nil, fmt.Errorf("resources memory must be a number with an optional unit of b, k, m or g")
+	}
+
+	return &resources, nil
+}
@@ -18,6 +18,7 @@ package metadata
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
@@ -27,6 +28,8 @@ type ScheduleInfo struct {
 	ID           string
 	WorkflowName string
 	Input        []any
+	// Whether the schedule should exist
+	Enabled bool
 }
 
 func GetScheduleInfo(workflow *model.Workflow, envvars map[string]any) (*ScheduleInfo, error) {
@@ -72,9 +75,46 @@ func GetScheduleInfo(workflow *model.Workflow, envvars map[string]any) (*Schedul
 		return nil, fmt.Errorf("error interpolating input for schedules: %w", err)
 	}
 
+	enabled, err := getScheduleEnabled(workflow, state)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ScheduleInfo{
 		ID:           scheduleID,
 		WorkflowName: workflowName,
 		Input:        parsedInput["input"].([]any),
+		Enabled:      enabled,
 	}, nil
 }
+
+// getScheduleEnabled returns whether the schedule is enabled, which defaults to
+// true. A runtime expression is evaluated against the envvars, which are
+// strings, so "true" and "false" are accepted.
+func getScheduleEnabled(workflow *model.Workflow, state *utils.State) (bool, error) {
+	v, ok := workflow.Document.Metadata[MetadataScheduleEnabled]
+	if !ok {
+		return true, nil
+	}
+
+	if s, ok := v.(string); ok {
+		res, err := utils.EvaluateString(s, state)
+		if err != nil {
+			return false, fmt.Errorf("error interpolating schedule enabled: %w", err)
+		}
+		v = res
+	}
+
+	switch enabled := v.(type) {
+	case bool:
+		return enabled, nil
+	case string:
+		b, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return false, fmt.Errorf("schedule enabled must be a boolean")
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("schedule enabled must be a boolean")
+	}
+}
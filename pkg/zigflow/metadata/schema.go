@@ -0,0 +1,338 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Types accepted by a search attribute
+var searchAttributeTypes = []string{
+	SearchAttributeBooleanType,
+	SearchAttributeDateTimeType,
+	SearchAttributeDoubleType,
+	SearchAttributeIntType,
+	SearchAttributeKeywordListType,
+	SearchAttributeKeywordType,
+	SearchAttributeTextType,
+}
+
+// DocumentSchema returns the JSON schema for the document metadata
+func DocumentSchema() map[string]any {
+	retryPolicy := SchemaFor(RetryPolicy{})
+	retryPolicy["description"] = "Default retry policy for the workflow's activities. The intervals are Go durations"
+
+	searchAttribute := SchemaFor(SearchAttribute{})
+	searchAttribute["properties"].(map[string]any)["type"].(map[string]any)["enum"] = searchAttributeTypes
+
+	envvar := SchemaFor(EnvVar{})
+	envvar["properties"].(map[string]any)["default"] = map[string]any{
+		"type": []string{"string", "number", "boolean"},
+	}
+
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			MetadataContinueAsNewAfter: map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"description": "Continue as new once the workflow history reaches this many events",
+			},
+			MetadataDescription: map[string]any{
+				"type":        "string",
+				"description": "What the workflow does. This is its summary in the Temporal UI and its details when it starts",
+			},
+			MetadataEnv: map[string]any{
+				"type":                 "object",
+				"additionalProperties": envvar,
+				"description":          "Envvars the workflow expects, keyed by their name without the prefix. Checked when the worker starts",
+			},
+			MetadataOnFailure: map[string]any{
+				"type":        "object",
+				"description": "HTTP call made when the workflow fails, before the error is returned",
+			},
+			MetadataResultEnvelope: map[string]any{
+				"type":        "boolean",
+				"description": "Return the workflow result inside an envelope",
+			},
+			MetadataRetryPolicy: retryPolicy,
+			MetadataScheduleID: map[string]any{
+				"type":        "string",
+				"description": "ID of the Temporal schedule",
+			},
+			MetadataScheduleWorkflowName: map[string]any{
+				"type":        "string",
+				"description": "Name of the workflow the schedule triggers",
+			},
+			MetadataScheduleInput: map[string]any{
+				"type":        "array",
+				"description": "Input passed to the scheduled workflow",
+			},
+			MetadataScheduleEnabled: map[string]any{
+				"type": []string{"boolean", "string"},
+				"description": "Whether the schedule is created. A disabled schedule is deleted. " +
+					"This may be a runtime expression, which can only use the envvars. Defaults to true",
+			},
+			MetadataSecrets: map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string", "minLength": 1},
+				"description": "Envvars and data keys whose values are masked in the logs and HTTP calls' responses",
+			},
+			MetadataStartSearchAttributes: map[string]any{
+				"type":                 "object",
+				"additionalProperties": searchAttribute,
+				"description":          "Search attributes the workflow is started with by the start command and the schedule, keyed by the attribute name",
+			},
+			MetadataTaskQueue: map[string]any{
+				"type":        "string",
+				"minLength":   1,
+				"description": "Task queue the workflow is run on. Defaults to the document name",
+			},
+			MetadataWorkflowIDPrefix: map[string]any{
+				"type":        "string",
+				"description": "Prefix of the IDs of the workflows started by the start command and the schedule",
+			},
+			MetadataWorkflows: map[string]any{
+				"type": "object",
+				"additionalProperties": map[string]any{
+					"type":     "array",
+					"minItems": 1,
+					"items":    map[string]any{"type": "object"},
+				},
+				"description": "Reusable task lists, keyed by the workflow name. Each is registered as a workflow that a run task can start",
+			},
+		},
+	}
+}
+
+// TaskSchema returns the JSON schema for the task metadata
+func TaskSchema() map[string]any {
+	retryPolicy := SchemaFor(RetryPolicy{})
+	retryPolicy["description"] = "Retry policy for the task's activities, in place of the document's. The intervals are Go durations"
+
+	searchAttribute := SchemaFor(SearchAttribute{})
+	searchAttribute["properties"].(map[string]any)["type"].(map[string]any)["enum"] = searchAttributeTypes
+
+	properties := map[string]any{
+		MetadataCancelSignal: map[string]any{
+			"type":        "string",
+			"minLength":   1,
+			"description": "Signal that cancels a do task's tasks, which then fails with a Canceled error",
+		},
+		MetadataDescription: map[string]any{
+			"type":        "string",
+			"description": "What the task does. A do task started as a workflow uses this in place of the document's description",
+		},
+		MetadataDetails: map[string]any{
+			"type":        "string",
+			"description": "Details shown in the Temporal UI while the task runs. This may be a runtime expression",
+		},
+		MetadataIterationDelay: map[string]any{
+			"type":        "string",
+			"description": "How long a do task that loops waits between iterations, as a Go duration",
+		},
+		MetadataLocalActivity: map[string]any{
+			"type":        "boolean",
+			"description": "Run a call task as a local activity, which is faster for short calls but can't heartbeat",
+		},
+		MetadataMaxIterations: map[string]any{
+			"type":        "integer",
+			"minimum":     1,
+			"description": "Most times a do task loops before it fails. Defaults to " + strconv.Itoa(DefaultMaxIterations),
+		},
+		MetadataMerge: map[string]any{
+			"type":        "string",
+			"enum":        []string{MergeShallow, MergeDeep},
+			"description": "How the set task merges into the existing data",
+		},
+		MetadataMode: map[string]any{
+			"type":        "string",
+			"enum":        []string{ModeSequential, ModeParallel},
+			"description": "Whether a do task runs its tasks one after another or in parallel. Defaults to sequential",
+		},
+		MetadataPriority: map[string]any{
+			"type":        "integer",
+			"minimum":     MinPriority,
+			"maximum":     MaxPriority,
+			"description": "Priority of the task's activities and child workflows, where 1 is the highest",
+		},
+		MetadataRetryPolicy: retryPolicy,
+		MetadataRetryable: map[string]any{
+			"type":        "boolean",
+			"description": "Whether the error thrown by a raise task can be retried. Defaults to true",
+		},
+		MetadataSearchAttribute: map[string]any{
+			"type":                 "object",
+			"additionalProperties": searchAttribute,
+			"description":          "Search attributes to upsert, keyed by the attribute name",
+		},
+		MetadataSummary: map[string]any{
+			"type": "string",
+			"description": "Summary of the task's activities and child workflows in the Temporal UI, in place of the task name. " +
+				"This may be a runtime expression",
+		},
+		MetadataTimeout: map[string]any{
+			"type":        "string",
+			"description": "How long the task's activities run for, a listen task waits, a local activity runs or a Nexus operation runs, as a Go duration",
+		},
+		MetadataUnset: map[string]any{
+			"oneOf": []any{
+				map[string]any{
+					"type":  "array",
+					"items": map[string]any{"type": "string", "minLength": 1},
+				},
+				map[string]any{"type": "boolean"},
+			},
+			"description": "Data keys a set task removes from the state, or true to remove all the data",
+		},
+		MetadataVersion: map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "string"},
+				SchemaFor(Version{}),
+			},
+			"description": "Workflow versioning for the task, either the change ID or the full version",
+		},
+		MetadataWhile: map[string]any{
+			"type": "string",
+			"description": "Runtime expression that repeats a do task while it's true. " +
+				"It's checked after each iteration, with the iteration's output as .result",
+		},
+	}
+	maps.Copy(properties, httpTaskSchema())
+	maps.Copy(properties, runTaskSchema())
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+// httpTaskSchema returns the JSON schema for the HTTP call's metadata
+func httpTaskSchema() map[string]any {
+	return map[string]any{
+		MetadataIdempotencyHeader: map[string]any{
+			"type": "string",
+			"description": "Header to send an HTTP call's idempotency key in, which is the same for every retry. " +
+				"Defaults to " + DefaultIdempotencyHeader + ". Set to an empty string to disable",
+		},
+		MetadataMaxBodyBytes: map[string]any{
+			"type":        "integer",
+			"minimum":     0,
+			"description": "Maximum size of an HTTP response body, in bytes. Set to 0 for no limit",
+		},
+		MetadataRetryOn: map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "integer", "minimum": 300, "maximum": 599},
+			"description": "HTTP status codes an HTTP call is retried on. Any other unsuccessful status isn't retried",
+		},
+	}
+}
+
+// runTaskSchema returns the JSON schema for the run task's metadata
+func runTaskSchema() map[string]any {
+	resources := SchemaFor(Resources{})
+	resources["description"] = "Resource limits of a run task's container, in Docker's format"
+
+	return map[string]any{
+		MetadataOutputFormat: map[string]any{
+			"type": "string",
+			"enum": []string{OutputFormatAuto, OutputFormatJSON, OutputFormatText, OutputFormatBytes, OutputFormatContentType},
+			"description": "How the stdout of a run task's script or container, or an HTTP call's response body, is parsed. " +
+				"Defaults to auto, which parses it if it's JSON. Only HTTP calls support bytes, which is base64 encoded, " +
+				"and contentType, which uses the response's Content-Type",
+		},
+		MetadataParentClosePolicy: map[string]any{
+			"type":        "string",
+			"description": "What happens to the child workflow started by a run task when the parent closes - abandon, request-cancel or terminate",
+		},
+		MetadataResources: resources,
+		MetadataWorkflowID: map[string]any{
+			"type":        "string",
+			"description": "ID of the child workflow started by a run task",
+		},
+		MetadataWorkflowIDReusePolicy: map[string]any{
+			"type":        "string",
+			"description": "Reuse policy of the child workflow started by a run task",
+		},
+	}
+}
+
+// SchemaFor generates a JSON schema from the value's type using the JSON tags
+func SchemaFor(v any) map[string]any {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+func schemaForType(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaForType(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem())}
+	case reflect.Struct:
+		return schemaForStruct(t)
+	default:
+		// Interfaces can be anything
+		return map[string]any{}
+	}
+}
+
+func schemaForStruct(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		properties[name] = schemaForType(field.Type)
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+}
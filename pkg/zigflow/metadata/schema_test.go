@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaCoversKeys(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Keys   []string
+		Schema map[string]any
+	}{
+		{
+			Name:   "document",
+			Keys:   metadata.DocumentKeys,
+			Schema: metadata.DocumentSchema(),
+		},
+		{
+			Name:   "task",
+			Keys:   metadata.TaskKeys,
+			Schema: metadata.TaskSchema(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			properties := test.Schema["properties"].(map[string]any)
+
+			assert.Len(t, properties, len(test.Keys))
+			for _, k := range test.Keys {
+				assert.Contains(t, properties, k)
+			}
+		})
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	type nested struct {
+		Name    string         `json:"name"`
+		Ignored string         `json:"-"`
+		Tags    []string       `json:"tags,omitempty"`
+		Labels  map[string]int `json:"labels"`
+		Any     any
+		private bool
+	}
+
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"tags": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string"},
+			},
+			"labels": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "integer"},
+			},
+			"Any": map[string]any{},
+		},
+	}, metadata.SchemaFor(&nested{private: true}))
+
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"changeId":     map[string]any{"type": "string"},
+			"minSupported": map[string]any{"type": "integer"},
+			"maxSupported": map[string]any{"type": "integer"},
+			"replaced":     map[string]any{"type": "boolean"},
+		},
+	}, metadata.SchemaFor(metadata.Version{}))
+}
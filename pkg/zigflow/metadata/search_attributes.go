@@ -17,18 +17,32 @@
 package metadata
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-viper/mapstructure/v2"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/api/operatorservice/v1"
+	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 )
 
 var ErrInvalidType = fmt.Errorf("invalid type")
 
+// SearchAttributeDedupeChangeID is the workflow version change that stopped
+// unchanged search attributes being upserted
+const SearchAttributeDedupeChangeID = "zigflow-search-attribute-dedupe"
+
+// SearchAttributeRegistrationChangeID is the workflow version change that
+// always checked the registered search attributes in a side effect
+const SearchAttributeRegistrationChangeID = "zigflow-search-attribute-registration"
+
 const (
 	SearchAttributeDateTimeType    string = "datetime"
 	SearchAttributeKeywordListType string = "keywordlist"
@@ -40,8 +54,62 @@ const (
 )
 
 type SearchAttribute struct {
-	Type  string `json:"type"`
+	Type  string `json:"type" validate:"required,oneofci=datetime keywordlist text keyword int double bool"`
 	Value any    `json:"value"` // If nil then the value is unset
+	// Skip the attribute, rather than failing, if it's not registered in the
+	// namespace
+	IgnoreIfUnregistered bool `json:"ignoreIfUnregistered,omitempty"`
+}
+
+var (
+	// Search attributes registered in the namespace. If nil, the registered
+	// attributes are unknown and nothing is skipped.
+	registeredSearchAttributes map[string]bool
+	// Skip all unregistered search attributes
+	lenientSearchAttributes bool
+)
+
+// SetRegisteredSearchAttributes sets the search attributes registered in the
+// namespace, which are used to skip unregistered attributes. A nil slice
+// means the registered attributes are unknown.
+func SetRegisteredSearchAttributes(keys []string) {
+	if keys == nil {
+		registeredSearchAttributes = nil
+		return
+	}
+
+	registeredSearchAttributes = make(map[string]bool, len(keys))
+	for _, k := range keys {
+		registeredSearchAttributes[k] = true
+	}
+}
+
+// SetLenientSearchAttributes skips all unregistered search attributes, as if
+// each one was set to ignoreIfUnregistered
+func SetLenientSearchAttributes(lenient bool) {
+	lenientSearchAttributes = lenient
+}
+
+// ListRegisteredSearchAttributes returns the names of the system and custom
+// search attributes registered in the namespace
+func ListRegisteredSearchAttributes(ctx context.Context, c client.Client, namespace string) ([]string, error) {
+	res, err := c.OperatorService().ListSearchAttributes(ctx, &operatorservice.ListSearchAttributesRequest{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing search attributes: %w", err)
+	}
+
+	keys := make([]string, 0, len(res.GetSystemAttributes())+len(res.GetCustomAttributes()))
+	for k := range res.GetSystemAttributes() {
+		keys = append(keys, k)
+	}
+	for k := range res.GetCustomAttributes() {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys, nil
 }
 
 func (v *SearchAttribute) newBooleanUpdate(key string) (temporal.SearchAttributeUpdate, error) {
@@ -145,28 +213,55 @@ func (v *SearchAttribute) newIntegerUpdate(key string) (temporal.SearchAttribute
 	return s.ValueSet(val), nil
 }
 
-func (v *SearchAttribute) newKeywordListUpdate(key string) temporal.SearchAttributeUpdate {
+func (v *SearchAttribute) newKeywordListUpdate(key string) (temporal.SearchAttributeUpdate, error) {
 	s := temporal.NewSearchAttributeKeyKeywordList(key)
 	if v.Value == nil {
-		return s.ValueUnset()
+		return s.ValueUnset(), nil
+	}
+
+	switch e := v.Value.(type) {
+	case []string:
+		return s.ValueSet(e), nil
+	case []any:
+		// Lists parsed from the document aren't typed
+		val := make([]string, 0, len(e))
+		for _, i := range e {
+			str, ok := i.(string)
+			if !ok {
+				return nil, ErrInvalidType
+			}
+			val = append(val, str)
+		}
+		return s.ValueSet(val), nil
+	default:
+		return nil, ErrInvalidType
 	}
-	return s.ValueSet(v.Value.([]string))
 }
 
-func (v *SearchAttribute) newKeywordUpdate(key string) temporal.SearchAttributeUpdate {
+func (v *SearchAttribute) newKeywordUpdate(key string) (temporal.SearchAttributeUpdate, error) {
 	s := temporal.NewSearchAttributeKeyKeyword(key)
 	if v.Value == nil {
-		return s.ValueUnset()
+		return s.ValueUnset(), nil
 	}
-	return s.ValueSet(v.Value.(string))
+
+	val, ok := v.Value.(string)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	return s.ValueSet(val), nil
 }
 
-func (v *SearchAttribute) newTextUpdate(key string) temporal.SearchAttributeUpdate {
+func (v *SearchAttribute) newTextUpdate(key string) (temporal.SearchAttributeUpdate, error) {
 	s := temporal.NewSearchAttributeKeyString(key)
 	if v.Value == nil {
-		return s.ValueUnset()
+		return s.ValueUnset(), nil
 	}
-	return s.ValueSet(v.Value.(string))
+
+	val, ok := v.Value.(string)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	return s.ValueSet(val), nil
 }
 
 // Sets by type. See the Temporal documentation for what these all mean
@@ -191,15 +286,15 @@ func (v *SearchAttribute) setAttribute(key string) (temporal.SearchAttributeUpda
 
 	case SearchAttributeKeywordType:
 		// Keyword
-		return v.newKeywordUpdate(key), nil
+		return v.newKeywordUpdate(key)
 
 	case SearchAttributeKeywordListType:
 		// Keyword List
-		return v.newKeywordListUpdate(key), nil
+		return v.newKeywordListUpdate(key)
 
 	case SearchAttributeTextType:
 		// Text
-		return v.newTextUpdate(key), nil
+		return v.newTextUpdate(key)
 
 	default:
 		return nil, fmt.Errorf("unknown search attribute type: %s", v.Type)
@@ -219,15 +314,34 @@ func ParseSearchAttributes(ctx workflow.Context, metadata any) error {
 		return fmt.Errorf("error converting attributes to golang struct: %w", err)
 	}
 
+	unregistered, err := unregisteredSearchAttributes(ctx, searchAttributes)
+	if err != nil {
+		return err
+	}
+
+	// Workflows started before unchanged attributes were skipped upserted them
+	// every time, so keep doing so to replay them
+	dedupe := workflow.GetVersion(ctx, SearchAttributeDedupeChangeID, workflow.DefaultVersion, 1) == 1
+	current := workflow.GetTypedSearchAttributes(ctx).GetUntypedValues()
 	signedAttributes := make([]temporal.SearchAttributeUpdate, 0)
 
 	for k, v := range searchAttributes {
-		if attr, err := v.setAttribute(k); err != nil {
+		if slices.Contains(unregistered, k) {
+			logger.Warn("Skipping unregistered search attribute", "key", k)
+			continue
+		}
+
+		attr, err := v.setAttribute(k)
+		if err != nil {
 			logger.Error("Error setting search attribute", "error", err)
 			return fmt.Errorf("error setting search attribute: %w", err)
-		} else {
-			signedAttributes = append(signedAttributes, attr)
 		}
+
+		if dedupe && !searchAttributeChanged(current, k, attr) {
+			logger.Debug("Search attribute unchanged", "key", k)
+			continue
+		}
+		signedAttributes = append(signedAttributes, attr)
 	}
 
 	if len(signedAttributes) == 0 {
@@ -242,3 +356,109 @@ func ParseSearchAttributes(ctx workflow.Context, metadata any) error {
 
 	return nil
 }
+
+// searchAttributeChanged returns whether the update changes the workflow's
+// search attribute. Each upsert is a history event, so there's no need to
+// upsert a value that's already set. The workflow's search attributes include
+// those it was started with and every upsert, so this is deterministic.
+func searchAttributeChanged(current map[temporal.SearchAttributeKey]any, key string, update temporal.SearchAttributeUpdate) bool {
+	var existing any
+	var found bool
+	for k, v := range current {
+		if k.GetName() == key {
+			existing = v
+			found = true
+		}
+	}
+
+	// Applied to an empty collection, the update gives the value it sets. If
+	// there's no value, the attribute is being unset.
+	for _, v := range temporal.NewSearchAttributes(update).GetUntypedValues() {
+		return !found || !reflect.DeepEqual(existing, v)
+	}
+
+	return found
+}
+
+// GetStartSearchAttributes returns the search attributes that the document's
+// workflows are started with, so they can be listed from the first event. As
+// these are set by the client, they can't be runtime expressions.
+func GetStartSearchAttributes(workflow *model.Workflow) (temporal.SearchAttributes, error) {
+	v, ok := workflow.Document.Metadata[MetadataStartSearchAttributes]
+	if !ok {
+		return temporal.SearchAttributes{}, nil
+	}
+
+	search, ok := v.(map[string]any)
+	if !ok {
+		return temporal.SearchAttributes{}, fmt.Errorf("start search attributes in invalid format")
+	}
+
+	var searchAttributes map[string]*SearchAttribute
+	if err := mapstructure.Decode(search, &searchAttributes); err != nil {
+		return temporal.SearchAttributes{}, fmt.Errorf("error converting start search attributes to golang struct: %w", err)
+	}
+
+	updates := make([]temporal.SearchAttributeUpdate, 0, len(searchAttributes))
+	for k, v := range searchAttributes {
+		if v == nil || v.Value == nil {
+			return temporal.SearchAttributes{}, fmt.Errorf("start search attribute %s must have a value", k)
+		}
+
+		attr, err := v.setAttribute(k)
+		if err != nil {
+			return temporal.SearchAttributes{}, fmt.Errorf("error setting start search attribute %s: %w", k, err)
+		}
+		updates = append(updates, attr)
+	}
+
+	return temporal.NewSearchAttributes(updates...), nil
+}
+
+// unregisteredSearchAttributes returns the attributes to skip because they're
+// not registered in the namespace. Registration and the worker's settings can
+// change while a workflow is running, so this is always recorded as a side
+// effect to keep replays deterministic. Workflows started before this only
+// recorded it if the worker could skip an attribute.
+func unregisteredSearchAttributes(ctx workflow.Context, searchAttributes map[string]*SearchAttribute) ([]string, error) {
+	if len(searchAttributes) == 0 {
+		return nil, nil
+	}
+
+	if workflow.GetVersion(ctx, SearchAttributeRegistrationChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		if registeredSearchAttributes == nil {
+			return nil, nil
+		}
+
+		lenient := lenientSearchAttributes
+		for _, v := range searchAttributes {
+			lenient = lenient || v.IgnoreIfUnregistered
+		}
+		if !lenient {
+			return nil, nil
+		}
+	}
+
+	var unregistered []string
+	err := workflow.SideEffect(ctx, func(ctx workflow.Context) any {
+		keys := make([]string, 0)
+		if registeredSearchAttributes == nil {
+			// The registered attributes are unknown, so nothing is skipped
+			return keys
+		}
+
+		for k, v := range searchAttributes {
+			if (lenientSearchAttributes || v.IgnoreIfUnregistered) && !registeredSearchAttributes[k] {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+
+		return keys
+	}).Get(&unregistered)
+	if err != nil {
+		return nil, fmt.Errorf("error checking registered search attributes: %w", err)
+	}
+
+	return unregistered, nil
+}
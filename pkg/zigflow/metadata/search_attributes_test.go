@@ -0,0 +1,381 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestParseSearchAttributesUnregistered(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Registered []string
+		Lenient    bool
+		Attributes map[string]any
+		Expected   []string
+	}{
+		{
+			Name:       "Registry unknown",
+			Registered: nil,
+			Lenient:    true,
+			Attributes: map[string]any{
+				"Registered":   map[string]any{"type": "keyword", "value": "a"},
+				"Unregistered": map[string]any{"type": "keyword", "value": "b"},
+			},
+			Expected: []string{"Registered", "Unregistered"},
+		},
+		{
+			Name:       "Not lenient",
+			Registered: []string{"Registered"},
+			Attributes: map[string]any{
+				"Registered":   map[string]any{"type": "keyword", "value": "a"},
+				"Unregistered": map[string]any{"type": "keyword", "value": "b"},
+			},
+			Expected: []string{"Registered", "Unregistered"},
+		},
+		{
+			Name:       "Lenient flag",
+			Registered: []string{"Registered"},
+			Lenient:    true,
+			Attributes: map[string]any{
+				"Registered":   map[string]any{"type": "keyword", "value": "a"},
+				"Unregistered": map[string]any{"type": "keyword", "value": "b"},
+			},
+			Expected: []string{"Registered"},
+		},
+		{
+			Name:       "Ignore if unregistered",
+			Registered: []string{"Registered"},
+			Attributes: map[string]any{
+				"Registered":   map[string]any{"type": "keyword", "value": "a"},
+				"Ignored":      map[string]any{"type": "keyword", "value": "b", "ignoreIfUnregistered": true},
+				"Unregistered": map[string]any{"type": "keyword", "value": "c"},
+			},
+			Expected: []string{"Registered", "Unregistered"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			metadata.SetRegisteredSearchAttributes(test.Registered)
+			metadata.SetLenientSearchAttributes(test.Lenient)
+			t.Cleanup(func() {
+				metadata.SetRegisteredSearchAttributes(nil)
+				metadata.SetLenientSearchAttributes(false)
+			})
+
+			var suite testsuite.WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+			env.ExecuteWorkflow(func(ctx workflow.Context) ([]string, error) {
+				if err := metadata.ParseSearchAttributes(ctx, test.Attributes); err != nil {
+					return nil, err
+				}
+
+				keys := make([]string, 0)
+				for k := range workflow.GetTypedSearchAttributes(ctx).GetUntypedValues() {
+					// Set by the workflow versioning
+					if k.GetName() != "TemporalChangeVersion" {
+						keys = append(keys, k.GetName())
+					}
+				}
+				return keys, nil
+			})
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var keys []string
+			assert.NoError(t, env.GetWorkflowResult(&keys))
+			assert.ElementsMatch(t, test.Expected, keys)
+		})
+	}
+}
+
+// upsertRecorder records the keys of each search attribute upsert and counts
+// the side effects
+type upsertRecorder struct {
+	interceptor.WorkerInterceptorBase
+
+	upserts     [][]string
+	sideEffects int
+}
+
+func (r *upsertRecorder) InterceptWorkflow(
+	_ workflow.Context, next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	return &upsertInbound{WorkflowInboundInterceptorBase: interceptor.WorkflowInboundInterceptorBase{Next: next}, recorder: r}
+}
+
+type upsertInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+
+	recorder *upsertRecorder
+}
+
+func (i *upsertInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	return i.Next.Init(&upsertOutbound{
+		WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound},
+		recorder:                        i.recorder,
+	})
+}
+
+type upsertOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+
+	recorder *upsertRecorder
+}
+
+func (o *upsertOutbound) UpsertTypedSearchAttributes(ctx workflow.Context, attributes ...temporal.SearchAttributeUpdate) error {
+	keys := make([]string, 0)
+	for k := range temporal.NewSearchAttributes(attributes...).GetUntypedValues() {
+		keys = append(keys, k.GetName())
+	}
+	slices.Sort(keys)
+	o.recorder.upserts = append(o.recorder.upserts, keys)
+
+	return o.Next.UpsertTypedSearchAttributes(ctx, attributes...)
+}
+
+func (o *upsertOutbound) SideEffect(ctx workflow.Context, f func(ctx workflow.Context) any) converter.EncodedValue {
+	o.recorder.sideEffects++
+
+	return o.Next.SideEffect(ctx, f)
+}
+
+func TestParseSearchAttributesUnchanged(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Version  workflow.Version
+		Expected [][]string
+	}{
+		{
+			Name:     "Unchanged attributes skipped",
+			Version:  1,
+			Expected: [][]string{{"Count", "Customer"}, {"Count"}},
+		},
+		{
+			// Histories recorded before the change upsert every attribute
+			Name:     "Before unchanged attributes were skipped",
+			Version:  workflow.DefaultVersion,
+			Expected: [][]string{{"Count", "Customer"}, {"Count", "Customer"}, {"Count", "Customer"}, {}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			recorder := &upsertRecorder{}
+
+			var suite testsuite.WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+			env.SetWorkerOptions(worker.Options{
+				Interceptors: []interceptor.WorkerInterceptor{recorder},
+			})
+			env.OnGetVersion(metadata.SearchAttributeDedupeChangeID, workflow.DefaultVersion, 1).Return(test.Version)
+			env.ExecuteWorkflow(func(ctx workflow.Context) error {
+				for _, attributes := range []map[string]any{
+					{
+						"Customer": map[string]any{"type": "keyword", "value": "acme"},
+						"Count":    map[string]any{"type": "int", "value": 1},
+					},
+					// Nothing has changed
+					{
+						"Customer": map[string]any{"type": "keyword", "value": "acme"},
+						"Count":    map[string]any{"type": "int", "value": float64(1)},
+					},
+					// Only the count has changed
+					{
+						"Customer": map[string]any{"type": "keyword", "value": "acme"},
+						"Count":    map[string]any{"type": "int", "value": 2},
+					},
+					// Unsetting an attribute that isn't set changes nothing
+					{
+						"Missing": map[string]any{"type": "keyword"},
+					},
+				} {
+					if err := metadata.ParseSearchAttributes(ctx, attributes); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			assert.Equal(t, test.Expected, recorder.upserts)
+		})
+	}
+}
+
+func TestParseSearchAttributesSideEffect(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Version     workflow.Version
+		Registered  []string
+		Lenient     bool
+		SideEffects int
+	}{
+		{
+			Name:        "Registry unknown",
+			Version:     1,
+			SideEffects: 1,
+		},
+		{
+			Name:        "Not lenient",
+			Version:     1,
+			Registered:  []string{"Customer"},
+			SideEffects: 1,
+		},
+		{
+			Name:        "Lenient",
+			Version:     1,
+			Registered:  []string{"Customer"},
+			Lenient:     true,
+			SideEffects: 1,
+		},
+		{
+			// Histories recorded before the change only have the side effect
+			// if an attribute could be skipped
+			Name:        "Before the side effect was always recorded",
+			Version:     workflow.DefaultVersion,
+			Registered:  []string{"Customer"},
+			SideEffects: 0,
+		},
+		{
+			Name:        "Lenient before the side effect was always recorded",
+			Version:     workflow.DefaultVersion,
+			Registered:  []string{"Customer"},
+			Lenient:     true,
+			SideEffects: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			metadata.SetRegisteredSearchAttributes(test.Registered)
+			metadata.SetLenientSearchAttributes(test.Lenient)
+			t.Cleanup(func() {
+				metadata.SetRegisteredSearchAttributes(nil)
+				metadata.SetLenientSearchAttributes(false)
+			})
+
+			recorder := &upsertRecorder{}
+
+			var suite testsuite.WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+			env.SetWorkerOptions(worker.Options{
+				Interceptors: []interceptor.WorkerInterceptor{recorder},
+			})
+			env.OnGetVersion(metadata.SearchAttributeRegistrationChangeID, workflow.DefaultVersion, 1).Return(test.Version)
+			env.ExecuteWorkflow(func(ctx workflow.Context) error {
+				return metadata.ParseSearchAttributes(ctx, map[string]any{
+					"Customer": map[string]any{"type": "keyword", "value": "acme"},
+				})
+			})
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			assert.Equal(t, test.SideEffects, recorder.sideEffects)
+		})
+	}
+}
+
+func TestSearchAttributeValidation(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Type  string
+		Valid bool
+	}{
+		{
+			Name:  "Bool",
+			Type:  "Bool",
+			Valid: true,
+		},
+		{
+			Name:  "Datetime",
+			Type:  "Datetime",
+			Valid: true,
+		},
+		{
+			Name:  "Double",
+			Type:  "Double",
+			Valid: true,
+		},
+		{
+			Name:  "Int",
+			Type:  "Int",
+			Valid: true,
+		},
+		{
+			Name:  "Keyword",
+			Type:  "Keyword",
+			Valid: true,
+		},
+		{
+			Name:  "KeywordList",
+			Type:  "KeywordList",
+			Valid: true,
+		},
+		{
+			Name:  "Text",
+			Type:  "Text",
+			Valid: true,
+		},
+		{
+			Name:  "Lowercase",
+			Type:  metadata.SearchAttributeBooleanType,
+			Valid: true,
+		},
+		{
+			Name: "Invalid",
+			Type: "string",
+		},
+		{
+			Name: "Empty",
+		},
+	}
+
+	v, err := utils.NewValidator()
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res, err := v.ValidateStruct(&metadata.SearchAttribute{
+				Type: test.Type,
+			})
+			assert.NoError(t, err)
+
+			if test.Valid {
+				assert.Empty(t, res)
+			} else {
+				assert.NotEmpty(t, res)
+			}
+		})
+	}
+}
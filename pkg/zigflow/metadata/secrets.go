@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// GetSecrets returns the names of the envvars and data keys whose values are
+// secret, or an empty list if not set
+func GetSecrets(workflow *model.Workflow) ([]string, error) {
+	v, ok := workflow.Document.Metadata[MetadataSecrets]
+	if !ok {
+		return []string{}, nil
+	}
+
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("secrets must be a list")
+	}
+
+	secrets := make([]string, 0, len(list))
+	for _, s := range list {
+		name, ok := s.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("secrets must be non-empty strings")
+		}
+		secrets = append(secrets, name)
+	}
+
+	return secrets, nil
+}
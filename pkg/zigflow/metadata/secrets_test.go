@@ -0,0 +1,82 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSecrets(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Secrets  any
+		Expected []string
+		Error    string
+	}{
+		{
+			Name:     "Not set",
+			Expected: []string{},
+		},
+		{
+			Name:     "Secrets",
+			Secrets:  []any{"API_KEY", "password"},
+			Expected: []string{"API_KEY", "password"},
+		},
+		{
+			Name:    "Not a list",
+			Secrets: "API_KEY",
+			Error:   "secrets must be a list",
+		},
+		{
+			Name:    "Empty name",
+			Secrets: []any{"API_KEY", ""},
+			Error:   "secrets must be non-empty strings",
+		},
+		{
+			Name:    "Not a string",
+			Secrets: []any{1},
+			Error:   "secrets must be non-empty strings",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := &model.Workflow{
+				Document: model.Document{
+					Name:     "workflow",
+					Metadata: map[string]any{},
+				},
+			}
+			if test.Secrets != nil {
+				doc.Document.Metadata[metadata.MetadataSecrets] = test.Secrets
+			}
+
+			secrets, err := metadata.GetSecrets(doc)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, secrets)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import "fmt"
+
+// GetSummary returns the task's summary, which is shown in the Temporal UI.
+// This may be a runtime expression. An empty string means that it's not set.
+func GetSummary(m map[string]any) (string, error) {
+	return getOptionalString(m, MetadataSummary, "summary")
+}
+
+// GetDetails returns the task's details, which are shown in the Temporal UI
+// while it runs. This may be a runtime expression. An empty string means that
+// it's not set.
+func GetDetails(m map[string]any) (string, error) {
+	return getOptionalString(m, MetadataDetails, "details")
+}
+
+// GetDescription returns what the workflow or task does, which is shown in
+// the Temporal UI and the compiled workflow. Unlike the summary, this is never
+// evaluated. An empty string means that it's not set.
+func GetDescription(m map[string]any) (string, error) {
+	return getOptionalString(m, MetadataDescription, "description")
+}
+
+func getOptionalString(m map[string]any, key, name string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", name)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// GetTaskQueue returns the task queue the document's workflows run on. This
+// defaults to the document name, which is independent of the Temporal
+// namespace.
+func GetTaskQueue(workflow *model.Workflow) (string, error) {
+	v, ok := workflow.Document.Metadata[MetadataTaskQueue]
+	if !ok {
+		return workflow.Document.Name, nil
+	}
+
+	taskQueue, ok := v.(string)
+	if !ok || taskQueue == "" {
+		return "", fmt.Errorf("task queue must be a non-empty string")
+	}
+
+	return taskQueue, nil
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import "fmt"
+
+// GetUnset returns the data keys a set task removes from the state. If all is
+// true, all the data is removed.
+func GetUnset(m map[string]any) (all bool, keys []string, err error) {
+	v, ok := m[MetadataUnset]
+	if !ok {
+		return false, nil, nil
+	}
+
+	switch u := v.(type) {
+	case bool:
+		return u, nil, nil
+	case []string:
+		return false, u, nil
+	case []any:
+		keys = make([]string, 0, len(u))
+		for _, k := range u {
+			key, ok := k.(string)
+			if !ok || key == "" {
+				return false, nil, fmt.Errorf("unset keys must be non-empty strings")
+			}
+			keys = append(keys, key)
+		}
+		return false, keys, nil
+	default:
+		return false, nil, fmt.Errorf("unset must be a boolean or a list of keys")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"maps"
+	"math"
+
+	"github.com/go-viper/mapstructure/v2"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Version pins a task to a Temporal change ID so that in-flight workflows
+// continue to replay against the task graph they started with. This can be
+// set as just the change ID or as an object.
+type Version struct {
+	ChangeID     string `json:"changeId" mapstructure:"changeId"`
+	MinSupported int    `json:"minSupported" mapstructure:"minSupported"`
+	MaxSupported int    `json:"maxSupported" mapstructure:"maxSupported"`
+
+	// The task has been replaced by this change and only runs for workflows
+	// started before it
+	Replaced bool `json:"replaced" mapstructure:"replaced"`
+}
+
+// GetVersion returns the task's version metadata, or nil if it's not set
+func GetVersion(m map[string]any) (*Version, error) {
+	v, ok := m[MetadataVersion]
+	if !ok {
+		return nil, nil
+	}
+
+	version := &Version{
+		MinSupported: int(workflow.DefaultVersion),
+		MaxSupported: 1,
+	}
+
+	switch e := v.(type) {
+	case string:
+		version.ChangeID = e
+	case map[string]any:
+		// The supported versions are decoded as whole numbers as mapstructure
+		// would truncate a fraction
+		fields := maps.Clone(e)
+		for _, supported := range []struct {
+			key   string
+			value *int
+		}{
+			{key: "minSupported", value: &version.MinSupported},
+			{key: "maxSupported", value: &version.MaxSupported},
+		} {
+			n, ok := fields[supported.key]
+			if !ok {
+				continue
+			}
+			delete(fields, supported.key)
+
+			var err error
+			if *supported.value, err = getSupportedVersion(supported.key, n); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := mapstructure.Decode(fields, version); err != nil {
+			return nil, fmt.Errorf("error decoding version: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("version must be a string or object")
+	}
+
+	if version.ChangeID == "" {
+		return nil, fmt.Errorf("version change id is required")
+	}
+	if version.MinSupported > version.MaxSupported {
+		return nil, fmt.Errorf("version minSupported must not be greater than maxSupported")
+	}
+	if version.Replaced && version.MinSupported != int(workflow.DefaultVersion) {
+		return nil, fmt.Errorf("replaced version must support the default version")
+	}
+
+	return version, nil
+}
+
+// getSupportedVersion returns a supported version, which must be a whole
+// number that's not negative
+func getSupportedVersion(key string, v any) (int, error) {
+	name := "version " + key
+
+	n, err := getWholeNumber(name, v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 {
+		return 0, fmt.Errorf("%s must not be negative", name)
+	}
+	if n > math.MaxInt32 {
+		return 0, fmt.Errorf("%s must be at most %d", name, math.MaxInt32)
+	}
+
+	return int(n), nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVersion(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata map[string]any
+		Expected *metadata.Version
+		Error    string
+	}{
+		{
+			Name:     "Not set",
+			Metadata: map[string]any{},
+		},
+		{
+			Name: "Change ID only",
+			Metadata: map[string]any{
+				metadata.MetadataVersion: "some-change",
+			},
+			Expected: &metadata.Version{
+				ChangeID:     "some-change",
+				MinSupported: -1,
+				MaxSupported: 1,
+			},
+		},
+		{
+			Name: "Object",
+			Metadata: map[string]any{
+				metadata.MetadataVersion: map[string]any{
+					"changeId":     "some-change",
+					"minSupported": float64(1),
+					"maxSupported": float64(2),
+				},
+			},
+			Expected: &metadata.Version{
+				ChangeID:     "some-change",
+				MinSupported: 1,
+				MaxSupported: 2,
+			},
+		},
+		{
+			Name: "Missing change ID",
+			Metadata: map[string]any{
+				metadata.MetadataVersion: map[string]any{
+					"maxSupported": float64(2),
+				},
+			},
+			Error: "version change id is required",
+		},
+		{
+			Name: "Invalid range",
+			Metadata: map[string]any{
+				metadata.MetadataVersion: map[string]any{
+					"changeId":     "some-change",
+					"minSupported": float64(3),
+					"maxSupported": float64(2),
+				},
+			},
+			Error: "version minSupported must not be greater than maxSupported",
+		},
+		{
+			Name: "Fractional version",
+			Metadata: map[string]any{
+				metadata.MetadataVersion: map[string]any{
+					"changeId":     "some-change",
+					"maxSupported": 1.5,
+				},
+			},
+			Error: "version maxSupported must be a whole number",
+		},
+		{
+			Name: "Negative version",
+			Metadata: map[string]any{
+				metadata.MetadataVersion: map[string]any{
+					"changeId":     "some-change",
+					"minSupported": float64(-2),
+				},
+			},
+			Error: "version minSupported must not be negative",
+		},
+		{
+			Name: "Invalid type",
+			Metadata: map[string]any{
+				metadata.MetadataVersion: true,
+			},
+			Error: "version must be a string or object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			v, err := metadata.GetVersion(test.Metadata)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, v)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/api/enums/v1"
+)
+
+// GetWorkflowID returns the workflow ID metadata. This may be a runtime
+// expression. An empty string means that it's not set.
+func GetWorkflowID(m map[string]any) (string, error) {
+	v, ok := m[MetadataWorkflowID]
+	if !ok {
+		return "", nil
+	}
+
+	id, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("workflow id must be a string")
+	}
+
+	return id, nil
+}
+
+// GetWorkflowIDPrefix returns the prefix of the IDs of the workflows started
+// from the document. An empty string means that it's not set.
+func GetWorkflowIDPrefix(workflow *model.Workflow) (string, error) {
+	v, ok := workflow.Document.Metadata[MetadataWorkflowIDPrefix]
+	if !ok {
+		return "", nil
+	}
+
+	prefix, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("workflow id prefix must be a string")
+	}
+
+	return prefix, nil
+}
+
+// GetWorkflowIDReusePolicy returns the workflow ID reuse policy metadata. This
+// accepts either the PascalCase (eg, AllowDuplicate) or SCREAMING_CASE (eg,
+// WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE) name.
+func GetWorkflowIDReusePolicy(m map[string]any) (enums.WorkflowIdReusePolicy, error) {
+	v, ok := m[MetadataWorkflowIDReusePolicy]
+	if !ok {
+		return enums.WORKFLOW_ID_REUSE_POLICY_UNSPECIFIED, nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return enums.WORKFLOW_ID_REUSE_POLICY_UNSPECIFIED, fmt.Errorf("workflow id reuse policy must be a string")
+	}
+
+	policy, err := enums.WorkflowIdReusePolicyFromString(s)
+	if err != nil {
+		return enums.WORKFLOW_ID_REUSE_POLICY_UNSPECIFIED, fmt.Errorf("invalid workflow id reuse policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// Parent close policies, as used in the metadata
+var parentClosePolicies = map[string]enums.ParentClosePolicy{
+	"abandon":        enums.PARENT_CLOSE_POLICY_ABANDON,
+	"request-cancel": enums.PARENT_CLOSE_POLICY_REQUEST_CANCEL,
+	"terminate":      enums.PARENT_CLOSE_POLICY_TERMINATE,
+}
+
+// GetParentClosePolicy returns the parent close policy metadata. This is one
+// of abandon, request-cancel or terminate, or the PascalCase (eg, Abandon) or
+// SCREAMING_CASE (eg, PARENT_CLOSE_POLICY_ABANDON) name.
+func GetParentClosePolicy(m map[string]any) (enums.ParentClosePolicy, error) {
+	v, ok := m[MetadataParentClosePolicy]
+	if !ok {
+		return enums.PARENT_CLOSE_POLICY_UNSPECIFIED, nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return enums.PARENT_CLOSE_POLICY_UNSPECIFIED, fmt.Errorf("parent close policy must be a string")
+	}
+
+	if policy, ok := parentClosePolicies[strings.ToLower(s)]; ok {
+		return policy, nil
+	}
+
+	policy, err := enums.ParentClosePolicyFromString(s)
+	if err != nil {
+		return enums.PARENT_CLOSE_POLICY_UNSPECIFIED, fmt.Errorf("invalid parent close policy: %w", err)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// GetWorkflows returns the document's inline workflows, keyed by the workflow
+// name, or nil if there are none
+func GetWorkflows(workflow *model.Workflow) (map[string]*model.TaskList, error) {
+	v, ok := workflow.Document.Metadata[MetadataWorkflows]
+	if !ok {
+		return nil, nil
+	}
+
+	if _, ok := v.(map[string]any); !ok {
+		return nil, fmt.Errorf("workflows must be an object")
+	}
+
+	// Convert the tasks in the same way as the document's tasks
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling workflows: %w", err)
+	}
+
+	var workflows map[string]*model.TaskList
+	if err := json.Unmarshal(data, &workflows); err != nil {
+		return nil, fmt.Errorf("invalid workflows: %w", err)
+	}
+
+	for name, list := range workflows {
+		if name == "" {
+			return nil, fmt.Errorf("workflow names must be non-empty strings")
+		}
+		if list == nil || len(*list) == 0 {
+			return nil, fmt.Errorf("workflow %s must have at least one task", name)
+		}
+	}
+
+	return workflows, nil
+}
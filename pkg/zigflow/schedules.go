@@ -27,7 +27,11 @@ import (
 	"go.temporal.io/sdk/client"
 )
 
-func UpdateSchedules(ctx context.Context, temporalClient client.Client, workflow *model.Workflow, envvars map[string]any) error {
+// UpdateSchedules replaces the document's schedule. The scheduled workflows
+// are started on the given task queue, which must be the one the worker polls.
+func UpdateSchedules(
+	ctx context.Context, temporalClient client.Client, workflow *model.Workflow, taskQueue string, envvars map[string]any,
+) error {
 	info, err := metadata.GetScheduleInfo(workflow, envvars)
 	if err != nil {
 		return fmt.Errorf("error getting schedule metadata: %w", err)
@@ -44,6 +48,9 @@ func UpdateSchedules(ctx context.Context, temporalClient client.Client, workflow
 	if schedule == nil {
 		log.Debug().Msg("No schedules set")
 		return nil
+	} else if !info.Enabled {
+		log.Info().Str("scheduleID", info.ID).Msg("Schedule disabled")
+		return nil
 	} else if info.WorkflowName == "" {
 		log.Error().Msg("Workflow name not set")
 		return fmt.Errorf("workflow name not set for schedule")
@@ -55,14 +62,38 @@ func UpdateSchedules(ctx context.Context, temporalClient client.Client, workflow
 		return fmt.Errorf("error converting schedule to temporal: %w", err)
 	}
 
+	workflowID, err := scheduleWorkflowID(workflow, info.ID)
+	if err != nil {
+		return fmt.Errorf("error getting workflow id prefix: %w", err)
+	}
+
+	description, err := metadata.GetDescription(workflow.Document.Metadata)
+	if err != nil {
+		return fmt.Errorf("error getting description: %w", err)
+	}
+
+	searchAttributes, err := metadata.GetStartSearchAttributes(workflow)
+	if err != nil {
+		return fmt.Errorf("error getting start search attributes: %w", err)
+	}
+
+	timeout, err := DocumentTimeout(workflow)
+	if err != nil {
+		return err
+	}
+
 	// Convert the Serverless Workflow schedule to a Temporal schedule
 	opts := client.ScheduleOptions{
 		ID:   info.ID,
 		Spec: *scheduleSpec,
 		Action: &client.ScheduleWorkflowAction{
-			Workflow:  info.WorkflowName,
-			TaskQueue: workflow.Document.Namespace,
-			Args:      info.Input,
+			ID:                       workflowID,
+			Workflow:                 info.WorkflowName,
+			TaskQueue:                taskQueue,
+			Args:                     info.Input,
+			WorkflowExecutionTimeout: timeout,
+			StaticSummary:            description,
+			TypedSearchAttributes:    searchAttributes,
 		},
 	}
 
@@ -74,6 +105,22 @@ func UpdateSchedules(ctx context.Context, temporalClient client.Client, workflow
 	return nil
 }
 
+// scheduleWorkflowID returns the workflow ID of the schedule's action. Temporal
+// appends the scheduled time to this to make each execution's ID unique and
+// uses the schedule ID if it's empty. With a workflowIdPrefix, the IDs are the
+// prefixed schedule ID followed by the time.
+func scheduleWorkflowID(workflow *model.Workflow, scheduleID string) (string, error) {
+	prefix, err := metadata.GetWorkflowIDPrefix(workflow)
+	if err != nil {
+		return "", err
+	}
+	if prefix == "" {
+		return "", nil
+	}
+
+	return PrefixWorkflowID(prefix, scheduleID), nil
+}
+
 // Converts the Serverless Workflow schedule to Temporal schedule spec
 func buildTemporalScheduleSpec(schedule model.Schedule) (*client.ScheduleSpec, error) {
 	calendars := make([]client.ScheduleCalendarSpec, 0)
@@ -84,11 +131,13 @@ func buildTemporalScheduleSpec(schedule model.Schedule) (*client.ScheduleSpec, e
 		cronExpression = append(cronExpression, schedule.Cron)
 	}
 	if schedule.Every != nil {
-		if duration := schedule.Every; duration != nil {
-			intervals = append(intervals, client.ScheduleIntervalSpec{
-				Every: utils.ToDuration(duration),
-			})
+		every, err := utils.ToDuration(schedule.Every)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule.every: %w", err)
 		}
+		intervals = append(intervals, client.ScheduleIntervalSpec{
+			Every: every,
+		})
 	}
 	if schedule.After != nil {
 		return nil, fmt.Errorf("schedule.after not supported")
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"sigs.k8s.io/yaml"
+)
+
+func TestUpdateSchedulesEnabled(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Enabled  string
+		Envvars  map[string]any
+		Expected bool
+		Error    string
+	}{
+		{
+			Name:     "Enabled by default",
+			Expected: true,
+		},
+		{
+			Name:     "Enabled",
+			Enabled:  "true",
+			Expected: true,
+		},
+		{
+			Name:    "Disabled",
+			Enabled: "false",
+		},
+		{
+			Name:     "Enabled by envvar",
+			Enabled:  "${ .env.SCHEDULE_ENABLED }",
+			Envvars:  map[string]any{"SCHEDULE_ENABLED": "true"},
+			Expected: true,
+		},
+		{
+			Name:    "Disabled by envvar",
+			Enabled: "${ .env.SCHEDULE_ENABLED }",
+			Envvars: map[string]any{"SCHEDULE_ENABLED": "false"},
+		},
+		{
+			Name:    "Invalid",
+			Enabled: "sometimes",
+			Error:   "schedule enabled must be a boolean",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			enabled := ""
+			if test.Enabled != "" {
+				enabled = "    scheduleEnabled: " + test.Enabled
+			}
+
+			var doc *model.Workflow
+			assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: zigflow
+  name: schedule
+  version: 0.0.1
+  metadata:
+    scheduleId: some-schedule
+    scheduleWorkflowName: schedule
+`+enabled+`
+schedule:
+  every:
+    minutes: 3
+do:
+  - step:
+      set:
+        hello: world`), &doc))
+
+			// The existing schedule is always deleted
+			iterator := &mocks.ScheduleListIterator{}
+			iterator.On("HasNext").Return(true).Once()
+			iterator.On("HasNext").Return(false)
+			iterator.On("Next").Return(&client.ScheduleListEntry{ID: "some-schedule"}, nil)
+
+			handle := &mocks.ScheduleHandle{}
+			handle.On("Delete", mock.Anything).Return(nil)
+
+			scheduleClient := &mocks.ScheduleClient{}
+			scheduleClient.On("List", mock.Anything, mock.Anything).Return(iterator, nil)
+			scheduleClient.On("GetHandle", mock.Anything, "some-schedule").Return(handle)
+			scheduleClient.On("Create", mock.Anything, mock.Anything).Return(handle, nil)
+
+			c := &mocks.Client{}
+			c.On("ScheduleClient").Return(scheduleClient)
+
+			err := zigflow.UpdateSchedules(context.Background(), c, doc, "queue", test.Envvars)
+			if test.Error != "" {
+				assert.ErrorContains(t, err, test.Error)
+				return
+			}
+			assert.NoError(t, err)
+
+			handle.AssertCalled(t, "Delete", mock.Anything)
+			if test.Expected {
+				scheduleClient.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(opts client.ScheduleOptions) bool {
+					return opts.ID == "some-schedule"
+				}))
+			} else {
+				scheduleClient.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
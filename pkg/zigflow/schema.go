@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow
+
+import "github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+
+// The Serverless Workflow schema that this extends
+const ServerlessWorkflowSchema = "https://serverlessworkflow.io/schemas/1.0.0/workflow.yaml"
+
+// JSONSchema returns the schema for a workflow file. This extends the
+// Serverless Workflow schema with the metadata that Zigflow understands.
+func JSONSchema() map[string]any {
+	taskList := map[string]any{"$ref": "#/$defs/taskList"}
+
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "Zigflow workflow",
+		"allOf": []any{
+			map[string]any{"$ref": ServerlessWorkflowSchema},
+		},
+		"properties": map[string]any{
+			"document": map[string]any{
+				"properties": map[string]any{
+					"metadata": map[string]any{"$ref": "#/$defs/documentMetadata"},
+				},
+			},
+			"do": taskList,
+		},
+		"$defs": map[string]any{
+			"documentMetadata": metadata.DocumentSchema(),
+			"taskMetadata":     metadata.TaskSchema(),
+			"taskList": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":                 "object",
+					"additionalProperties": map[string]any{"$ref": "#/$defs/task"},
+				},
+			},
+			// Only the parts of the task that contain metadata or nested tasks
+			"task": map[string]any{
+				"properties": map[string]any{
+					"metadata": map[string]any{"$ref": "#/$defs/taskMetadata"},
+					"do":       taskList,
+					"try":      taskList,
+					"catch": map[string]any{
+						"properties": map[string]any{
+							"do": taskList,
+						},
+					},
+					"fork": map[string]any{
+						"properties": map[string]any{
+							"branches": taskList,
+						},
+					},
+				},
+			},
+		},
+	}
+}
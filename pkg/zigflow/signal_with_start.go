@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/sdk/client"
+)
+
+// Signal is sent to a workflow as it's started
+type Signal struct {
+	Name string
+	Data any
+}
+
+// SignalWithStart starts the workflow and sends it the signal in a single
+// call. If a workflow with the options' ID is already running, it's only
+// signalled. Temporal buffers the signal until the workflow listens for it, so
+// there's no race between the workflow starting and the signal being sent.
+//
+// The workflow ID is generated if not set in the options.
+func SignalWithStart(
+	ctx context.Context,
+	c client.Client,
+	opts client.StartWorkflowOptions,
+	workflow string,
+	signal Signal,
+	input ...any,
+) (client.WorkflowRun, error) {
+	if signal.Name == "" {
+		return nil, fmt.Errorf("signal name must be set")
+	}
+
+	run, err := c.SignalWithStartWorkflow(ctx, opts.ID, signal.Name, signal.Data, opts, workflow, input...)
+	if err != nil {
+		return nil, fmt.Errorf("error signalling with start: %w", err)
+	}
+
+	return run, nil
+}
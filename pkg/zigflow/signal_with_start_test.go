@@ -0,0 +1,87 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+)
+
+func TestSignalWithStart(t *testing.T) {
+	opts := client.StartWorkflowOptions{
+		ID:        "some-id",
+		TaskQueue: "some-queue",
+	}
+
+	tests := []struct {
+		Name   string
+		Signal zigflow.Signal
+		Err    error
+		Error  string
+	}{
+		{
+			Name: "Signal with start",
+			Signal: zigflow.Signal{
+				Name: "approve",
+				Data: map[string]any{"approved": true},
+			},
+		},
+		{
+			Name:  "No signal name",
+			Error: "signal name must be set",
+		},
+		{
+			Name: "Client error",
+			Signal: zigflow.Signal{
+				Name: "approve",
+			},
+			Err:   errors.New("some error"),
+			Error: "error signalling with start: some error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			c := &mocks.Client{}
+			run := &mocks.WorkflowRun{}
+
+			if test.Signal.Name != "" {
+				c.On(
+					"SignalWithStartWorkflow", ctx, opts.ID, test.Signal.Name, test.Signal.Data, opts, "workflow", "input",
+				).Return(run, test.Err)
+			}
+
+			res, err := zigflow.SignalWithStart(ctx, c, opts, "workflow", test.Signal, "input")
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				assert.Nil(t, res)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, run, res)
+			}
+
+			c.AssertExpectations(t)
+		})
+	}
+}
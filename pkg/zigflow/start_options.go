@@ -0,0 +1,111 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/client"
+)
+
+// DocumentTimeout returns the document's timeout, or zero if not set. This
+// bounds the whole workflow execution when started with StartWorkflowOptions
+// and each activity's start-to-close timeout.
+func DocumentTimeout(doc *model.Workflow) (time.Duration, error) {
+	if doc == nil || doc.Timeout == nil || doc.Timeout.Timeout == nil || doc.Timeout.Timeout.After == nil {
+		return 0, nil
+	}
+
+	timeout, err := utils.ToDuration(doc.Timeout.Timeout.After)
+	if err != nil {
+		return 0, fmt.Errorf("invalid document timeout: %w", err)
+	}
+
+	return timeout, nil
+}
+
+// StartWorkflowOptions fills in the options used to start the document's
+// workflow. Any option that's already set takes precedence over the document.
+//
+// The task queue is the document's taskQueue metadata, or its name if not set,
+// and the workflow execution timeout is the document's timeout. The workflow
+// run timeout isn't set from the document, as an execution may consist of many
+// runs if it continues as new. The workflow ID is prefixed with the document's
+// workflowIdPrefix metadata, the static summary is its description and the
+// search attributes are its startSearchAttributes.
+func StartWorkflowOptions(doc *model.Workflow, opts client.StartWorkflowOptions) (client.StartWorkflowOptions, error) {
+	if opts.TaskQueue == "" {
+		taskQueue, err := metadata.GetTaskQueue(doc)
+		if err != nil {
+			return opts, fmt.Errorf("error getting task queue: %w", err)
+		}
+		opts.TaskQueue = taskQueue
+	}
+
+	prefix, err := metadata.GetWorkflowIDPrefix(doc)
+	if err != nil {
+		return opts, fmt.Errorf("error getting workflow id prefix: %w", err)
+	}
+	opts.ID = PrefixWorkflowID(prefix, opts.ID)
+
+	if opts.WorkflowExecutionTimeout == 0 {
+		if opts.WorkflowExecutionTimeout, err = DocumentTimeout(doc); err != nil {
+			return opts, err
+		}
+	}
+
+	if opts.StaticSummary == "" {
+		description, err := metadata.GetDescription(doc.Document.Metadata)
+		if err != nil {
+			return opts, fmt.Errorf("error getting description: %w", err)
+		}
+		opts.StaticSummary = description
+	}
+
+	if opts.TypedSearchAttributes.Size() == 0 {
+		searchAttributes, err := metadata.GetStartSearchAttributes(doc)
+		if err != nil {
+			return opts, fmt.Errorf("error getting start search attributes: %w", err)
+		}
+		opts.TypedSearchAttributes = searchAttributes
+	}
+
+	return opts, nil
+}
+
+// PrefixWorkflowID adds the prefix to the workflow ID, unless it's already
+// there. If there's a prefix but no ID, a random one is generated, as Temporal
+// would otherwise generate an ID without the prefix.
+func PrefixWorkflowID(prefix, id string) string {
+	if prefix == "" {
+		return id
+	}
+
+	if id == "" {
+		id = uuid.NewString()
+	} else if strings.HasPrefix(id, prefix) {
+		return id
+	}
+
+	return prefix + id
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"sigs.k8s.io/yaml"
+)
+
+func TestStartWorkflowOptions(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata string
+		Timeout  string
+		Opts     client.StartWorkflowOptions
+		Expected client.StartWorkflowOptions
+		Error    string
+	}{
+		{
+			Name: "No document timeout",
+			Expected: client.StartWorkflowOptions{
+				TaskQueue: "timeout",
+			},
+		},
+		{
+			Name: "Document timeout",
+			Timeout: `timeout:
+  after:
+    minutes: 5`,
+			Expected: client.StartWorkflowOptions{
+				TaskQueue:                "timeout",
+				WorkflowExecutionTimeout: 5 * time.Minute,
+			},
+		},
+		{
+			Name: "Task queue metadata",
+			Metadata: `  metadata:
+    taskQueue: some-queue`,
+			Expected: client.StartWorkflowOptions{
+				TaskQueue: "some-queue",
+			},
+		},
+		{
+			Name: "Invalid task queue metadata",
+			Metadata: `  metadata:
+    taskQueue: ""`,
+			Error: "task queue must be a non-empty string",
+		},
+		{
+			Name: "Workflow ID prefix",
+			Metadata: `  metadata:
+    workflowIdPrefix: billing-`,
+			Opts: client.StartWorkflowOptions{
+				ID: "some-id",
+			},
+			Expected: client.StartWorkflowOptions{
+				ID:        "billing-some-id",
+				TaskQueue: "timeout",
+			},
+		},
+		{
+			Name: "Invalid workflow ID prefix",
+			Metadata: `  metadata:
+    workflowIdPrefix: 123`,
+			Error: "workflow id prefix must be a string",
+		},
+		{
+			Name: "Description",
+			Metadata: `  metadata:
+    description: Charges the customer`,
+			Expected: client.StartWorkflowOptions{
+				TaskQueue:     "timeout",
+				StaticSummary: "Charges the customer",
+			},
+		},
+		{
+			Name: "Invalid description",
+			Metadata: `  metadata:
+    description: [hello]`,
+			Error: "description must be a string",
+		},
+		{
+			Name: "Start search attributes",
+			Metadata: `  metadata:
+    startSearchAttributes:
+      TenantId:
+        type: keyword
+        value: acme
+      Tier:
+        type: int
+        value: 2
+      Regions:
+        type: keywordlist
+        value: [eu, us]`,
+			Expected: client.StartWorkflowOptions{
+				TaskQueue: "timeout",
+				TypedSearchAttributes: temporal.NewSearchAttributes(
+					temporal.NewSearchAttributeKeyKeyword("TenantId").ValueSet("acme"),
+					temporal.NewSearchAttributeKeyInt64("Tier").ValueSet(2),
+					temporal.NewSearchAttributeKeyKeywordList("Regions").ValueSet([]string{"eu", "us"}),
+				),
+			},
+		},
+		{
+			Name: "Start search attribute without a value",
+			Metadata: `  metadata:
+    startSearchAttributes:
+      TenantId:
+        type: keyword`,
+			Error: "start search attribute TenantId must have a value",
+		},
+		{
+			Name: "Invalid start search attribute",
+			Metadata: `  metadata:
+    startSearchAttributes:
+      Tier:
+        type: int
+        value: lots`,
+			Error: "error setting start search attribute Tier",
+		},
+		{
+			Name: "Options take precedence",
+			Timeout: `timeout:
+  after:
+    minutes: 5`,
+			Opts: client.StartWorkflowOptions{
+				ID:                       "some-id",
+				TaskQueue:                "other",
+				WorkflowExecutionTimeout: time.Hour,
+				WorkflowRunTimeout:       time.Minute,
+			},
+			Expected: client.StartWorkflowOptions{
+				ID:                       "some-id",
+				TaskQueue:                "other",
+				WorkflowExecutionTimeout: time.Hour,
+				WorkflowRunTimeout:       time.Minute,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var doc *model.Workflow
+			assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: zigflow
+  name: timeout
+  version: 0.0.1
+`+test.Metadata+`
+`+test.Timeout+`
+do:
+  - step:
+      set:
+        hello: world`), &doc))
+
+			opts, err := zigflow.StartWorkflowOptions(doc, test.Opts)
+			if test.Error != "" {
+				assert.ErrorContains(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, opts)
+		})
+	}
+}
+
+func TestPrefixWorkflowID(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Prefix   string
+		ID       string
+		Expected string
+	}{
+		{
+			Name: "No prefix or ID",
+		},
+		{
+			Name:     "No prefix",
+			ID:       "some-id",
+			Expected: "some-id",
+		},
+		{
+			Name:     "Prefix",
+			Prefix:   "billing-",
+			ID:       "some-id",
+			Expected: "billing-some-id",
+		},
+		{
+			Name:     "Already prefixed",
+			Prefix:   "billing-",
+			ID:       "billing-some-id",
+			Expected: "billing-some-id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, zigflow.PrefixWorkflowID(test.Prefix, test.ID))
+		})
+	}
+
+	t.Run("Generated ID", func(t *testing.T) {
+		id := zigflow.PrefixWorkflowID("billing-", "")
+
+		assert.True(t, strings.HasPrefix(id, "billing-"))
+		assert.NoError(t, uuid.Validate(strings.TrimPrefix(id, "billing-")))
+	})
+}
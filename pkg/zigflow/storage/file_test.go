@@ -0,0 +1,43 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := storage.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Put(ctx, "workflow/run/task-1", []byte("hello")))
+
+	data, err := store.Get(ctx, "workflow/run/task-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), data)
+
+	_, err = store.Get(ctx, "workflow/run/unknown")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.Error(t, store.Put(ctx, "../escape", []byte("hello")))
+}
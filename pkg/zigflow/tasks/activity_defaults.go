@@ -0,0 +1,142 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ActivityDefaults are the worker's default activity options. The document's
+// and task's timeout and retryPolicy metadata take precedence. A zero value isn't set.
+type ActivityDefaults struct {
+	// How long an activity can run for, instead of 5 minutes
+	Timeout time.Duration
+	// How many times an activity is attempted, instead of unlimited
+	MaxAttempts int32
+	// How much the interval increases after each retry, instead of 2
+	BackoffCoefficient float64
+}
+
+// Validate checks the defaults are usable
+func (a ActivityDefaults) Validate() error {
+	if a.Timeout < 0 {
+		return errors.New("default activity timeout must not be negative")
+	}
+	if a.MaxAttempts < 0 {
+		return errors.New("default activity max attempts must not be negative")
+	}
+	if a.BackoffCoefficient != 0 && a.BackoffCoefficient < 1 {
+		return errors.New("default activity backoff must be at least 1")
+	}
+
+	return nil
+}
+
+// timeout returns the activity timeout. The document's timeout is used if
+// set, then the default.
+func (a ActivityDefaults) timeout(doc *model.Workflow) (time.Duration, error) {
+	if doc != nil && doc.Timeout != nil && doc.Timeout.Timeout != nil && doc.Timeout.Timeout.After != nil {
+		timeout, err := utils.ToDuration(doc.Timeout.Timeout.After)
+		if err != nil {
+			return 0, fmt.Errorf("invalid document timeout: %w", err)
+		}
+		return timeout, nil
+	}
+	if a.Timeout > 0 {
+		return a.Timeout, nil
+	}
+
+	return defaultWorkflowTimeout, nil
+}
+
+// retryPolicy fills in anything not set on the document's retry policy with
+// the defaults. If neither sets anything, nil is returned to use Temporal's
+// default policy.
+func (a ActivityDefaults) retryPolicy(policy *temporal.RetryPolicy) *temporal.RetryPolicy {
+	if a.MaxAttempts == 0 && a.BackoffCoefficient == 0 {
+		return policy
+	}
+
+	res := &temporal.RetryPolicy{}
+	if policy != nil {
+		p := *policy
+		res = &p
+	}
+	if res.MaximumAttempts == 0 {
+		res.MaximumAttempts = a.MaxAttempts
+	}
+	if res.BackoffCoefficient == 0 {
+		res.BackoffCoefficient = a.BackoffCoefficient
+	}
+
+	return res
+}
+
+// withTaskActivityOptions sets the timeout and retry policy of the task's
+// activities from its timeout and retryPolicy metadata, in place of the
+// document's. Anything not set on the task's retry policy uses the defaults.
+func (t *DoTaskBuilder) withTaskActivityOptions(ctx workflow.Context, task workflowFunc) (workflow.Context, error) {
+	m := task.GetTask().GetBase().Metadata
+
+	ao := workflow.GetActivityOptions(ctx)
+
+	timeout, err := metadata.GetTimeout(m, 0)
+	if err != nil {
+		return nil, newValidationError("Invalid timeout metadata", err, task.Name)
+	}
+	if timeout > 0 {
+		ao.StartToCloseTimeout = timeout
+	}
+
+	policy, err := metadata.GetRetryPolicy(m)
+	if err != nil {
+		return nil, newValidationError("Invalid retry policy metadata", err, task.Name)
+	}
+	if policy != nil {
+		ao.RetryPolicy = t.activityDefaults.retryPolicy(policy)
+	}
+
+	return workflow.WithActivityOptions(ctx, ao), nil
+}
+
+// validateTaskActivityOptions checks that the timeout and retryPolicy metadata
+// are valid
+func validateTaskActivityOptions(taskName string, task model.Task) error {
+	m := task.GetBase().Metadata
+
+	timeout, err := metadata.GetTimeout(m, 0)
+	if err != nil {
+		return fmt.Errorf("invalid timeout metadata for task %s: %w", taskName, err)
+	}
+	if timeout < 0 {
+		return fmt.Errorf("timeout for task %s must not be negative", taskName)
+	}
+
+	if _, err := metadata.GetRetryPolicy(m); err != nil {
+		return fmt.Errorf("invalid retry policy metadata for task %s: %w", taskName, err)
+	}
+
+	return nil
+}
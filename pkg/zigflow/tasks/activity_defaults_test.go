@@ -0,0 +1,287 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// activityOptionsRecorder records the options that activities are started
+// with, as the test environment doesn't pass on the retry policy
+type activityOptionsRecorder struct {
+	interceptor.WorkerInterceptorBase
+
+	options []workflow.ActivityOptions
+}
+
+func (r *activityOptionsRecorder) InterceptWorkflow(
+	_ workflow.Context, next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	i := &activityOptionsRecorderInbound{recorder: r}
+	i.Next = next
+	return i
+}
+
+type activityOptionsRecorderInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+
+	recorder *activityOptionsRecorder
+}
+
+func (i *activityOptionsRecorderInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	o := &activityOptionsRecorderOutbound{recorder: i.recorder}
+	o.Next = outbound
+	return i.Next.Init(o)
+}
+
+type activityOptionsRecorderOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+
+	recorder *activityOptionsRecorder
+}
+
+func (o *activityOptionsRecorderOutbound) ExecuteActivity(ctx workflow.Context, activityType string, args ...any) workflow.Future {
+	o.recorder.options = append(o.recorder.options, workflow.GetActivityOptions(ctx))
+	return o.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func TestActivityDefaults(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/defaults", httpmock.NewStringResponder(http.StatusOK, "ok"))
+
+	defaults := ActivityDefaults{
+		Timeout:            time.Minute,
+		MaxAttempts:        3,
+		BackoffCoefficient: 1.5,
+	}
+
+	tests := []struct {
+		Name            string
+		Metadata        string
+		TaskMetadata    string
+		ExpectedTimeout time.Duration
+		ExpectedPolicy  *temporal.RetryPolicy
+	}{
+		{
+			Name:            "Defaults",
+			ExpectedTimeout: time.Minute,
+			ExpectedPolicy: &temporal.RetryPolicy{
+				MaximumAttempts:    3,
+				BackoffCoefficient: 1.5,
+			},
+		},
+		{
+			Name: "Document overrides",
+			Metadata: `
+  metadata:
+    retryPolicy:
+      initialInterval: 2s
+      maximumAttempts: 5
+timeout:
+  after:
+    seconds: 30`,
+			ExpectedTimeout: 30 * time.Second,
+			ExpectedPolicy: &temporal.RetryPolicy{
+				InitialInterval:    2 * time.Second,
+				MaximumAttempts:    5,
+				BackoffCoefficient: 1.5,
+			},
+		},
+		{
+			Name: "Task overrides",
+			Metadata: `
+  metadata:
+    retryPolicy:
+      initialInterval: 2s
+      maximumAttempts: 5
+timeout:
+  after:
+    seconds: 30`,
+			TaskMetadata: `
+            metadata:
+              timeout: 10s
+              retryPolicy:
+                maximumInterval: 1m`,
+			ExpectedTimeout: 10 * time.Second,
+			ExpectedPolicy: &temporal.RetryPolicy{
+				MaximumInterval:    time.Minute,
+				MaximumAttempts:    3,
+				BackoffCoefficient: 1.5,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			// The call is in a try task, so runs in a child workflow
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: defaults
+  version: 0.0.1`+test.Metadata+`
+do:
+  - attempt:
+      try:
+        - get:`+test.TaskMetadata+`
+            call: http
+            with:
+              method: get
+              endpoint: https://example.com/defaults
+      catch:
+        do:
+          - recover:
+              set:
+                failed: true`)
+			env := newTestEnvironment(t, doc, DoTaskOpts{ActivityDefaults: defaults})
+
+			recorder := &activityOptionsRecorder{}
+			env.SetWorkerOptions(worker.Options{
+				Interceptors: []interceptor.WorkerInterceptor{recorder},
+			})
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			assert.Len(t, recorder.options, 1)
+			assert.Equal(t, test.ExpectedTimeout, recorder.options[0].StartToCloseTimeout)
+			assert.Equal(t, test.ExpectedPolicy, recorder.options[0].RetryPolicy)
+		})
+	}
+}
+
+func TestActivityDefaultsValidate(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Defaults ActivityDefaults
+		Error    string
+	}{
+		{
+			Name: "Unset",
+		},
+		{
+			Name: "Valid",
+			Defaults: ActivityDefaults{
+				Timeout:            time.Minute,
+				MaxAttempts:        3,
+				BackoffCoefficient: 1,
+			},
+		},
+		{
+			Name:     "Negative timeout",
+			Defaults: ActivityDefaults{Timeout: -time.Second},
+			Error:    "default activity timeout must not be negative",
+		},
+		{
+			Name:     "Negative max attempts",
+			Defaults: ActivityDefaults{MaxAttempts: -1},
+			Error:    "default activity max attempts must not be negative",
+		},
+		{
+			Name:     "Backoff less than 1",
+			Defaults: ActivityDefaults{BackoffCoefficient: 0.5},
+			Error:    "default activity backoff must be at least 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Defaults.Validate()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+}
+
+func TestTaskActivityOptionsValidation(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata string
+		Error    string
+	}{
+		{
+			Name: "Valid",
+			Metadata: `
+        timeout: 10s
+        retryPolicy:
+          maximumAttempts: 2`,
+		},
+		{
+			Name: "Invalid timeout",
+			Metadata: `
+        timeout: soon`,
+			Error: "invalid timeout metadata for task task",
+		},
+		{
+			Name: "Negative timeout",
+			Metadata: `
+        timeout: -1s`,
+			Error: "timeout for task task must not be negative",
+		},
+		{
+			Name: "Invalid retry policy",
+			Metadata: `
+        retryPolicy:
+          backoffCoefficient: 0.5`,
+			Error: "invalid retry policy metadata for task task",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: options
+  version: 0.0.1
+do:
+  - task:
+      metadata:`+test.Metadata+`
+      set:
+        hello: world`)
+
+			builder, err := NewDoTaskBuilder(nil, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc, DoTaskOpts{
+				DisableRegisterWorkflow: true,
+			})
+			assert.NoError(t, err)
+
+			_, err = builder.Build()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, test.Error)
+		})
+	}
+}
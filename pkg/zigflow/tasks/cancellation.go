@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ErrorTypeCanceled is the type of the error returned when a do task is
+// cancelled by its cancel signal. This matches the type given to caught
+// cancellation errors.
+const ErrorTypeCanceled = "Canceled"
+
+// cancelOnSignal runs the tasks in a cancellation scope, which is cancelled
+// when the signal is received by the workflow. Any running activities, timers
+// and child workflows are cancelled, the remaining tasks are skipped and a
+// Canceled error is returned, which can be caught by a try task.
+//
+// The signal must be sent to the workflow that runs the tasks. If the do task
+// is inside a try task, this is the try's child workflow. A signal received
+// before the tasks start cancels them as soon as they do.
+func cancelOnSignal(signal string, wf TemporalWorkflowFunc) TemporalWorkflowFunc {
+	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
+		logger := workflow.GetLogger(ctx)
+
+		scopeCtx, cancel := workflow.WithCancel(ctx)
+		defer cancel()
+
+		var cancelled bool
+		workflow.Go(scopeCtx, func(ctx workflow.Context) {
+			s := workflow.NewSelector(ctx)
+			s.AddReceive(workflow.GetSignalChannel(ctx, signal), func(c workflow.ReceiveChannel, _ bool) {
+				c.Receive(ctx, nil)
+
+				logger.Info("Cancel signal received", "signal", signal)
+				cancelled = true
+				cancel()
+			})
+			// Stop listening once the tasks have finished
+			s.AddReceive(ctx.Done(), func(workflow.ReceiveChannel, bool) {})
+			s.Select(ctx)
+		})
+
+		output, err := wf(scopeCtx, input, state)
+		if cancelled {
+			return nil, temporal.NewNonRetryableApplicationError(
+				"Tasks cancelled by signal",
+				ErrorTypeCanceled,
+				nil,
+				map[string]any{
+					"signal": signal,
+				},
+			)
+		}
+
+		return output, err
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+)
+
+const cancelWorkflow = `document:
+  dsl: 1.0.0
+  namespace: default
+  name: cancel
+  version: 0.0.1
+do:
+  - work:
+      metadata:
+        cancelSignal: stop
+      do:
+        - waitForWork:
+            wait:
+              hours: 1
+        - finished:
+            export:
+              as: finished
+            set:
+              done: true
+  - after:
+      export:
+        as: after
+      set:
+        done: true`
+
+func TestCancelSignal(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Signal   bool
+		Expected map[string]any
+	}{
+		{
+			Name: "Not cancelled",
+			Expected: map[string]any{
+				"finished": map[string]any{"done": true},
+				"after":    map[string]any{"done": true},
+			},
+		},
+		{
+			Name:   "Cancelled",
+			Signal: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, cancelWorkflow)
+			env := newTestEnvironment(t, doc)
+
+			if test.Signal {
+				env.RegisterDelayedCallback(func() {
+					env.SignalWorkflow("stop", nil)
+				}, time.Minute)
+			}
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Signal {
+				var appErr *temporal.ApplicationError
+				assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+				assert.Equal(t, ErrorTypeCanceled, appErr.Type())
+				return
+			}
+
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+}
+
+func TestCancelSignalCaught(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: cancel
+  version: 0.0.1
+do:
+  - attempt:
+      export:
+        as: result
+      try:
+        - start:
+            set:
+              started: true
+        - work:
+            metadata:
+              cancelSignal: stop
+            do:
+              - waitForWork:
+                  wait:
+                    hours: 1
+              - finished:
+                  set:
+                    done: true
+      catch:
+        do:
+          - handled:
+              export:
+                as: handled
+              set:
+                type: ${ .data.error.type }
+                signal: ${ .data.error.details.signal }`)
+	env := newTestEnvironment(t, doc)
+
+	// The try block runs as a child workflow, which receives the signal
+	env.RegisterDelayedCallback(func() {
+		assert.NoError(t, env.SignalWorkflowByID("default-test-workflow-id_attempt_try", "stop", nil))
+	}, time.Minute)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"handled": map[string]any{
+			"type":   ErrorTypeCanceled,
+			"signal": "stop",
+		},
+	}, result["result"])
+}
+
+func TestCancelSignalValidation(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: cancel
+  version: 0.0.1
+do:
+  - work:
+      metadata:
+        cancelSignal: true
+      do:
+        - finished:
+            set:
+              done: true`)
+
+	builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "invalid cancel signal metadata for task work: cancel signal must be a non-empty string")
+}
@@ -19,3 +19,16 @@ package tasks
 import "time"
 
 const defaultWorkflowTimeout = time.Minute * 5
+
+// defaultTryRetryAttempts is used if a try task's retry policy sets no limits
+// to avoid retrying forever
+const defaultTryRetryAttempts = 3
+
+// TaskSkippedKey is set to true in the state's data, under the task's name,
+// when a task is skipped by its if statement
+const TaskSkippedKey = "__skipped"
+
+// TaskVersionsKey is the key in the state's data that holds the version of
+// each versioned task, keyed by its change ID. It's reserved so it doesn't
+// overwrite the workflow's own data.
+const TaskVersionsKey = "__versions"
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// wallClockFuncs are the time functions that read or wait on the wall clock.
+// Workflow code must use workflow.Now, workflow.Sleep and workflow.NewTimer so
+// that it replays in the same way.
+var wallClockFuncs = map[string]bool{
+	"After":     true,
+	"AfterFunc": true,
+	"NewTicker": true,
+	"NewTimer":  true,
+	"Now":       true,
+	"Since":     true,
+	"Sleep":     true,
+	"Tick":      true,
+	"Until":     true,
+}
+
+// wallClockAllowed are the functions that only run inside an activity, so can
+// use the wall clock
+var wallClockAllowed = map[string]bool{
+	"callHTTPAction":  true,
+	"checkHTTPStatus": true,
+}
+
+// TestNoWallClockInWorkflowCode checks the packages that build the workflows
+// never use the wall clock outside of activity code
+func TestNoWallClockInWorkflowCode(t *testing.T) {
+	for _, dir := range []string{".", "../metadata", "../../utils"} {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		assert.NoError(t, err)
+
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, file, nil, 0)
+			assert.NoError(t, err)
+
+			timePkg := importName(f, "time")
+			if timePkg == "" {
+				continue
+			}
+
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || wallClockAllowed[fn.Name.Name] {
+					continue
+				}
+
+				ast.Inspect(fn, func(n ast.Node) bool {
+					sel, ok := n.(*ast.SelectorExpr)
+					if !ok {
+						return true
+					}
+					if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == timePkg && wallClockFuncs[sel.Sel.Name] {
+						t.Errorf("%s: %s uses time.%s - use the workflow's clock or allow it if it's activity code",
+							fset.Position(sel.Pos()), fn.Name.Name, sel.Sel.Name)
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+// importName returns the name the file imports the package as, or an empty
+// string if it's not imported
+func importName(f *ast.File, path string) string {
+	for _, imp := range f.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err != nil || p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return filepath.Base(path)
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+)
+
+// ErrorTypeValidation is the Temporal error type of a failed validation
+const ErrorTypeValidation = "Validation"
+
+// ErrorTypeNotFound is the Temporal error type when another workflow that a
+// task targets doesn't exist
+const ErrorTypeNotFound = "NotFound"
+
+// ErrorEnvelope is the first detail of the ApplicationError returned by a
+// raise task or a failed validation. This is the Serverless Workflow error
+// model, so a caller can decode every failure in the same way.
+type ErrorEnvelope struct {
+	Type     string `json:"type"`
+	Status   int    `json:"status"`
+	Title    string `json:"title,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// The fields which failed JSON schema validation
+	Errors []utils.SchemaFieldError `json:"errors,omitempty"`
+}
+
+// Message is the detail, falling back to the title and then the type
+func (e *ErrorEnvelope) Message() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+	if e.Title != "" {
+		return e.Title
+	}
+	return e.Type
+}
+
+// newValidationError returns a non-retryable Validation error. The message is
+// the envelope's title and the cause is its detail. The instance is the task
+// that failed, if it's known. Any other details follow the envelope.
+func newValidationError(msg string, cause error, instance string, details ...any) error {
+	envelope := &ErrorEnvelope{
+		Type:     model.ErrorTypeValidation,
+		Status:   http.StatusBadRequest,
+		Title:    msg,
+		Instance: instance,
+	}
+	if cause != nil {
+		envelope.Detail = cause.Error()
+	}
+
+	// Use the Serverless Workflow error's detail rather than its summary
+	var modelErr *model.Error
+	if errors.As(cause, &modelErr) && modelErr.Detail != nil {
+		envelope.Detail = modelErr.Detail.String()
+	}
+
+	var schemaErr *utils.SchemaValidationError
+	if errors.As(cause, &schemaErr) {
+		envelope.Errors = schemaErr.Errors
+	}
+
+	return temporal.NewNonRetryableApplicationError(msg, ErrorTypeValidation, cause, append([]any{envelope}, details...)...)
+}
+
+// newNotFoundError returns a non-retryable NotFound error for another workflow
+// that doesn't exist. The envelope is a communication error with a 404 status,
+// so a catch can filter on either.
+func newNotFoundError(msg string, cause error, instance string) error {
+	envelope := &ErrorEnvelope{
+		Type:     model.ErrorTypeCommunication,
+		Status:   http.StatusNotFound,
+		Title:    msg,
+		Instance: instance,
+	}
+	if cause != nil {
+		envelope.Detail = cause.Error()
+	}
+
+	return temporal.NewNonRetryableApplicationError(msg, ErrorTypeNotFound, cause, envelope)
+}
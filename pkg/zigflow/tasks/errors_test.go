@@ -0,0 +1,138 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Workflow string
+		Input    any
+		Type     string
+		Expected ErrorEnvelope
+	}{
+		{
+			Name: "Raised error",
+			Workflow: `do:
+  - fail:
+      raise:
+        error:
+          type: https://example.com/errors/not-found
+          status: 404
+          title: Not found
+          detail: ${ "No user " + .input.userId }`,
+			Input: map[string]any{"userId": "abc123"},
+			Type:  "https://example.com/errors/not-found",
+			Expected: ErrorEnvelope{
+				Type:     "https://example.com/errors/not-found",
+				Status:   404,
+				Title:    "Not found",
+				Detail:   "No user abc123",
+				Instance: "default-test-workflow-id",
+			},
+		},
+		{
+			Name: "Raised non-retryable error",
+			Workflow: `do:
+  - fail:
+      raise:
+        error:
+          type: https://temporal.io/errors/nonretryable
+          status: 500
+          title: Broken`,
+			Type: temporaErrlNonRetryable,
+			Expected: ErrorEnvelope{
+				Type:     temporaErrlNonRetryable,
+				Status:   500,
+				Title:    "Broken",
+				Instance: "default-test-workflow-id",
+			},
+		},
+		{
+			Name: "Input validation",
+			Workflow: `input:
+  schema:
+    format: json
+    document:
+      type: object
+      required:
+        - name
+do:
+  - step:
+      set:
+        name: ${ .input.name }`,
+			Input: map[string]any{},
+			Type:  ErrorTypeValidation,
+			Expected: ErrorEnvelope{
+				Type:     model.ErrorTypeValidation,
+				Status:   400,
+				Title:    "Workflow input did not meet JSON schema specification",
+				Detail:   "JSON schema validation failed:\n- (root): name is required",
+				Instance: "envelope",
+				Errors: []utils.SchemaFieldError{
+					{Pointer: "/name", Message: "name is required"},
+				},
+			},
+		},
+		{
+			Name: "Runtime validation",
+			Workflow: `do:
+  - pause:
+      wait: ${ 5 }`,
+			Type: ErrorTypeValidation,
+			Expected: ErrorEnvelope{
+				Type:     model.ErrorTypeValidation,
+				Status:   400,
+				Title:    "Wait must resolve to a string",
+				Instance: "pause",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: envelope
+  version: 0.0.1
+`+test.Workflow)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, test.Input, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			var appErr *temporal.ApplicationError
+			assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+			assert.Equal(t, test.Type, appErr.Type())
+
+			var envelope ErrorEnvelope
+			assert.NoError(t, appErr.Details(&envelope))
+			assert.Equal(t, test.Expected, envelope)
+		})
+	}
+}
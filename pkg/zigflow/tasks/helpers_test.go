@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+	"sigs.k8s.io/yaml"
+)
+
+// testWorker allows the task builders to register workflows and activities
+// with the test environment
+type testWorker struct {
+	worker.Worker
+
+	env *testsuite.TestWorkflowEnvironment
+}
+
+func (w *testWorker) RegisterWorkflowWithOptions(wf any, opts workflow.RegisterOptions) {
+	w.env.RegisterWorkflowWithOptions(wf, opts)
+}
+
+func (w *testWorker) RegisterActivity(a any) {
+	w.env.RegisterActivity(a)
+}
+
+// loadWorkflow converts the YAML into a workflow document
+func loadWorkflow(t *testing.T, data string) *model.Workflow {
+	t.Helper()
+
+	var doc *model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(data), &doc))
+
+	return doc
+}
+
+// newTestEnvironment builds the document's workflows against a new test
+// environment
+func newTestEnvironment(t *testing.T, doc *model.Workflow, opts ...DoTaskOpts) *testsuite.TestWorkflowEnvironment {
+	t.Helper()
+
+	return newTestEnvironmentForSuite(t, &testsuite.WorkflowTestSuite{}, doc, opts...)
+}
+
+// newTestEnvironmentForSuite builds the document's workflows against a new test
+// environment from the suite, allowing it to be configured
+func newTestEnvironmentForSuite(
+	t *testing.T,
+	s *testsuite.WorkflowTestSuite,
+	doc *model.Workflow,
+	opts ...DoTaskOpts,
+) *testsuite.TestWorkflowEnvironment {
+	t.Helper()
+
+	env := s.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{NewSecretsInterceptor()},
+	})
+
+	w := &testWorker{env: env}
+
+	// Inline workflows are built first, in the same way as the worker
+	inline, err := NewInlineWorkflowBuilders(w, doc, opts...)
+	assert.NoError(t, err)
+	for _, b := range inline {
+		assert.NoError(t, b.PostLoad())
+		_, err = b.Build()
+		assert.NoError(t, err)
+	}
+
+	builder, err := NewDoTaskBuilder(w, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc, opts...)
+	assert.NoError(t, err)
+
+	assert.NoError(t, builder.PostLoad())
+
+	_, err = builder.Build()
+	assert.NoError(t, err)
+
+	for _, a := range ActivitiesList() {
+		env.RegisterActivity(a)
+	}
+
+	return env
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+)
+
+// resolveAuthentication returns the task with its endpoint's authentication
+// resolved. A policy referenced by name is replaced with the document's policy
+// from use.authentications. The task is copied so the document isn't changed.
+func (t *CallHTTPTaskBuilder) resolveAuthentication() (*model.CallHTTP, error) {
+	auth := endpointAuthentication(t.task.With.Endpoint)
+	if auth == nil {
+		return t.task, nil
+	}
+
+	if auth.Use == nil {
+		if err := validateAuthenticationPolicy(auth.AuthenticationPolicy); err != nil {
+			return nil, fmt.Errorf("invalid authentication for task %s: %w", t.GetTaskName(), err)
+		}
+		return t.task, nil
+	}
+
+	var policy *model.AuthenticationPolicy
+	if t.doc != nil && t.doc.Use != nil {
+		policy = t.doc.Use.Authentications[*auth.Use]
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("unknown authentication %s for task %s", *auth.Use, t.GetTaskName())
+	}
+	if err := validateAuthenticationPolicy(policy); err != nil {
+		return nil, fmt.Errorf("invalid authentication %s for task %s: %w", *auth.Use, t.GetTaskName(), err)
+	}
+
+	config := *t.task.With.Endpoint.EndpointConfig
+	config.Authentication = &model.ReferenceableAuthenticationPolicy{AuthenticationPolicy: policy}
+
+	task := *t.task
+	task.With.Endpoint = &model.Endpoint{EndpointConfig: &config}
+
+	return &task, nil
+}
+
+// endpointAuthentication returns the endpoint's authentication, if it has any
+func endpointAuthentication(endpoint *model.Endpoint) *model.ReferenceableAuthenticationPolicy {
+	if endpoint == nil || endpoint.EndpointConfig == nil {
+		return nil
+	}
+	return endpoint.EndpointConfig.Authentication
+}
+
+// validateAuthenticationPolicy checks the policy can be used. Only basic and
+// bearer authentication are supported, and their credentials must be set on
+// the policy rather than referencing a secret.
+func validateAuthenticationPolicy(policy *model.AuthenticationPolicy) error {
+	switch {
+	case policy == nil:
+		return errors.New("authentication policy must be set")
+	case policy.Basic != nil:
+		if policy.Basic.Use != "" {
+			return errors.New("basic authentication secrets aren't supported")
+		}
+	case policy.Bearer != nil:
+		if policy.Bearer.Use != "" {
+			return errors.New("bearer authentication secrets aren't supported")
+		}
+	default:
+		return errors.New("only basic and bearer authentication are supported")
+	}
+
+	return nil
+}
+
+// addAuthentication sets the request's Authorization header from the
+// endpoint's authentication. Any reference has already been resolved.
+func addAuthentication(req *http.Request, endpoint *model.Endpoint) error {
+	auth := endpointAuthentication(endpoint)
+	if auth == nil {
+		return nil
+	}
+
+	policy := auth.AuthenticationPolicy
+	if err := validateAuthenticationPolicy(policy); err != nil {
+		return newValidationError("Invalid authentication", err, "")
+	}
+
+	if policy.Basic != nil {
+		req.SetBasicAuth(policy.Basic.Username, policy.Basic.Password)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+policy.Bearer.Token)
+	}
+
+	return nil
+}
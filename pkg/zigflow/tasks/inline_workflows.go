@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/worker"
+)
+
+// NewInlineWorkflowBuilders creates a builder for each of the document's
+// inline workflows, sorted by name. Each is registered under its name when
+// it's built, so a run task can start it. As the document and its do tasks are
+// also registered by name, an inline workflow can't share their names.
+func NewInlineWorkflowBuilders(temporalWorker worker.Worker, doc *model.Workflow, opts ...DoTaskOpts) ([]*DoTaskBuilder, error) {
+	workflows, err := metadata.GetWorkflows(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflows metadata: %w", err)
+	}
+
+	var doOpts DoTaskOpts
+	if len(opts) == 1 {
+		doOpts = opts[0]
+	}
+	doOpts.ForceRegisterWorkflow = true
+
+	taken := map[string]bool{doc.Document.Name: true}
+	utils.WalkTasks(doc.Do, func(_ string, task *model.TaskItem) {
+		if task.AsDoTask() != nil {
+			taken[task.Key] = true
+		}
+	})
+
+	builders := make([]*DoTaskBuilder, 0, len(workflows))
+	for _, name := range slices.Sorted(maps.Keys(workflows)) {
+		if taken[name] {
+			return nil, fmt.Errorf("inline workflow %s has the same name as the document or a do task", name)
+		}
+
+		builder, err := NewDoTaskBuilder(temporalWorker, &model.DoTask{Do: workflows[name]}, name, doc, doOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error creating builder for inline workflow %s: %w", name, err)
+		}
+		builders = append(builders, builder)
+	}
+
+	return builders, nil
+}
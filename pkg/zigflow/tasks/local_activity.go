@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// Local activities run inside the workflow task, so must be short. They
+	// can't heartbeat and a long-running one delays the whole workflow.
+	defaultLocalActivityTimeout = 10 * time.Second
+	maxLocalActivityTimeout     = time.Minute
+	// Local activities are retried by the worker, not the server, so the
+	// retries are limited
+	localActivityMaxAttempts = 3
+)
+
+// localActivityOptions returns the options if the task's metadata asks for it
+// to be run as a local activity, otherwise nil. The timeout must be short
+// enough to run inside a workflow task.
+func localActivityOptions(taskName string, m map[string]any) (*workflow.LocalActivityOptions, error) {
+	local, err := metadata.GetLocalActivity(m)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local activity metadata for task %s: %w", taskName, err)
+	}
+	if !local {
+		return nil, nil
+	}
+
+	timeout, err := metadata.GetTimeout(m, defaultLocalActivityTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout metadata for task %s: %w", taskName, err)
+	}
+	if timeout <= 0 || timeout > maxLocalActivityTimeout {
+		return nil, fmt.Errorf(
+			"timeout for local activity task %s must be between 0 and %s, use a normal activity for longer tasks",
+			taskName, maxLocalActivityTimeout,
+		)
+	}
+
+	return &workflow.LocalActivityOptions{
+		StartToCloseTimeout: timeout,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: localActivityMaxAttempts,
+		},
+	}, nil
+}
+
+// executeActivity runs the activity as a local activity if the options are
+// given, otherwise as a normal activity
+func executeActivity(ctx workflow.Context, localOpts *workflow.LocalActivityOptions, activity any, args ...any) workflow.Future {
+	args = snapshotArgs(args)
+
+	if localOpts != nil {
+		// Use the same summary as a normal activity
+		opts := *localOpts
+		opts.Summary = workflow.GetActivityOptions(ctx).Summary
+
+		return workflow.ExecuteLocalActivity(workflow.WithLocalActivityOptions(ctx, opts), activity, args...)
+	}
+
+	return workflow.ExecuteActivity(ctx, activity, args...)
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+)
+
+func TestLocalActivityOptions(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata map[string]any
+		Expected time.Duration
+		Error    string
+	}{
+		{
+			Name:     "Not set",
+			Metadata: map[string]any{},
+		},
+		{
+			Name: "Disabled",
+			Metadata: map[string]any{
+				"localActivity": false,
+				"timeout":       "5m",
+			},
+		},
+		{
+			Name: "Default timeout",
+			Metadata: map[string]any{
+				"localActivity": true,
+			},
+			Expected: defaultLocalActivityTimeout,
+		},
+		{
+			Name: "Custom timeout",
+			Metadata: map[string]any{
+				"localActivity": true,
+				"timeout":       "30s",
+			},
+			Expected: 30 * time.Second,
+		},
+		{
+			Name: "Timeout too long",
+			Metadata: map[string]any{
+				"localActivity": true,
+				"timeout":       "5m",
+			},
+			Error: "timeout for local activity task task must be between 0 and 1m0s, use a normal activity for longer tasks",
+		},
+		{
+			Name: "Invalid local activity",
+			Metadata: map[string]any{
+				"localActivity": "yes",
+			},
+			Error: "invalid local activity metadata for task task: local activity must be a boolean",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			opts, err := localActivityOptions("task", test.Metadata)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+			assert.NoError(t, err)
+
+			if test.Expected == 0 {
+				assert.Nil(t, opts)
+				return
+			}
+			assert.Equal(t, test.Expected, opts.StartToCloseTimeout)
+			assert.Equal(t, int32(localActivityMaxAttempts), opts.RetryPolicy.MaximumAttempts)
+		})
+	}
+}
+
+func TestCallHTTPLocalActivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write([]byte(`{"hello":"world"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		Name  string
+		Local bool
+	}{
+		{
+			Name:  "Local activity",
+			Local: true,
+		},
+		{
+			Name: "Activity",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        localActivity: %t
+      export:
+        as: response
+      call: http
+      with:
+        method: get
+        endpoint: %s`, test.Local, server.URL))
+			env := newTestEnvironment(t, doc)
+
+			localActivities := 0
+			env.SetOnLocalActivityStartedListener(func(*activity.Info, context.Context, []any) {
+				localActivities++
+			})
+			activities := 0
+			env.SetOnActivityStartedListener(func(*activity.Info, context.Context, converter.EncodedValues) {
+				activities++
+			})
+
+			// The test environment can't pass nil arguments to local activities
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			if test.Local {
+				assert.Equal(t, 1, localActivities)
+				assert.Zero(t, activities)
+			} else {
+				assert.Zero(t, localActivities)
+				assert.Equal(t, 1, activities)
+			}
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, map[string]any{"hello": "world"}, result["response"])
+		})
+	}
+}
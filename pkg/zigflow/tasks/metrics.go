@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	MetricTaskExecutions = "zigflow_task_executions"
+	MetricTaskFailures   = "zigflow_task_failures"
+	MetricTaskDuration   = "zigflow_task_duration"
+)
+
+// Per-task metrics are labelled with the task name, which can be high
+// cardinality for large workflows
+var taskMetricsEnabled = true
+
+// SetTaskMetrics enables or disables the per-task metrics
+func SetTaskMetrics(enabled bool) {
+	taskMetricsEnabled = enabled
+}
+
+// TaskType returns the task's type in the same form as the DSL, eg "set"
+func TaskType(task model.Task) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", task), "*model.")
+	name = strings.TrimSuffix(name, "Task")
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// recordTaskMetrics runs the task, counting the execution, any failure and
+// how long it took. These go through the workflow's metrics handler so they
+// use the same prefix as the worker metrics and aren't emitted on replay.
+func recordTaskMetrics(ctx workflow.Context, task workflowFunc, fn func() (any, error)) (any, error) {
+	if !taskMetricsEnabled {
+		return fn()
+	}
+
+	handler := workflow.GetMetricsHandler(ctx).WithTags(map[string]string{
+		"task_name": task.Name,
+		"task_type": TaskType(task.GetTask()),
+	})
+
+	start := workflow.Now(ctx)
+	res, err := fn()
+
+	handler.Counter(MetricTaskExecutions).Inc(1)
+	handler.Timer(MetricTaskDuration).Record(workflow.Now(ctx).Sub(start))
+	if err != nil && !temporal.IsCanceledError(err) {
+		handler.Counter(MetricTaskFailures).Inc(1)
+	}
+
+	return res, err
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// capturingHandler records the counters and timers by name and task name
+type capturingHandler struct {
+	mu       *sync.Mutex
+	tags     map[string]string
+	counters map[string]int64
+	timers   map[string]int
+}
+
+type (
+	counterFunc func(int64)
+	gaugeFunc   func(float64)
+	timerFunc   func(time.Duration)
+)
+
+func (f counterFunc) Inc(i int64)          { f(i) }
+func (f gaugeFunc) Update(v float64)       { f(v) }
+func (f timerFunc) Record(d time.Duration) { f(d) }
+
+func newCapturingHandler() *capturingHandler {
+	return &capturingHandler{
+		mu:       &sync.Mutex{},
+		tags:     map[string]string{},
+		counters: map[string]int64{},
+		timers:   map[string]int{},
+	}
+}
+
+func (h *capturingHandler) key(name string) string {
+	return name + ":" + h.tags["task_name"] + ":" + h.tags["task_type"]
+}
+
+func (h *capturingHandler) WithTags(tags map[string]string) client.MetricsHandler {
+	merged := map[string]string{}
+	for k, v := range h.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+
+	return &capturingHandler{mu: h.mu, tags: merged, counters: h.counters, timers: h.timers}
+}
+
+func (h *capturingHandler) Counter(name string) client.MetricsCounter {
+	return counterFunc(func(i int64) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.counters[h.key(name)] += i
+	})
+}
+
+func (h *capturingHandler) Gauge(name string) client.MetricsGauge {
+	return gaugeFunc(func(float64) {})
+}
+
+func (h *capturingHandler) Timer(name string) client.MetricsTimer {
+	return timerFunc(func(time.Duration) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.timers[h.key(name)]++
+	})
+}
+
+func TestTaskMetrics(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: metrics
+  version: 0.0.1
+do:
+  - step:
+      set:
+        hello: world
+  - fail:
+      raise:
+        error:
+          type: https://serverlessworkflow.io/spec/1.0.0/errors/runtime
+          status: 500
+          title: Failure`)
+
+	tests := []struct {
+		Name     string
+		Enabled  bool
+		Counters map[string]int64
+		Timers   map[string]int
+	}{
+		{
+			Name:    "enabled",
+			Enabled: true,
+			Counters: map[string]int64{
+				MetricTaskExecutions + ":step:set":   1,
+				MetricTaskExecutions + ":fail:raise": 1,
+				MetricTaskFailures + ":fail:raise":   1,
+			},
+			Timers: map[string]int{
+				MetricTaskDuration + ":step:set":   1,
+				MetricTaskDuration + ":fail:raise": 1,
+			},
+		},
+		{
+			Name:     "disabled",
+			Counters: map[string]int64{},
+			Timers:   map[string]int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			SetTaskMetrics(test.Enabled)
+			defer SetTaskMetrics(true)
+
+			handler := newCapturingHandler()
+
+			var s testsuite.WorkflowTestSuite
+			s.SetMetricsHandler(handler)
+			env := s.NewTestWorkflowEnvironment()
+
+			builder, err := NewDoTaskBuilder(&testWorker{env: env}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+			_, err = builder.Build()
+			assert.NoError(t, err)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.Error(t, env.GetWorkflowError())
+
+			// Ignore the SDK's own metrics
+			counters := map[string]int64{}
+			for k, v := range handler.counters {
+				if strings.HasPrefix(k, "zigflow_") {
+					counters[k] = v
+				}
+			}
+			timers := map[string]int{}
+			for k, v := range handler.timers {
+				if strings.HasPrefix(k, "zigflow_") {
+					timers[k] = v
+				}
+			}
+
+			assert.Equal(t, test.Counters, counters)
+			assert.Equal(t, test.Timers, timers)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	onFailureTaskName = "onFailure"
+	// The hook's activity, including retries, must finish within this unless
+	// the hook sets its own timeout
+	defaultOnFailureTimeout = 30 * time.Second
+)
+
+// onFailureHook is the document's on failure HTTP call
+type onFailureHook struct {
+	fn      TemporalWorkflowFunc
+	timeout time.Duration
+}
+
+// buildOnFailure builds the document's on failure hook. This is only run by
+// the document's workflow, so returns nil for any other do task.
+func (t *DoTaskBuilder) buildOnFailure() (*onFailureHook, error) {
+	if t.doc == nil || t.GetTaskName() != t.doc.Document.Name {
+		return nil, nil
+	}
+
+	task, err := metadata.GetOnFailure(t.doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid on failure metadata: %w", err)
+	}
+	if task == nil {
+		return nil, nil
+	}
+
+	timeout, err := metadata.GetTimeout(task.Metadata, defaultOnFailureTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout metadata for on failure: %w", err)
+	}
+
+	builder, err := NewCallHTTPTaskBuilder(t.temporalWorker, task, onFailureTaskName, t.doc)
+	if err != nil {
+		return nil, fmt.Errorf("error creating on failure builder: %w", err)
+	}
+	if err := builder.PostLoad(); err != nil {
+		return nil, err
+	}
+
+	fn, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("error building on failure: %w", err)
+	}
+
+	return &onFailureHook{
+		fn:      fn,
+		timeout: timeout,
+	}, nil
+}
+
+// runOnFailure calls the on failure hook with the error in ".data.error". Any
+// error from the hook is logged so the workflow fails with the original error.
+// Cancellations and continue-as-new aren't failures, so don't call the hook.
+func (t *DoTaskBuilder) runOnFailure(ctx workflow.Context, input any, state *utils.State, cause error) {
+	if t.onFailure == nil || temporal.IsCanceledError(cause) || workflow.IsContinueAsNewError(cause) {
+		return
+	}
+
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Running on failure hook", "error", cause)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    t.onFailure.timeout,
+		ScheduleToCloseTimeout: t.onFailure.timeout,
+		RetryPolicy:            t.retryPolicy,
+		Summary:                onFailureTaskName,
+	})
+
+	hookState := state.Clone().AddData(map[string]any{
+		"error": newCaughtError(cause),
+	})
+
+	if _, err := t.onFailure.fn(ctx, input, hookState); err != nil {
+		logger.Error("Error running on failure hook", "error", err)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnFailure(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Fail       bool
+		HookStatus int
+		Expected   []map[string]any
+	}{
+		{
+			Name:       "Success",
+			HookStatus: http.StatusOK,
+			Expected:   []map[string]any{},
+		},
+		{
+			Name:       "Failure",
+			Fail:       true,
+			HookStatus: http.StatusOK,
+			Expected: []map[string]any{
+				{
+					"orderId": "order1",
+					"message": "Order not found",
+				},
+			},
+		},
+		{
+			Name:       "Failing hook",
+			Fail:       true,
+			HookStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			calls := make([]map[string]any, 0)
+			httpmock.RegisterResponder(http.MethodPost, "https://example.com/alert", func(req *http.Request) (*http.Response, error) {
+				var body map[string]any
+				assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+				calls = append(calls, body)
+				return httpmock.NewStringResponse(test.HookStatus, ""), nil
+			})
+
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: onFailure
+  version: 0.0.1
+  metadata:
+    onFailure:
+      metadata:
+        timeout: 5s
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/alert
+        body:
+          orderId: ${ .input.orderId }
+          message: ${ .data.error.message }
+do:
+  - check:
+      if: ${ .input.fail }
+      raise:
+        error:
+          type: https://serverlessworkflow.io/spec/1.0.0/errors/runtime
+          status: 404
+          title: Order not found
+  - finish:
+      set:
+        done: true`)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{
+				"orderId": "order1",
+				"fail":    test.Fail,
+			}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Expected != nil {
+				assert.Equal(t, test.Expected, calls)
+			} else {
+				// Retried until the hook's timeout
+				assert.NotEmpty(t, calls)
+			}
+
+			if !test.Fail {
+				assert.NoError(t, env.GetWorkflowError())
+				return
+			}
+
+			// The original error is returned, even if the hook fails
+			assert.ErrorContains(t, env.GetWorkflowError(), "Order not found")
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/storage"
+	"go.temporal.io/sdk/activity"
+)
+
+// Large activity results are offloaded to this store and replaced with a
+// reference. This keeps the history and the state small, as the state is
+// passed to child workflows and activities, across continue-as-new and back
+// to the caller.
+var outputStore struct {
+	store     storage.Store
+	threshold int
+}
+
+// SetOutputStore enables offloading of any activity result larger than the
+// threshold in bytes. The results are only resolved inside activities, so the
+// workflow only ever sees the references.
+func SetOutputStore(store storage.Store, threshold int) {
+	outputStore.store = store
+	outputStore.threshold = threshold
+}
+
+// offloadResult moves an activity's result to the store if it's larger than
+// the threshold, returning the reference to return in its place. This runs
+// inside the activity, so only the reference is recorded in the history.
+func offloadResult(ctx context.Context, result any) (any, error) {
+	if outputStore.store == nil || outputStore.threshold <= 0 || result == nil {
+		return result, nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("error serialising result: %w", err)
+	}
+	if len(data) <= outputStore.threshold {
+		return result, nil
+	}
+
+	// The activity ID is unique within the run and reused when it's retried
+	info := activity.GetInfo(ctx)
+	key := strings.Join([]string{info.WorkflowExecution.ID, info.WorkflowExecution.RunID, info.ActivityID}, "/")
+
+	logger := activity.GetLogger(ctx)
+	logger.Debug("Offloading result", "key", key, "size", len(data))
+	if err := outputStore.store.Put(ctx, key, data); err != nil {
+		logger.Error("Error offloading result", "key", key, "error", err)
+		return nil, fmt.Errorf("error offloading result: %w", err)
+	}
+
+	return utils.NewOutputReference(key, len(data)), nil
+}
+
+// resolveOutputs retrieves the offloaded outputs from the store
+func resolveOutputs(ctx context.Context, refs []string) (map[string]any, error) {
+	logger := activity.GetLogger(ctx)
+
+	if outputStore.store == nil {
+		return nil, fmt.Errorf("no output store configured")
+	}
+
+	resolved := make(map[string]any, len(refs))
+	for _, ref := range refs {
+		logger.Debug("Resolving output", "key", ref)
+
+		data, err := outputStore.store.Get(ctx, ref)
+		if err != nil {
+			logger.Error("Error resolving output", "key", ref, "error", err)
+			return nil, fmt.Errorf("error resolving output: %w", err)
+		}
+
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("error deserialising output: %w", err)
+		}
+
+		resolved[ref] = value
+	}
+
+	return resolved, nil
+}
+
+// resolveStateOutputs resolves any offloaded outputs inside an activity so
+// that expressions evaluated in the activity can use them. They're never
+// resolved by the workflow, as its commands' results are written to the
+// history, which is what the offload avoids.
+func resolveStateOutputs(ctx context.Context, state *utils.State) error {
+	refs := state.UnresolvedReferences()
+	if len(refs) == 0 {
+		return nil
+	}
+
+	resolved, err := resolveOutputs(ctx, refs)
+	if err != nil {
+		return err
+	}
+
+	for ref, value := range resolved {
+		state.SetResolvedReference(ref, value)
+	}
+
+	return nil
+}
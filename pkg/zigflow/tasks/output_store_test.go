@@ -0,0 +1,282 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/storage"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+)
+
+const offloadWorkflow = `document:
+  dsl: 1.0.0
+  namespace: default
+  name: offload
+  version: 0.0.1
+do:
+  - large:
+      export:
+        as: large
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/large
+  - unexported:
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/large
+  - small:
+      export:
+        as: small
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/echo
+        body:
+          length: ${ .output.large.value | length }
+          dataLength: ${ .data.unexported.value | length }
+  - reference:
+      export:
+        as: reference
+      set:
+        offloaded: ${ .output.large | has("$zigflowRef") }`
+
+// recordingStore records the keys put in the store
+type recordingStore struct {
+	*storage.MemoryStore
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *recordingStore) Put(ctx context.Context, key string, data []byte) error {
+	r.mu.Lock()
+	r.keys = append(r.keys, key)
+	r.mu.Unlock()
+
+	return r.MemoryStore.Put(ctx, key, data)
+}
+
+func newOutputStore(t *testing.T, threshold int) *recordingStore {
+	t.Helper()
+
+	store := &recordingStore{MemoryStore: storage.NewMemoryStore()}
+	SetOutputStore(store, threshold)
+	t.Cleanup(func() {
+		SetOutputStore(nil, 0)
+	})
+
+	return store
+}
+
+func mockLargeResponse(t *testing.T, value string) {
+	t.Helper()
+
+	httpmock.Activate()
+	t.Cleanup(httpmock.DeactivateAndReset)
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/large",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"value": value}))
+	registerEchoResponder()
+}
+
+// registerEchoResponder responds with the request's body
+func registerEchoResponder() {
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/echo", func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp := httpmock.NewBytesResponse(http.StatusOK, body)
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+}
+
+func TestOffloadOutput(t *testing.T) {
+	store := newOutputStore(t, 100)
+
+	value := strings.Repeat("a", 500)
+	mockLargeResponse(t, value)
+
+	doc := loadWorkflow(t, offloadWorkflow)
+	env := newTestEnvironment(t, doc)
+
+	// The activities' results are what's written to the history
+	var results []string
+	env.SetOnActivityCompletedListener(func(_ *activity.Info, result converter.EncodedValue, _ error) {
+		var v any
+		if result != nil && result.HasValue() {
+			assert.NoError(t, result.Get(&v))
+		}
+		b, err := json.Marshal(v)
+		assert.NoError(t, err)
+		results = append(results, string(b))
+	})
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+
+	// The offloaded value is never in the history or the result
+	assert.Len(t, results, 3)
+	for _, r := range results {
+		assert.NotContains(t, r, value)
+	}
+	b, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), value)
+
+	// The workflow only sees the reference
+	assert.Equal(t, map[string]any{"offloaded": true}, result["reference"])
+
+	// The large output is replaced with a reference
+	ref, ok := utils.GetOutputReference(result["large"])
+	assert.True(t, ok)
+
+	// Every large result is offloaded by its activity, whether it's exported
+	// or not, and later activities can use them from the output and the data
+	assert.Len(t, store.keys, 2)
+	assert.Equal(t, map[string]any{
+		"length":     float64(len(value)),
+		"dataLength": float64(len(value)),
+	}, result["small"])
+
+	// The reference resolves to the original output
+	data, err := store.Get(context.Background(), ref)
+	assert.NoError(t, err)
+
+	var stored map[string]any
+	assert.NoError(t, json.Unmarshal(data, &stored))
+	assert.Equal(t, map[string]any{"value": value}, stored)
+}
+
+func TestOffloadOutputBelowThreshold(t *testing.T) {
+	store := newOutputStore(t, 1000)
+	mockLargeResponse(t, "a")
+
+	doc := loadWorkflow(t, offloadWorkflow)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+
+	assert.Equal(t, map[string]any{"value": "a"}, result["large"])
+	assert.Equal(t, map[string]any{"offloaded": false}, result["reference"])
+	assert.Empty(t, store.keys)
+}
+
+func TestResolveOffloadedOutput(t *testing.T) {
+	store := newOutputStore(t, 100)
+	assert.NoError(t, store.Put(context.Background(), "some/ref", []byte(`{"value":"hello"}`)))
+
+	httpmock.Activate()
+	t.Cleanup(httpmock.DeactivateAndReset)
+	registerEchoResponder()
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: resolve
+  version: 0.0.1
+do:
+  - step:
+      export:
+        as: result
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/echo
+        body:
+          value: ${ .output.previous.value }`)
+	env := newTestEnvironment(t, doc)
+
+	// State received from a parent workflow or previous run
+	state := utils.NewState()
+	state.Output["previous"] = utils.NewOutputReference("some/ref", 17)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, state)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+
+	assert.Equal(t, map[string]any{"value": "hello"}, result["result"])
+}
+
+func TestResolveOffloadedData(t *testing.T) {
+	store := newOutputStore(t, 100)
+	assert.NoError(t, store.Put(context.Background(), "some/ref", []byte(`{"value":"hello"}`)))
+
+	httpmock.Activate()
+	t.Cleanup(httpmock.DeactivateAndReset)
+	registerEchoResponder()
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: resolve
+  version: 0.0.1
+do:
+  - step:
+      export:
+        as: result
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/echo
+        body:
+          value: ${ .data.previous.value }`)
+	env := newTestEnvironment(t, doc)
+
+	// A task result received from a parent workflow or previous run
+	state := utils.NewState()
+	state.Data["previous"] = utils.NewOutputReference("some/ref", 17)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, state)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+
+	assert.Equal(t, map[string]any{"value": "hello"}, result["result"])
+}
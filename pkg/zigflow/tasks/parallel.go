@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// isParallel returns whether the do task runs its tasks in parallel. Tasks
+// can't go to another task as there's no order to them.
+func (t *DoTaskBuilder) isParallel() (bool, error) {
+	mode, err := metadata.GetMode(t.task.Metadata)
+	if err != nil {
+		return false, fmt.Errorf("invalid mode metadata for task %s: %w", t.GetTaskName(), err)
+	}
+	if mode != metadata.ModeParallel {
+		return false, nil
+	}
+
+	for _, task := range *t.task.Do {
+		if then := task.GetBase().Then; then != nil && !then.IsEnum() {
+			return false, fmt.Errorf("task %s cannot go to another task as it's in parallel do task %s", task.Key, t.GetTaskName())
+		}
+	}
+
+	return true, nil
+}
+
+// runTasks runs the tasks either one after another or in parallel
+func (t *DoTaskBuilder) runTasks(ctx workflow.Context, tasks []workflowFunc, input any, state *utils.State) error {
+	if t.parallel {
+		return t.runParallel(ctx, tasks, input, state)
+	}
+
+	return t.iterateTasks(ctx, tasks, input, state)
+}
+
+// runParallel runs all the tasks at the same time and waits for them to
+// finish. If a task fails, the others are cancelled.
+//
+// Each task gets its own copy of the state. Once they've all finished, the
+// data and output keys that each task added, changed or removed are applied to
+// the state in the order the tasks are declared. If more than one task changes
+// the same top-level key, the last task declared wins.
+func (t *DoTaskBuilder) runParallel(ctx workflow.Context, tasks []workflowFunc, input any, state *utils.State) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Debug("Running tasks in parallel", "name", t.GetTaskName(), "tasks", len(tasks))
+
+	ctx, cancel := workflow.WithCancel(ctx)
+	defer cancel()
+
+	before := state.Clone()
+	branches := make([]*utils.State, len(tasks))
+	errs := make([]error, len(tasks))
+
+	wg := workflow.NewWaitGroup(ctx)
+	for i, task := range tasks {
+		branches[i] = state.Clone().AddData(map[string]any{
+			"task": map[string]any{
+				"name": task.GetTaskName(),
+			},
+		})
+
+		wg.Add(1)
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			defer wg.Done()
+
+			ctx = withLogSecrets(ctx, t.secrets, branches[i])
+
+			then, _, err := t.runAndStoreTask(ctx, task, input, branches[i])
+			if err == nil && then != nil && !then.IsEnum() {
+				err = newValidationError(
+					fmt.Sprintf("Task %s cannot go to another task as it's in a parallel do task", task.Name),
+					nil,
+					task.Name,
+				)
+			}
+			if err != nil && !temporal.IsCanceledError(err) {
+				cancel()
+			}
+			errs[i] = err
+		})
+	}
+	wg.Wait(ctx)
+
+	for i, task := range tasks {
+		if err := errs[i]; err != nil && !temporal.IsCanceledError(err) {
+			return err
+		} else if err != nil {
+			logger.Debug("Task cancelled", "name", task.Name)
+			continue
+		}
+
+		state.ApplyChanges(before, branches[i])
+	}
+
+	return nil
+}
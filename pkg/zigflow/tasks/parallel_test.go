@@ -0,0 +1,295 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestParallelDo(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var calls []string
+	record := func(name string) httpmock.Responder {
+		return func(*http.Request) (*http.Response, error) {
+			calls = append(calls, name)
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"name": name})
+		}
+	}
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/slow", record("slow"))
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/fast", record("fast"))
+
+	tests := []struct {
+		Name  string
+		Mode  string
+		Calls []string
+	}{
+		{
+			Name:  "Sequential",
+			Mode:  "sequential",
+			Calls: []string{"slow", "fast"},
+		},
+		{
+			Name:  "Parallel",
+			Mode:  "parallel",
+			Calls: []string{"fast", "slow"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			calls = nil
+
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: parallel
+  version: 0.0.1
+do:
+  - fanOut:
+      metadata:
+        mode: %s
+      do:
+        - slow:
+            do:
+              - pause:
+                  wait:
+                    seconds: 5
+              - getSlow:
+                  export:
+                    as: slow
+                  call: http
+                  with:
+                    method: get
+                    endpoint: https://example.com/slow
+              - setSlow:
+                  set:
+                    winner: slow
+                    slowDone: true
+        - fast:
+            do:
+              - getFast:
+                  export:
+                    as: fast
+                  call: http
+                  with:
+                    method: get
+                    endpoint: https://example.com/fast
+              - setFast:
+                  set:
+                    winner: fast
+                    fastDone: true
+  - summary:
+      export:
+        as: summary
+      set:
+        winner: ${ .data.winner }
+        done: ${ .data.slowDone and .data.fastDone }`, test.Mode))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+			assert.Equal(t, test.Calls, calls)
+
+			// Conflicting keys are taken from the last task declared, not the
+			// last to finish
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, map[string]any{
+				"slow": map[string]any{"name": "slow"},
+				"fast": map[string]any{"name": "fast"},
+				"summary": map[string]any{
+					"winner": "fast",
+					"done":   true,
+				},
+			}, result)
+		})
+	}
+}
+
+func TestParallelDoFailure(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: parallel
+  version: 0.0.1
+do:
+  - fanOut:
+      metadata:
+        mode: parallel
+      do:
+        - slow:
+            do:
+              - pause:
+                  wait:
+                    hours: 1
+              - finished:
+                  export:
+                    as: finished
+                  set:
+                    finished: true
+        - fail:
+            raise:
+              error:
+                type: https://serverlessworkflow.io/spec/1.0.0/errors/runtime
+                status: 500
+                title: Failed
+  - after:
+      set:
+        after: true`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.ErrorContains(t, env.GetWorkflowError(), "Failed")
+}
+
+func TestParallelDoTry(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: parallel
+  version: 0.0.1
+do:
+  - fanOut:
+      metadata:
+        mode: parallel
+      do:
+        - first:
+            try:
+              - pause:
+                  wait:
+                    seconds: 5
+              - setFirst:
+                  set:
+                    first: true
+            catch:
+              do:
+                - caught:
+                    set:
+                      caught: true
+        - second:
+            try:
+              - pause:
+                  wait:
+                    seconds: 5
+              - setSecond:
+                  set:
+                    second: true
+            catch:
+              do:
+                - caught:
+                    set:
+                      caught: true
+  - after:
+      set:
+        after: true`)
+	env := newTestEnvironment(t, doc)
+
+	var started []string
+	env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, _ workflow.Context, _ converter.EncodedValues) {
+		started = append(started, info.WorkflowExecution.ID)
+	})
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	// Each try task starts its own child workflow
+	parentID := "default-test-workflow-id"
+	assert.ElementsMatch(t, []string{
+		parentID + "_first_try",
+		parentID + "_second_try",
+	}, started)
+}
+
+func TestParallelDoValidation(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata string
+		Then     string
+		Error    string
+	}{
+		{
+			Name:     "Continue allowed",
+			Metadata: "mode: parallel",
+			Then:     "continue",
+		},
+		{
+			Name:     "Go to task in sequential",
+			Metadata: "mode: sequential",
+			Then:     "second",
+		},
+		{
+			Name:     "Go to task in parallel",
+			Metadata: "mode: parallel",
+			Then:     "second",
+			Error:    "task first cannot go to another task as it's in parallel do task fanOut",
+		},
+		{
+			Name:     "Unknown mode",
+			Metadata: "mode: random",
+			Then:     "continue",
+			Error:    "invalid mode metadata for task fanOut: mode must be sequential or parallel",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: parallel
+  version: 0.0.1
+do:
+  - fanOut:
+      metadata:
+        %s
+      do:
+        - first:
+            then: %s
+            set:
+              hello: world
+        - second:
+            set:
+              hello: world`, test.Metadata, test.Then))
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			err = builder.PostLoad()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.Error)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// withTaskPriority sets the priority of the task's activities. Child workflows
+// take their priority from the same options. Temporal treats an unset priority
+// as inherited from the workflow, so a task without one shares its parent's.
+func withTaskPriority(ctx workflow.Context, task model.Task) (workflow.Context, error) {
+	priority, err := metadata.GetPriority(task.GetBase().Metadata)
+	if err != nil {
+		return nil, temporal.NewNonRetryableApplicationError("Invalid priority metadata", "Priority", err)
+	}
+	if priority == 0 {
+		return ctx, nil
+	}
+
+	return workflow.WithPriority(ctx, temporal.Priority{
+		PriorityKey: priority,
+	}), nil
+}
+
+// taskPriority returns the priority to start child workflows with
+func taskPriority(ctx workflow.Context) temporal.Priority {
+	return workflow.GetActivityOptions(ctx).Priority
+}
+
+// validateTaskPriority checks that the priority metadata is valid
+func validateTaskPriority(taskName string, task model.Task) error {
+	if _, err := metadata.GetPriority(task.GetBase().Metadata); err != nil {
+		return fmt.Errorf("invalid priority metadata for task %s: %w", taskName, err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// priorityRecorder records the priority that activities and child workflows
+// are started with, as the test environment doesn't pass it on
+type priorityRecorder struct {
+	interceptor.WorkerInterceptorBase
+
+	activities []int
+	children   []int
+}
+
+func (r *priorityRecorder) InterceptWorkflow(
+	_ workflow.Context, next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	i := &priorityRecorderInbound{recorder: r}
+	i.Next = next
+	return i
+}
+
+type priorityRecorderInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+
+	recorder *priorityRecorder
+}
+
+func (i *priorityRecorderInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	o := &priorityRecorderOutbound{recorder: i.recorder}
+	o.Next = outbound
+	return i.Next.Init(o)
+}
+
+type priorityRecorderOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+
+	recorder *priorityRecorder
+}
+
+func (o *priorityRecorderOutbound) ExecuteActivity(ctx workflow.Context, activityType string, args ...any) workflow.Future {
+	o.recorder.activities = append(o.recorder.activities, workflow.GetActivityOptions(ctx).Priority.PriorityKey)
+	return o.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func (o *priorityRecorderOutbound) ExecuteChildWorkflow(
+	ctx workflow.Context, childWorkflowType string, args ...any,
+) workflow.ChildWorkflowFuture {
+	o.recorder.children = append(o.recorder.children, workflow.GetChildWorkflowOptions(ctx).Priority.PriorityKey)
+	return o.Next.ExecuteChildWorkflow(ctx, childWorkflowType, args...)
+}
+
+func TestTaskPriority(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/priority", httpmock.NewStringResponder(http.StatusOK, "ok"))
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: priority
+  version: 0.0.1
+do:
+  - urgent:
+      metadata:
+        priority: 1
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/priority
+  - background:
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/priority
+  - group:
+      metadata:
+        priority: 2
+      do:
+        - nested:
+            call: http
+            with:
+              method: get
+              endpoint: https://example.com/priority
+  - child:
+      metadata:
+        priority: 4
+      run:
+        workflow:
+          namespace: default
+          name: child
+          version: 0.0.1`)
+	env := newTestEnvironment(t, doc)
+
+	recorder := &priorityRecorder{}
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{recorder},
+	})
+
+	env.RegisterWorkflowWithOptions(func(workflow.Context, any, *utils.State) (any, error) {
+		return nil, nil
+	}, workflow.RegisterOptions{Name: "child"})
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []int{1, 0, 2}, recorder.activities)
+	assert.Equal(t, []int{4}, recorder.children)
+}
+
+func TestTaskPriorityValidation(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Priority string
+		Error    string
+	}{
+		{
+			Name:     "Valid",
+			Priority: "3",
+		},
+		{
+			Name:     "Too high",
+			Priority: "6",
+			Error:    "priority must be between 1 and 5",
+		},
+		{
+			Name:     "Too low",
+			Priority: "0",
+			Error:    "priority must be between 1 and 5",
+		},
+		{
+			Name:     "Fraction",
+			Priority: "1.5",
+			Error:    "priority must be a whole number",
+		},
+		{
+			Name:     "String",
+			Priority: "high",
+			Error:    "priority must be a number",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: priority
+  version: 0.0.1
+do:
+  - task:
+      metadata:
+        priority: `+test.Priority+`
+      set:
+        hello: world`)
+
+			builder, err := NewDoTaskBuilder(nil, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc, DoTaskOpts{
+				DisableRegisterWorkflow: true,
+			})
+			assert.NoError(t, err)
+
+			_, err = builder.Build()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, "invalid priority metadata for task task")
+			assert.ErrorContains(t, err, test.Error)
+		})
+	}
+}
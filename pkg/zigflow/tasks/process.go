@@ -0,0 +1,189 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+)
+
+// How long to wait for a process to exit once it's been interrupted, before
+// it's killed
+const processWaitDelay = 5 * time.Second
+
+// ProcessResult is the output of a run task's script or container. The stdout
+// is parsed according to the task's output format.
+type ProcessResult struct {
+	Code   int    `json:"code"`
+	Stdout any    `json:"stdout"`
+	Stderr string `json:"stderr"`
+}
+
+// processOutput captures a process's output, logging each line as it's
+// written and heartbeating so long-running processes can be seen to be alive.
+// Any secret values are masked in the logs.
+type processOutput struct {
+	ctx     context.Context
+	stream  string
+	secrets *utils.Secrets
+	buf     bytes.Buffer
+	line    []byte
+}
+
+func (p *processOutput) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+	p.line = append(p.line, b...)
+
+	for {
+		i := bytes.IndexByte(p.line, '\n')
+		if i < 0 {
+			break
+		}
+		activity.GetLogger(p.ctx).Debug("Process output", "stream", p.stream, "line", p.secrets.Mask(string(p.line[:i])))
+		p.line = p.line[i+1:]
+	}
+
+	activity.RecordHeartbeat(p.ctx)
+
+	return len(b), nil
+}
+
+// runProcess runs the command and returns its output, which is offloaded if
+// it's too large. A non-zero exit code isn't retried, as running the same thing
+// again is unlikely to help, and the result is given in the error's details.
+func runProcess(ctx context.Context, cmd *exec.Cmd, errType, outputFormat string, secrets *utils.Secrets) (any, error) {
+	logger := activity.GetLogger(ctx)
+
+	stdout := &processOutput{ctx: ctx, stream: "stdout", secrets: secrets}
+	stderr := &processOutput{ctx: ctx, stream: "stderr", secrets: secrets}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	cmd.WaitDelay = processWaitDelay
+
+	runErr := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if runErr != nil && (!errors.As(runErr, &exitErr) || ctx.Err() != nil) {
+		logger.Error("Error running process", "error", runErr)
+		return nil, fmt.Errorf("error running process: %w", runErr)
+	}
+
+	result := &ProcessResult{
+		Code:   cmd.ProcessState.ExitCode(),
+		Stdout: strings.TrimSpace(stdout.buf.String()),
+		Stderr: stderr.buf.String(),
+	}
+
+	if exitErr != nil {
+		logger.Error("Process exited unsuccessfully", "code", result.Code)
+
+		return nil, temporal.NewApplicationErrorWithOptions(
+			fmt.Sprintf("%s exited with code %d", errType, result.Code),
+			errType,
+			temporal.ApplicationErrorOptions{
+				NonRetryable: true,
+				Details:      []any{result},
+			},
+		)
+	}
+
+	output, err := parseProcessOutput(stdout.buf.Bytes(), outputFormat)
+	if err != nil {
+		logger.Error("Error parsing process output", "error", err)
+		return nil, newValidationError("Invalid process output", err, "", result)
+	}
+	result.Stdout = output
+
+	return offloadResult(ctx, result)
+}
+
+// interruptOnCancel gives the process the chance to stop cleanly if the
+// activity's cancelled
+func interruptOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+}
+
+// parseProcessOutput parses the stdout. In auto format, it's parsed if it's
+// JSON, otherwise it's returned as a string.
+func parseProcessOutput(stdout []byte, format string) (any, error) {
+	if format == metadata.OutputFormatText {
+		return strings.TrimSpace(string(stdout)), nil
+	}
+
+	var output any
+	if err := json.Unmarshal(stdout, &output); err != nil {
+		if format == metadata.OutputFormatJSON {
+			return nil, fmt.Errorf("stdout is not valid json: %w", err)
+		}
+		return strings.TrimSpace(string(stdout)), nil
+	}
+
+	return output, nil
+}
+
+// processEnvironment interpolates the envvars given to a process. Any value
+// that isn't a string is JSON encoded. Later sets of envvars take precedence.
+func processEnvironment(state *utils.State, vars ...map[string]any) (map[string]string, error) {
+	env := map[string]string{}
+
+	for _, v := range vars {
+		obj, err := utils.TraverseAndEvaluateObj(model.NewObjectOrRuntimeExpr(swUtil.DeepClone(v)), state)
+		if err != nil {
+			return nil, fmt.Errorf("error traversing process environment: %w", err)
+		}
+
+		for name, value := range obj {
+			str, ok := value.(string)
+			if !ok {
+				b, err := json.Marshal(value)
+				if err != nil {
+					return nil, fmt.Errorf("error encoding envvar %s: %w", name, err)
+				}
+				str = string(b)
+			}
+			env[name] = str
+		}
+	}
+
+	return env, nil
+}
+
+// stringMapToAny converts the model's envvars so they can be interpolated
+func stringMapToAny(m map[string]string) map[string]any {
+	res := make(map[string]any, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
@@ -0,0 +1,28 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import "github.com/serverlessworkflow/sdk-go/v3/model"
+
+// BuildRecorder may be implemented by the worker passed to the builders to be
+// told about each task as it's built. Any workflows registered between
+// BeginTask and EndTask are child workflows created by that task. This allows
+// the generated workflows to be inspected without connecting to Temporal.
+type BuildRecorder interface {
+	BeginTask(workflowName, taskName string, task model.Task)
+	EndTask()
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func init() {
+	activities = append(activities, runContainerActivity)
+}
+
+// Containers run arbitrary images on the worker's host, so are disabled
+// unless the worker explicitly allows them
+var allowContainers bool
+
+// The CLI used to run containers, such as docker or podman
+var containerRuntime = "docker"
+
+// SetAllowContainers sets whether run tasks can run containers
+func SetAllowContainers(allow bool) {
+	allowContainers = allow
+}
+
+// SetContainerRuntime sets the CLI used to run containers. It must accept
+// Docker's run arguments.
+func SetContainerRuntime(runtime string) {
+	containerRuntime = runtime
+}
+
+// validateContainer checks the container can be run by this worker
+func (t *RunTaskBuilder) validateContainer() error {
+	container := t.task.Run.Container
+
+	if !allowContainers {
+		return fmt.Errorf("run task %s runs a container, but containers are not allowed on this worker", t.GetTaskName())
+	}
+	if len(container.Ports) > 0 || len(container.Volumes) > 0 {
+		return fmt.Errorf("container for task %s must not set ports or volumes - they are not supported", t.GetTaskName())
+	}
+	if t.task.Run.Await != nil && !*t.task.Run.Await {
+		return fmt.Errorf("container for task %s must be awaited", t.GetTaskName())
+	}
+	if _, err := metadata.GetResources(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid resources metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	return nil
+}
+
+func (t *RunTaskBuilder) runContainer(ctx workflow.Context, input any, state *utils.State) (any, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Debug("Running a container", "task", t.GetTaskName(), "image", t.task.Run.Container.Image)
+
+	var res any
+	if err := executeActivity(ctx, nil, runContainerActivity, t.task, input, state, t.secrets).Get(ctx, &res); err != nil {
+		if temporal.IsCanceledError(err) {
+			return nil, nil
+		}
+
+		logger.Error("Error running container", "task", t.GetTaskName(), "error", err)
+		return nil, fmt.Errorf("error running container: %w", err)
+	}
+
+	return res, nil
+}
+
+func runContainerActivity(ctx context.Context, task *model.RunTask, input any, state *utils.State, secrets []string) (any, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Debug("Running container activity")
+
+	state = state.AddActivityInfo(ctx)
+
+	if err := resolveStateOutputs(ctx, state); err != nil {
+		logger.Error("Error resolving offloaded outputs", "error", err)
+		return nil, err
+	}
+
+	outputFormat, err := metadata.GetOutputFormat(task.Metadata)
+	if err != nil {
+		return nil, newValidationError("Invalid output format", err, "")
+	}
+
+	resources, err := metadata.GetResources(task.Metadata)
+	if err != nil {
+		return nil, newValidationError("Invalid resources", err, "")
+	}
+
+	env, err := processEnvironment(state, stringMapToAny(task.Run.Container.Environment))
+	if err != nil {
+		logger.Error("Error creating container environment", "error", err)
+		return nil, err
+	}
+
+	name := containerName(ctx)
+	cmd := exec.CommandContext(ctx, containerRuntime, containerArgs(name, task.Run.Container, env, resources)...)
+
+	// The runtime needs the worker's envvars to find its socket. The container
+	// only gets the envvars it's given, which are read from the runtime's
+	// environment so their values aren't visible in the process list.
+	cmd.Env = os.Environ()
+	for envName, value := range env {
+		cmd.Env = append(cmd.Env, envName+"="+value)
+	}
+
+	// Interrupting the runtime's CLI doesn't always stop the container, so it's
+	// removed by its name
+	cmd.Cancel = func() error {
+		rm := exec.Command(containerRuntime, "rm", "--force", name)
+		rm.Env = os.Environ()
+		if out, err := rm.CombinedOutput(); err != nil {
+			logger.Error("Error removing container", "name", name, "error", err, "output", string(out))
+		}
+		return cmd.Process.Signal(os.Interrupt)
+	}
+
+	return runProcess(ctx, cmd, "Container", outputFormat, stateSecrets(secrets, state))
+}
+
+// containerName returns the container's name, which is unique to the
+// activity's attempt so it can be removed if the activity's cancelled
+func containerName(ctx context.Context) string {
+	info := activity.GetInfo(ctx)
+	id := fmt.Sprintf("%s/%s/%s/%d", info.WorkflowExecution.ID, info.WorkflowExecution.RunID, info.ActivityID, info.Attempt)
+
+	// Workflow IDs can have characters that aren't allowed in container names
+	sum := sha256.Sum256([]byte(id))
+	return "zigflow-" + hex.EncodeToString(sum[:8])
+}
+
+// containerArgs returns the runtime's arguments to run the container. The
+// command replaces the image's entrypoint and is run by the image's shell.
+func containerArgs(name string, container *model.Container, env map[string]string, resources *metadata.Resources) []string {
+	args := []string{"run", "--rm", "--name", name}
+	if container.Command != "" {
+		args = append(args, "--entrypoint", "sh")
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		args = append(args, "--env", name)
+	}
+
+	if resources != nil {
+		if resources.CPUs != "" {
+			args = append(args, "--cpus", resources.CPUs)
+		}
+		if resources.Memory != "" {
+			args = append(args, "--memory", resources.Memory)
+		}
+	}
+
+	args = append(args, container.Image)
+	if container.Command != "" {
+		args = append(args, "-c", container.Command)
+	}
+
+	return args
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+// fakeContainerRuntime replaces the container runtime with a script, so the
+// tests don't need Docker
+func fakeContainerRuntime(t *testing.T, script string) {
+	runtime := filepath.Join(t.TempDir(), "runtime")
+	assert.NoError(t, os.WriteFile(runtime, []byte("#!/bin/sh\n"+script), 0o700))
+
+	SetAllowContainers(true)
+	SetContainerRuntime(runtime)
+	t.Cleanup(func() {
+		SetAllowContainers(false)
+		SetContainerRuntime("docker")
+	})
+}
+
+func TestRunContainer(t *testing.T) {
+	fakeContainerRuntime(t, `printf '{"args": "%s", "name": "%s"}' "$*" "$NAME"`)
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: container
+  version: 0.0.1
+do:
+  - greet:
+      export:
+        as: result
+      metadata:
+        resources:
+          cpus: "0.5"
+          memory: 256m
+      run:
+        container:
+          image: alpine:3
+          command: echo hello
+          environment:
+            NAME: ${ .input.name }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow("container", map[string]any{"name": "Ziggy"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"code": float64(0),
+		"stdout": map[string]any{
+			"args": "run --rm --name zigflow-78a2920f3925d5c9 --entrypoint sh --env NAME --cpus 0.5 --memory 256m alpine:3 -c echo hello",
+			"name": "Ziggy",
+		},
+		"stderr": "",
+	}, result["result"])
+}
+
+func TestRunContainerExitCode(t *testing.T) {
+	fakeContainerRuntime(t, `echo "pulling image" >&2; exit 2`)
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: container
+  version: 0.0.1
+do:
+  - fail:
+      run:
+        container:
+          image: alpine:3`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow("container", nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+
+	err := env.GetWorkflowError()
+	assert.ErrorContains(t, err, "Container exited with code 2")
+
+	// The activity's error is wrapped by the task's error
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.True(t, errors.As(appErr.Unwrap(), &appErr))
+	assert.Equal(t, "Container", appErr.Type())
+
+	var details ProcessResult
+	assert.NoError(t, appErr.Details(&details))
+	assert.Equal(t, ProcessResult{
+		Code:   2,
+		Stdout: "",
+		Stderr: "pulling image\n",
+	}, details)
+}
+
+func TestRunContainerCancel(t *testing.T) {
+	dir := t.TempDir()
+	fakeContainerRuntime(t, `if [ "$1" = "rm" ]; then echo "$3" > `+dir+`/removed; exit 0; fi
+echo "$4" > `+dir+`/started
+exec sleep 10`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.SetWorkerOptions(worker.Options{BackgroundActivityContext: ctx})
+	env.RegisterActivity(runContainerActivity)
+
+	time.AfterFunc(time.Second, cancel)
+
+	task := &model.RunTask{Run: model.RunTaskConfiguration{
+		Container: &model.Container{Image: "alpine:3"},
+	}}
+	_, err := env.ExecuteActivity(runContainerActivity, task, nil, utils.NewState(), nil)
+	assert.Error(t, err)
+
+	// The container that was started is removed by its name
+	started, err := os.ReadFile(filepath.Join(dir, "started"))
+	assert.NoError(t, err)
+	removed, err := os.ReadFile(filepath.Join(dir, "removed"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(started), string(removed))
+	assert.True(t, strings.HasPrefix(string(removed), "zigflow-"))
+}
+
+func TestRunContainerValidation(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Allow     bool
+		Container string
+		Error     string
+	}{
+		{
+			Name: "Containers not allowed",
+			Container: `
+          image: alpine:3`,
+			Error: "run task greet runs a container, but containers are not allowed on this worker",
+		},
+		{
+			Name:  "Volumes",
+			Allow: true,
+			Container: `
+          image: alpine:3
+          volumes:
+            /etc: /host-etc`,
+			Error: "container for task greet must not set ports or volumes - they are not supported",
+		},
+		{
+			Name:  "Not awaited",
+			Allow: true,
+			Container: `
+          image: alpine:3
+        await: false`,
+			Error: "container for task greet must be awaited",
+		},
+		{
+			Name:  "Invalid resources",
+			Allow: true,
+			Container: `
+          image: alpine:3
+      metadata:
+        resources:
+          memory: lots`,
+			Error: "invalid resources metadata for task greet: resources memory must be a number with an optional unit of b, k, m or g",
+		},
+		{
+			Name:  "Invalid output format",
+			Allow: true,
+			Container: `
+          image: alpine:3
+      metadata:
+        outputFormat: yaml`,
+			Error: "invalid output format metadata for task greet: output format must be auto, json or text",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			SetAllowContainers(test.Allow)
+			t.Cleanup(func() {
+				SetAllowContainers(false)
+			})
+
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: container
+  version: 0.0.1
+do:
+  - greet:
+      run:
+        container:`+test.Container)
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			assert.ErrorContains(t, builder.PostLoad(), test.Error)
+		})
+	}
+}
+
+func TestParseProcessOutput(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Stdout   string
+		Format   string
+		Expected any
+		Error    string
+	}{
+		{
+			Name:     "Auto JSON",
+			Stdout:   `{"hello": "world"}`,
+			Format:   metadata.OutputFormatAuto,
+			Expected: map[string]any{"hello": "world"},
+		},
+		{
+			Name:     "Auto text",
+			Stdout:   "hello world\n",
+			Format:   metadata.OutputFormatAuto,
+			Expected: "hello world",
+		},
+		{
+			Name:     "Text ignores JSON",
+			Stdout:   `{"hello": "world"}`,
+			Format:   metadata.OutputFormatText,
+			Expected: `{"hello": "world"}`,
+		},
+		{
+			Name:   "JSON must be valid",
+			Stdout: "hello world",
+			Format: metadata.OutputFormatJSON,
+			Error:  "stdout is not valid json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			output, err := parseProcessOutput([]byte(test.Stdout), test.Format)
+			if test.Error != "" {
+				assert.ErrorContains(t, err, test.Error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, output)
+		})
+	}
+}
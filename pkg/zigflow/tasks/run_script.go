@@ -0,0 +1,144 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func init() {
+	activities = append(activities, runScriptActivity)
+}
+
+// Scripts run arbitrary code on the worker, so are disabled unless the worker
+// explicitly allows them
+var allowScripts bool
+
+// SetAllowScripts sets whether run tasks can run scripts
+func SetAllowScripts(allow bool) {
+	allowScripts = allow
+}
+
+// scriptInterpreters are the commands that run the code for each language.
+// The code is passed as an argument, so it's never written to disk.
+var scriptInterpreters = map[string][]string{
+	"bash":   {"bash", "-c"},
+	"python": {"python3", "-c"},
+}
+
+// validateScript checks the script can be run by this worker
+func (t *RunTaskBuilder) validateScript() error {
+	script := t.task.Run.Script
+
+	if !allowScripts {
+		return fmt.Errorf("run task %s runs a script, but scripts are not allowed on this worker", t.GetTaskName())
+	}
+	if _, ok := scriptInterpreters[script.Language]; !ok {
+		return fmt.Errorf("unsupported script language for task %s: %s", t.GetTaskName(), script.Language)
+	}
+	if script.External != nil || script.InlineCode == nil || *script.InlineCode == "" {
+		return fmt.Errorf("script for task %s must set its code - external sources are not supported", t.GetTaskName())
+	}
+	if t.task.Run.Await != nil && !*t.task.Run.Await {
+		return fmt.Errorf("script for task %s must be awaited", t.GetTaskName())
+	}
+
+	return nil
+}
+
+func (t *RunTaskBuilder) runScript(ctx workflow.Context, input any, state *utils.State) (any, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Debug("Running a script", "task", t.GetTaskName(), "language", t.task.Run.Script.Language)
+
+	var res any
+	if err := executeActivity(ctx, nil, runScriptActivity, t.task, input, state, t.secrets).Get(ctx, &res); err != nil {
+		if temporal.IsCanceledError(err) {
+			return nil, nil
+		}
+
+		logger.Error("Error running script", "task", t.GetTaskName(), "error", err)
+		return nil, fmt.Errorf("error running script: %w", err)
+	}
+
+	return res, nil
+}
+
+func runScriptActivity(ctx context.Context, task *model.RunTask, input any, state *utils.State, secrets []string) (any, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Debug("Running script activity")
+
+	state = state.AddActivityInfo(ctx)
+
+	if err := resolveStateOutputs(ctx, state); err != nil {
+		logger.Error("Error resolving offloaded outputs", "error", err)
+		return nil, err
+	}
+
+	script := task.Run.Script
+	interpreter, ok := scriptInterpreters[script.Language]
+	if !ok || script.InlineCode == nil {
+		return nil, newValidationError("Unsupported script", nil, "")
+	}
+
+	outputFormat, err := metadata.GetOutputFormat(task.Metadata)
+	if err != nil {
+		return nil, newValidationError("Invalid output format", err, "")
+	}
+
+	// The arguments and environment are the only envvars the script receives
+	// other than the PATH, so the worker's envvars aren't leaked
+	env, err := processEnvironment(state, stringMapToAny(script.Environment), script.Arguments)
+	if err != nil {
+		logger.Error("Error creating script environment", "error", err)
+		return nil, err
+	}
+
+	// Run in an empty working directory, which is removed afterwards
+	dir, err := os.MkdirTemp("", "zigflow-script-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating script directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Error("Error removing script directory", "error", err)
+		}
+	}()
+
+	args := make([]string, 0, len(interpreter))
+	args = append(args, interpreter[1:]...)
+	args = append(args, *script.InlineCode)
+
+	cmd := exec.CommandContext(ctx, interpreter[0], args...)
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir}
+	for name, value := range env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+	interruptOnCancel(cmd)
+
+	return runProcess(ctx, cmd, "Script", outputFormat, stateSecrets(secrets, state))
+}
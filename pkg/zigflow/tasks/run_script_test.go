@@ -0,0 +1,218 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+)
+
+func allowScriptsForTest(t *testing.T) {
+	SetAllowScripts(true)
+	t.Cleanup(func() {
+		SetAllowScripts(false)
+	})
+}
+
+func TestRunScript(t *testing.T) {
+	allowScriptsForTest(t)
+	t.Setenv("ZIGFLOW_SCRIPT_SECRET", "secret")
+
+	tests := []struct {
+		Name     string
+		Script   string
+		Expected map[string]any
+	}{
+		{
+			Name: "Bash with JSON output",
+			Script: `
+          language: bash
+          code: 'echo "{\"greeting\": \"hello $NAME\", \"count\": $COUNT}"'
+          arguments:
+            NAME: ${ .input.name }
+            COUNT: 2`,
+			Expected: map[string]any{
+				"code":   float64(0),
+				"stdout": map[string]any{"greeting": "hello Ziggy", "count": float64(2)},
+				"stderr": "",
+			},
+		},
+		{
+			Name: "Python with text output",
+			Script: `
+          language: python
+          code: |
+            import os, sys
+            print("hello " + os.environ["NAME"])
+            print("warning", file=sys.stderr)
+          environment:
+            NAME: ${ .input.name }`,
+			Expected: map[string]any{
+				"code":   float64(0),
+				"stdout": "hello Ziggy",
+				"stderr": "warning\n",
+			},
+		},
+		{
+			Name: "Worker envvars are not passed",
+			Script: `
+          language: bash
+          code: echo "${ZIGFLOW_SCRIPT_SECRET:-unset}"`,
+			Expected: map[string]any{
+				"code":   float64(0),
+				"stdout": "unset",
+				"stderr": "",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: script
+  version: 0.0.1
+do:
+  - greet:
+      export:
+        as: result
+      run:
+        script:`+test.Script)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow("script", map[string]any{"name": "Ziggy"}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result["result"])
+		})
+	}
+}
+
+func TestRunScriptExitCode(t *testing.T) {
+	allowScriptsForTest(t)
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: script
+  version: 0.0.1
+do:
+  - fail:
+      run:
+        script:
+          language: bash
+          code: echo "some output"; echo "some error" >&2; exit 3`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow("script", nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+
+	err := env.GetWorkflowError()
+	assert.ErrorContains(t, err, "Script exited with code 3")
+
+	// The activity's error is wrapped by the task's error
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.True(t, errors.As(appErr.Unwrap(), &appErr))
+	assert.True(t, appErr.NonRetryable())
+	assert.Equal(t, "Script", appErr.Type())
+
+	var details ProcessResult
+	assert.NoError(t, appErr.Details(&details))
+	assert.Equal(t, ProcessResult{
+		Code:   3,
+		Stdout: "some output",
+		Stderr: "some error\n",
+	}, details)
+}
+
+func TestRunScriptValidation(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Allow  bool
+		Script string
+		Error  string
+	}{
+		{
+			Name: "Scripts not allowed",
+			Script: `
+          language: bash
+          code: echo hello`,
+			Error: "run task greet runs a script, but scripts are not allowed on this worker",
+		},
+		{
+			Name:  "Unsupported language",
+			Allow: true,
+			Script: `
+          language: javascript
+          code: console.log("hello")`,
+			Error: "unsupported script language for task greet: javascript",
+		},
+		{
+			Name:  "External source",
+			Allow: true,
+			Script: `
+          language: bash
+          source:
+            endpoint: https://example.com/script.sh`,
+			Error: "script for task greet must set its code - external sources are not supported",
+		},
+		{
+			Name:  "Not awaited",
+			Allow: true,
+			Script: `
+          language: bash
+          code: echo hello
+        await: false`,
+			Error: "script for task greet must be awaited",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			SetAllowScripts(test.Allow)
+			t.Cleanup(func() {
+				SetAllowScripts(false)
+			})
+
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: script
+  version: 0.0.1
+do:
+  - greet:
+      run:
+        script:`+test.Script)
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			assert.ErrorContains(t, builder.PostLoad(), test.Error)
+		})
+	}
+}
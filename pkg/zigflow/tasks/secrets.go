@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	swUtils "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/workflow"
+)
+
+// stateSecrets returns the values in the state of the secret envvars and data
+// keys. Only string values can be masked.
+func stateSecrets(names []string, state *utils.State) *utils.Secrets {
+	secrets := &utils.Secrets{}
+
+	for _, name := range names {
+		for _, values := range []map[string]any{state.Env, state.Data} {
+			if v, ok := values[name].(string); ok {
+				secrets.Add(v)
+			}
+		}
+	}
+
+	return secrets
+}
+
+// maskSecretData returns a copy of the data with the secret keys masked,
+// whatever their type
+func maskSecretData(names []string, data map[string]any) map[string]any {
+	masked := swUtils.DeepClone(data)
+	for _, name := range names {
+		if _, ok := masked[name]; ok {
+			masked[name] = utils.SecretMask
+		}
+	}
+
+	return masked
+}
+
+// logSecretsContextKey is the context key of the state whose secrets are
+// masked in the workflow logs
+type logSecretsContextKey struct{}
+
+type logSecrets struct {
+	names []string
+	state *utils.State
+}
+
+// withLogSecrets masks the state's secret envvars and data keys in anything
+// logged with the context. The values are read when each line is logged, so a
+// secret set by a task is masked from then on.
+func withLogSecrets(ctx workflow.Context, names []string, state *utils.State) workflow.Context {
+	if len(names) == 0 {
+		return ctx
+	}
+
+	return workflow.WithValue(ctx, logSecretsContextKey{}, logSecrets{names: names, state: state})
+}
+
+// SecretsInterceptor masks secrets in the workflow logs. The secret data keys
+// are only known once the workflow runs, so they can't be added to the
+// process's log secrets.
+type SecretsInterceptor struct {
+	interceptor.WorkerInterceptorBase
+}
+
+func NewSecretsInterceptor() *SecretsInterceptor {
+	return &SecretsInterceptor{}
+}
+
+func (s *SecretsInterceptor) InterceptWorkflow(
+	_ workflow.Context, next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	return &secretsWorkflowInbound{
+		WorkflowInboundInterceptorBase: interceptor.WorkflowInboundInterceptorBase{Next: next},
+	}
+}
+
+type secretsWorkflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (i *secretsWorkflowInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	return i.Next.Init(&secretsWorkflowOutbound{
+		WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound},
+	})
+}
+
+type secretsWorkflowOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+}
+
+func (o *secretsWorkflowOutbound) GetLogger(ctx workflow.Context) log.Logger {
+	logger := o.Next.GetLogger(ctx)
+
+	secrets, ok := ctx.Value(logSecretsContextKey{}).(logSecrets)
+	if !ok {
+		return logger
+	}
+
+	return &maskedLogger{logger: logger, secrets: secrets}
+}
+
+// maskedLogger masks the secrets in the message and values of each line
+type maskedLogger struct {
+	logger  log.Logger
+	secrets logSecrets
+}
+
+func (l *maskedLogger) Debug(msg string, keyvals ...any) {
+	msg, keyvals = l.mask(msg, keyvals)
+	l.logger.Debug(msg, keyvals...)
+}
+
+func (l *maskedLogger) Info(msg string, keyvals ...any) {
+	msg, keyvals = l.mask(msg, keyvals)
+	l.logger.Info(msg, keyvals...)
+}
+
+func (l *maskedLogger) Warn(msg string, keyvals ...any) {
+	msg, keyvals = l.mask(msg, keyvals)
+	l.logger.Warn(msg, keyvals...)
+}
+
+func (l *maskedLogger) Error(msg string, keyvals ...any) {
+	msg, keyvals = l.mask(msg, keyvals)
+	l.logger.Error(msg, keyvals...)
+}
+
+func (l *maskedLogger) mask(msg string, keyvals []any) (string, []any) {
+	secrets := stateSecrets(l.secrets.names, l.secrets.state)
+
+	masked := make([]any, len(keyvals))
+	for i, v := range keyvals {
+		masked[i] = secrets.MaskValue(v)
+	}
+
+	return secrets.Mask(msg), masked
+}
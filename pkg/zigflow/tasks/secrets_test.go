@@ -0,0 +1,185 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestCallHTTPSecrets(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/login", func(req *http.Request) (*http.Response, error) {
+		// The server must still receive the real values
+		assert.Equal(t, "Bearer s3cret", req.Header.Get("authorization"))
+		assert.Equal(t, "s3cret", req.URL.Query().Get("key"))
+		assert.Equal(t, "hunter2", req.Header.Get("x-password"))
+
+		return httpmock.NewStringResponse(http.StatusOK, `{"ok":true}`), nil
+	})
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - setPassword:
+      set:
+        password: hunter2
+  - login:
+      export:
+        as: login
+      call: http
+      with:
+        method: post
+        endpoint: ${ "https://example.com/login?key=" + .env.API_KEY }
+        headers:
+          authorization: ${ "Bearer " + .env.API_KEY }
+          x-password: ${ .data.password }
+        output: response`)
+
+	var buf bytes.Buffer
+	s := &testsuite.WorkflowTestSuite{}
+	s.SetLogger(log.NewStructuredLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	env := newTestEnvironmentForSuite(t, s, doc, DoTaskOpts{
+		Envvars: map[string]any{"API_KEY": "s3cret"},
+		Secrets: []string{"API_KEY", "password"},
+	})
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+
+	login := result["login"].(map[string]any)
+	request := login["request"].(map[string]any)
+	assert.Equal(t, "https://example.com/login?key=***", request["uri"])
+	assert.Equal(t, "Bearer ***", request["headers"].(map[string]any)["authorization"])
+	assert.Equal(t, "***", request["headers"].(map[string]any)["x-password"])
+
+	assert.Contains(t, buf.String(), "Making HTTP call")
+	assert.NotContains(t, buf.String(), "s3cret")
+	assert.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestCallHTTPErrorSecrets(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/login", func(req *http.Request) (*http.Response, error) {
+		return httpmock.NewJsonResponse(http.StatusUnauthorized, map[string]any{
+			"error": "invalid token hunter2",
+		})
+	})
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - setPassword:
+      set:
+        password: hunter2
+  - login:
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/login
+        body:
+          password: ${ .data.password }`)
+
+	var buf bytes.Buffer
+	s := &testsuite.WorkflowTestSuite{}
+	s.SetLogger(log.NewStructuredLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	env := newTestEnvironmentForSuite(t, s, doc, DoTaskOpts{
+		Secrets: []string{"password"},
+	})
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+
+	// The activity's error is wrapped by the task's error
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.True(t, errors.As(appErr.Unwrap(), &appErr))
+	assert.Equal(t, "CallHTTP error", appErr.Type())
+
+	var details map[string]any
+	assert.NoError(t, appErr.Details(&details))
+	assert.Equal(t, map[string]any{"error": "invalid token ***"}, details)
+
+	assert.Contains(t, buf.String(), "CallHTTP returned 4xx status code")
+	assert.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestWorkflowLogSecrets(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: logs
+  version: 0.0.1
+do:
+  - login:
+      set:
+        token: t0ken-value
+  - tag:
+      metadata:
+        searchAttributes:
+          count:
+            type: int
+            value: ${ .data.token }
+      set:
+        tagged: true`)
+
+	var buf bytes.Buffer
+	s := &testsuite.WorkflowTestSuite{}
+	s.SetLogger(log.NewStructuredLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	env := newTestEnvironmentForSuite(t, s, doc, DoTaskOpts{
+		Secrets: []string{"token"},
+	})
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.Error(t, env.GetWorkflowError())
+
+	// The attributes and the error are logged by the workflow
+	assert.Contains(t, buf.String(), "Error parsing search attributes")
+	assert.Contains(t, buf.String(), utils.SecretMask)
+	assert.NotContains(t, buf.String(), "t0ken-value")
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"go.temporal.io/sdk/workflow"
+)
+
+// snapshotArgs replaces any state in the arguments with a clone, so the task
+// that's dispatched can't share the workflow's live state.
+//
+// Activities and child workflows serialise their arguments as they're
+// dispatched, but local activities are given them as-is and run alongside the
+// workflow. Without a snapshot, a local activity's changes to the state would
+// appear in the workflow, but not when it's replayed from history.
+func snapshotArgs(args []any) []any {
+	snapshot := make([]any, len(args))
+	for i, arg := range args {
+		if state, ok := arg.(*utils.State); ok && state != nil {
+			arg = state.Clone()
+		}
+		snapshot[i] = arg
+	}
+
+	return snapshot
+}
+
+// executeChildWorkflow starts the child workflow with a snapshot of the state,
+// so later changes to the parent's state can't reach the child. Every child
+// workflow is a TemporalWorkflowFunc, so it always receives its input and the
+// state. The input can be any JSON value and is also the state's input.
+func executeChildWorkflow(ctx workflow.Context, childWorkflow string, input any, state *utils.State) workflow.ChildWorkflowFuture {
+	return workflow.ExecuteChildWorkflow(ctx, childWorkflow, snapshotArgs([]any{input, state})...)
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestSnapshotArgs(t *testing.T) {
+	state := utils.NewState().AddData(map[string]any{"hello": "world"})
+	var nilState *utils.State
+
+	args := snapshotArgs([]any{"input", state, nilState})
+
+	assert.Equal(t, "input", args[0])
+	assert.Nil(t, args[2])
+
+	snapshot, ok := args[1].(*utils.State)
+	assert.True(t, ok)
+	assert.NotSame(t, state, snapshot)
+	assert.Equal(t, state.Data, snapshot.Data)
+
+	// Changes to either state after dispatch aren't shared
+	state.AddData(map[string]any{"hello": "parent"})
+	snapshot.AddData(map[string]any{"child": true})
+
+	assert.Equal(t, map[string]any{"hello": "world", "child": true}, snapshot.Data)
+	assert.Equal(t, map[string]any{"hello": "parent"}, state.Data)
+}
+
+func TestLocalActivityStateSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write([]byte(`{"hello":"world"}`))
+	}))
+	defer server.Close()
+
+	doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        localActivity: true
+      call: http
+      with:
+        method: get
+        endpoint: %s
+  - check:
+      export:
+        as: result
+      set:
+        leaked: ${ .data.activity != null }`, server.URL))
+	env := newTestEnvironment(t, doc)
+
+	// The test environment can't pass nil arguments to local activities
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	// The activity info is added to the activity's state, which must not be
+	// the workflow's as it isn't added when the workflow is replayed
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{"leaked": false}, result["result"])
+}
+
+func TestChildWorkflowStateSnapshot(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: parent
+  version: 0.0.1
+do:
+  - setup:
+      set:
+        status: dispatched
+  - child:
+      run:
+        await: false
+        workflow:
+          namespace: default
+          name: child
+          version: 0.0.1
+  - update:
+      set:
+        status: changed`)
+	env := newTestEnvironment(t, doc)
+
+	var childData map[string]any
+	env.RegisterWorkflowWithOptions(func(ctx workflow.Context, input any, state *utils.State) (any, error) {
+		// Give the parent the chance to change its state
+		if err := workflow.Sleep(ctx, 0); err != nil {
+			return nil, err
+		}
+		childData = state.Data
+		return nil, nil
+	}, workflow.RegisterOptions{Name: "child"})
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, "dispatched", childData["status"])
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	swUtils "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
+	"go.temporal.io/sdk/workflow"
+)
+
+// StateQuery is the built-in query that returns a snapshot of the workflow's
+// data and output, which is useful when debugging a stuck workflow. Query
+// types starting "__" are reserved by Temporal.
+const StateQuery = "zigflow_state"
+
+// The state may contain sensitive data, so the query can be disabled
+var stateQueryEnabled = true
+
+// SetStateQuery enables or disables the built-in state query
+func SetStateQuery(enabled bool) {
+	stateQueryEnabled = enabled
+}
+
+// registerStateQuery exposes the state to the state query. The envvars and
+// input are excluded as they may contain secrets, and the secret data keys are
+// masked. Query handlers can't change the workflow, so this is safe to replay.
+func registerStateQuery(ctx workflow.Context, state *utils.State, secrets []string) error {
+	if !stateQueryEnabled {
+		return nil
+	}
+
+	return workflow.SetQueryHandlerWithOptions(ctx, StateQuery, func() (map[string]any, error) {
+		return map[string]any{
+			"data":   maskSecretData(secrets, state.Data),
+			"output": swUtils.DeepClone(state.Output),
+		}, nil
+	}, workflow.QueryHandlerOptions{
+		Description: "Snapshot of the workflow's data and output",
+	})
+}
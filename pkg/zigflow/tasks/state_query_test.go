@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateQuery(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Enabled  bool
+		Expected map[string]any
+	}{
+		{
+			Name:    "Enabled",
+			Enabled: true,
+			Expected: map[string]any{
+				"data": map[string]any{
+					"password": "***",
+					"progress": "started",
+					"task": map[string]any{
+						"name": "pause",
+					},
+				},
+				"output": map[string]any{
+					"first": map[string]any{
+						"progress": "started",
+					},
+				},
+			},
+		},
+		{
+			Name: "Disabled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			SetStateQuery(test.Enabled)
+			t.Cleanup(func() {
+				SetStateQuery(true)
+			})
+
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: state
+  version: 0.0.1
+do:
+  - first:
+      export:
+        as: first
+      set:
+        progress: started
+  - login:
+      set:
+        password: hunter2
+  - pause:
+      wait:
+        minutes: 1`)
+			env := newTestEnvironment(t, doc, DoTaskOpts{
+				Secrets: []string{"password"},
+			})
+
+			env.RegisterDelayedCallback(func() {
+				res, err := env.QueryWorkflow(StateQuery)
+				if !test.Enabled {
+					assert.Error(t, err)
+					return
+				}
+				assert.NoError(t, err)
+
+				var state map[string]any
+				assert.NoError(t, res.Get(&state))
+
+				// Workflow info is added to the data by the workflow
+				delete(state["data"].(map[string]any), "workflow")
+				assert.Equal(t, test.Expected, state)
+			}, time.Second)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{"secret": "s3cr3t"}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+		})
+	}
+}
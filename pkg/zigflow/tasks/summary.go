@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/workflow"
+)
+
+// withTaskSummary sets the summary of the task's activities, which is shown in
+// the Temporal UI, and the workflow's current details while the task runs. The
+// summary defaults to the task name. These are evaluated as a side effect so
+// they're deterministic. The returned function restores the workflow's details
+// once the task has finished.
+func (t *DoTaskBuilder) withTaskSummary(ctx workflow.Context, task workflowFunc, state *utils.State) (workflow.Context, func(), error) {
+	m := task.GetTask().GetBase().Metadata
+
+	summary, err := metadata.GetSummary(m)
+	if err != nil {
+		return nil, nil, newValidationError("Invalid summary metadata", err, task.Name)
+	}
+	details, err := metadata.GetDetails(m)
+	if err != nil {
+		return nil, nil, newValidationError("Invalid details metadata", err, task.Name)
+	}
+
+	clearDetails := func() {}
+	if details != "" {
+		if details, err = t.evaluateText(ctx, details, state); err != nil {
+			return nil, nil, fmt.Errorf("error evaluating details: %w", err)
+		}
+
+		previous := workflow.GetCurrentDetails(ctx)
+		workflow.SetCurrentDetails(ctx, details)
+		clearDetails = func() {
+			workflow.SetCurrentDetails(ctx, previous)
+		}
+	}
+
+	if summary == "" {
+		return ctx, clearDetails, nil
+	}
+
+	if summary, err = t.evaluateText(ctx, summary, state); err != nil {
+		clearDetails()
+		return nil, nil, fmt.Errorf("error evaluating summary: %w", err)
+	}
+
+	ao := workflow.GetActivityOptions(ctx)
+	ao.Summary = summary
+
+	return workflow.WithActivityOptions(ctx, ao), clearDetails, nil
+}
+
+// workflowDescription returns the description of the workflow, which is the do
+// task's description or, if not set, the document's
+func (t *DoTaskBuilder) workflowDescription() (string, error) {
+	description, err := metadata.GetDescription(t.task.Metadata)
+	if err != nil {
+		return "", fmt.Errorf("invalid description metadata for task %s: %w", t.GetTaskName(), err)
+	}
+	if description != "" || t.doc == nil {
+		return description, nil
+	}
+
+	if description, err = metadata.GetDescription(t.doc.Document.Metadata); err != nil {
+		return "", fmt.Errorf("invalid document description metadata: %w", err)
+	}
+
+	return description, nil
+}
+
+// evaluateText evaluates a string that may be a runtime expression
+func (t *DoTaskBuilder) evaluateText(ctx workflow.Context, text string, state *utils.State) (string, error) {
+	res, err := utils.EvaluateString(text, state, func(fn func() (any, error)) (any, error) {
+		return t.sideEffectWrapper(ctx, fn)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(res), nil
+}
+
+// taskSummary returns the summary to start child workflows with
+func taskSummary(ctx workflow.Context) string {
+	return workflow.GetActivityOptions(ctx).Summary
+}
+
+// validateTaskSummary checks that the summary, details and description metadata
+// are valid
+func validateTaskSummary(taskName string, task model.Task) error {
+	if _, err := metadata.GetDescription(task.GetBase().Metadata); err != nil {
+		return fmt.Errorf("invalid description metadata for task %s: %w", taskName, err)
+	}
+	if _, err := metadata.GetSummary(task.GetBase().Metadata); err != nil {
+		return fmt.Errorf("invalid summary metadata for task %s: %w", taskName, err)
+	}
+	if _, err := metadata.GetDetails(task.GetBase().Metadata); err != nil {
+		return fmt.Errorf("invalid details metadata for task %s: %w", taskName, err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// summaryRecorder records the summaries and details that activities and child
+// workflows are started with
+type summaryRecorder struct {
+	interceptor.WorkerInterceptorBase
+
+	mu         sync.Mutex
+	activities []string
+	details    []string
+	children   []string
+}
+
+func (r *summaryRecorder) InterceptWorkflow(
+	_ workflow.Context, next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	return &summaryInbound{WorkflowInboundInterceptorBase: interceptor.WorkflowInboundInterceptorBase{Next: next}, recorder: r}
+}
+
+type summaryInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+
+	recorder *summaryRecorder
+}
+
+func (i *summaryInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	return i.Next.Init(&summaryOutbound{
+		WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound},
+		recorder:                        i.recorder,
+	})
+}
+
+type summaryOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+
+	recorder *summaryRecorder
+}
+
+func (o *summaryOutbound) ExecuteActivity(ctx workflow.Context, activityType string, args ...any) workflow.Future {
+	o.recorder.mu.Lock()
+	o.recorder.activities = append(o.recorder.activities, workflow.GetActivityOptions(ctx).Summary)
+	o.recorder.details = append(o.recorder.details, workflow.GetCurrentDetails(ctx))
+	o.recorder.mu.Unlock()
+
+	return o.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func (o *summaryOutbound) ExecuteChildWorkflow(ctx workflow.Context, childWorkflowType string, args ...any) workflow.ChildWorkflowFuture {
+	o.recorder.mu.Lock()
+	o.recorder.children = append(o.recorder.children, workflow.GetChildWorkflowOptions(ctx).StaticSummary)
+	o.recorder.mu.Unlock()
+
+	return o.Next.ExecuteChildWorkflow(ctx, childWorkflowType, args...)
+}
+
+func TestTaskSummary(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/charge", httpmock.NewStringResponder(http.StatusOK, ""))
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/notify", httpmock.NewStringResponder(http.StatusOK, ""))
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: summary
+  version: 0.0.1
+do:
+  - charge:
+      metadata:
+        summary: ${ "Charging card for order " + .input.orderId }
+        details: ${ "Order **" + .input.orderId + "**" }
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/charge
+  - notify:
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/notify
+  - attempt:
+      metadata:
+        summary: Trying something risky
+      try:
+        - step:
+            set:
+              hello: world
+      catch:
+        do:
+          - recover:
+              set:
+                hello: world`)
+	env := newTestEnvironment(t, doc)
+
+	recorder := &summaryRecorder{}
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{recorder},
+	})
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"orderId": "123"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	assert.Equal(t, []string{"Charging card for order 123", "notify"}, recorder.activities)
+	// The details are cleared once the task has finished
+	assert.Equal(t, []string{"Order **123**", ""}, recorder.details)
+	assert.Equal(t, []string{"Trying something risky"}, recorder.children)
+}
+
+func TestWorkflowDescription(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/charge", httpmock.NewStringResponder(http.StatusOK, ""))
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/notify", httpmock.NewStringResponder(http.StatusOK, ""))
+
+	tests := []struct {
+		Name        string
+		Metadata    string
+		Description string
+	}{
+		{
+			Name: "No description",
+		},
+		{
+			Name: "Document description",
+			Metadata: `  metadata:
+    description: Charges the customer`,
+			Description: "Charges the customer",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: description
+  version: 0.0.1
+`+test.Metadata+`
+do:
+  - charge:
+      metadata:
+        details: Charging card
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/charge
+  - notify:
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/notify`)
+			env := newTestEnvironment(t, doc)
+
+			recorder := &summaryRecorder{}
+			env.SetWorkerOptions(worker.Options{
+				Interceptors: []interceptor.WorkerInterceptor{recorder},
+			})
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			// The task's details replace the description while it runs
+			assert.Equal(t, []string{"Charging card", test.Description}, recorder.details)
+		})
+	}
+}
+
+func TestTaskSummaryValidation(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata string
+		Error    string
+	}{
+		{
+			Name:     "Invalid summary",
+			Metadata: "summary: 123",
+			Error:    "invalid summary metadata for task step: summary must be a string",
+		},
+		{
+			Name:     "Invalid details",
+			Metadata: "details: [hello]",
+			Error:    "invalid details metadata for task step: details must be a string",
+		},
+		{
+			Name:     "Invalid description",
+			Metadata: "description: 123",
+			Error:    "invalid description metadata for task step: description must be a string",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: summary
+  version: 0.0.1
+do:
+  - step:
+      metadata:
+        `+test.Metadata+`
+      set:
+        hello: world`)
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			_, err = builder.Build()
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+}
@@ -18,6 +18,8 @@ package tasks
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
 	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
@@ -43,15 +45,57 @@ type TaskBuilder interface {
 	ShouldRun(*utils.State) (bool, error)
 }
 
+// flowDirective can be returned by a task to change the task that runs next in
+// the parent do list, in the same way as setting "then" on the task
+type flowDirective struct {
+	Then *model.FlowDirective
+}
+
 type TemporalWorkflowFunc func(ctx workflow.Context, input any, state *utils.State) (output any, err error)
 
 type builder[T model.Task] struct {
+	inheritedOptions
+
 	doc            *model.Workflow
 	name           string
 	task           T
 	temporalWorker worker.Worker
 }
 
+// inheritedOptions are set on a do task's builder and passed to every builder
+// it creates, so they apply to the whole document
+type inheritedOptions struct {
+	// The worker's default activity options
+	activityDefaults ActivityDefaults
+	// The names of the envvars and data keys whose values are secret
+	secrets []string
+}
+
+// inheritedOptionsSetter is implemented by every builder, so the options can
+// be passed to the builders that a builder creates
+type inheritedOptionsSetter interface {
+	setInheritedOptions(inheritedOptions)
+}
+
+func (d *builder[T]) setInheritedOptions(opts inheritedOptions) {
+	d.inheritedOptions = opts
+}
+
+// newTaskBuilder creates the builder for a task inside this one, which uses
+// the same inherited options
+func (d *builder[T]) newTaskBuilder(taskName string, task model.Task) (TaskBuilder, error) {
+	b, err := NewTaskBuilder(taskName, task, d.temporalWorker, d.doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := b.(inheritedOptionsSetter); ok {
+		s.setInheritedOptions(d.inheritedOptions)
+	}
+
+	return b, nil
+}
+
 func (d *builder[T]) GetTask() model.Task {
 	return d.task
 }
@@ -60,30 +104,59 @@ func (d *builder[T]) GetTaskName() string {
 	return d.name
 }
 
+// searchAttributeSideEffectChangeID is the workflow version change that
+// started interpolating the search attributes in a side effect
+const searchAttributeSideEffectChangeID = "zigflow-search-attribute-side-effect"
+
+// childWorkflowIDChangeID is the workflow version change that added the task
+// name to the IDs of the child workflows a task starts
+const childWorkflowIDChangeID = "zigflow-child-workflow-id-task-name"
+
+// childWorkflowIDPrefix returns the start of the IDs of the child workflows
+// this task starts. It includes the task name so that tasks running in
+// parallel don't start children with the same ID. Workflows started before
+// this only use the parent's ID.
+func (d *builder[T]) childWorkflowIDPrefix(ctx workflow.Context) string {
+	parentID := workflow.GetInfo(ctx).WorkflowExecution.ID
+	if workflow.GetVersion(ctx, childWorkflowIDChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return parentID
+	}
+
+	return fmt.Sprintf("%s_%s", parentID, d.GetTaskName())
+}
+
 func (d builder[T]) ParseMetadata(ctx workflow.Context, state *utils.State) error {
 	logger := workflow.GetLogger(ctx)
 
-	task := d.GetTask().GetBase()
-
-	if len(task.Metadata) == 0 {
-		// No metadata set - continue
+	search, ok := d.GetTask().GetBase().Metadata[metadata.MetadataSearchAttribute]
+	if !ok {
+		// Nothing to set - continue
 		return nil
 	}
 
-	// Clone the metadata to avoid pollution
-	mClone := swUtils.DeepClone(task.Metadata)
+	// Interpolate the search attributes against the state, so they can be set
+	// from the workflow data. The clone avoids polluting the task. This is in a
+	// side effect as the expressions may not be deterministic, eg ${ uuid }.
+	// Workflows started before this evaluated them directly.
+	evaluate := func() (any, error) {
+		return utils.TraverseAndEvaluateObj(model.NewObjectOrRuntimeExpr(swUtils.DeepCloneValue(search)), state)
+	}
 
-	parsed, err := utils.TraverseAndEvaluateObj(model.NewObjectOrRuntimeExpr(mClone), state)
+	var res any
+	var err error
+	if workflow.GetVersion(ctx, searchAttributeSideEffectChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		res, err = evaluate()
+	} else {
+		res, err = d.sideEffectWrapper(ctx, evaluate)
+	}
 	if err != nil {
-		return fmt.Errorf("error interpolating metadata: %w", err)
+		return fmt.Errorf("error interpolating search attributes: %w", err)
 	}
 
-	if search, ok := parsed[metadata.MetadataSearchAttribute]; ok {
-		logger.Debug("Parsing search attributes")
-		if err := metadata.ParseSearchAttributes(ctx, search); err != nil {
-			logger.Error("Error parsing search attributes", "attributes", search, "error", err)
-			return fmt.Errorf("error parsing search attributes: %w", err)
-		}
+	logger.Debug("Parsing search attributes")
+	if err := metadata.ParseSearchAttributes(ctx, res); err != nil {
+		logger.Error("Error parsing search attributes", "attributes", res, "error", err)
+		return fmt.Errorf("error parsing search attributes: %w", err)
 	}
 
 	return nil
@@ -98,12 +171,42 @@ func (d *builder[T]) ShouldRun(state *utils.State) (bool, error) {
 	return utils.CheckIfStatement(d.task.GetBase().If, state)
 }
 
+// sideEffectWrapper creates a wrapper function for the Runtime Expression traversal to ensure that
+// the generated values are set deterministically. For many things, this might be considered overkill
+// as input/envvars/state are likely to be determinstic. However, as this also supports things like
+// generation of UUIDs, there could be non-deterministic values being set.
+func (d *builder[T]) sideEffectWrapper(ctx workflow.Context, fn func() (any, error)) (any, error) {
+	var val any
+	var sideEffectErr error
+	err := workflow.SideEffect(ctx, func(ctx workflow.Context) any {
+		res, err := fn()
+		if err != nil {
+			sideEffectErr = err
+			return nil
+		}
+		return res
+	}).Get(&val)
+	if err != nil {
+		return nil, fmt.Errorf("error running side effect: %w", err)
+	}
+	if sideEffectErr != nil {
+		return nil, fmt.Errorf("error running runtime expression: %w", sideEffectErr)
+	}
+
+	return val, nil
+}
+
 // Factory to create a TaskBuilder instance, or die trying
 func NewTaskBuilder(taskName string, task model.Task, temporalWorker worker.Worker, doc *model.Workflow) (TaskBuilder, error) {
 	switch t := task.(type) {
 	case *model.CallHTTP:
 		return NewCallHTTPTaskBuilder(temporalWorker, t, taskName, doc)
+	case *model.CallFunction:
+		return newCallFunctionTaskBuilder(temporalWorker, t, taskName, doc)
 	case *model.DoTask:
+		if isLoop(t) {
+			return NewLoopTaskBuilder(temporalWorker, t, taskName, doc)
+		}
 		return NewDoTaskBuilder(temporalWorker, t, taskName, doc)
 	case *model.ForTask:
 		return NewForTaskBuilder(temporalWorker, t, taskName, doc)
@@ -128,13 +231,62 @@ func NewTaskBuilder(taskName string, task model.Task, temporalWorker worker.Work
 	}
 }
 
+// newCallFunctionTaskBuilder creates the builder for a call task that isn't
+// built into the DSL, such as a Nexus call
+func newCallFunctionTaskBuilder(
+	temporalWorker worker.Worker, task *model.CallFunction, taskName string, doc *model.Workflow,
+) (TaskBuilder, error) {
+	switch task.Call {
+	case CallCancel:
+		return NewCallCancelTaskBuilder(temporalWorker, task, taskName, doc)
+	case CallNexus:
+		return NewCallNexusTaskBuilder(temporalWorker, task, taskName, doc)
+	case CallSignal:
+		return NewCallSignalTaskBuilder(temporalWorker, task, taskName, doc)
+	default:
+		return nil, fmt.Errorf("unsupported call type '%s' for task '%s'", task.Call, taskName)
+	}
+}
+
+// ValidateTaskTypes reports every task in the document that has no builder.
+// This allows all unsupported tasks to be listed at once, rather than failing
+// on the first one when the workflow is built.
+func ValidateTaskTypes(doc *model.Workflow) []utils.ValidationErrors {
+	vErrs := make([]utils.ValidationErrors, 0)
+
+	validate := func(prefix string) func(path string, task *model.TaskItem) {
+		return func(path string, task *model.TaskItem) {
+			if _, err := NewTaskBuilder(task.Key, task.Task, nil, doc); err != nil {
+				vErrs = append(vErrs, utils.ValidationErrors{
+					Key:     prefix + path,
+					Message: fmt.Sprintf("unsupported task type '%T'", task.Task),
+				})
+			}
+		}
+	}
+
+	utils.WalkTasks(doc.Do, validate(""))
+
+	// Invalid inline workflows are reported when they're built
+	workflows, _ := metadata.GetWorkflows(doc)
+	for _, name := range slices.Sorted(maps.Keys(workflows)) {
+		utils.WalkTasks(workflows[name], validate(metadata.MetadataWorkflows+"."+name+"."))
+	}
+
+	return vErrs
+}
+
 // Ensure the tasks meets the TaskBuilder type
 var (
+	_ TaskBuilder = &CallCancelTaskBuilder{}
 	_ TaskBuilder = &CallHTTPTaskBuilder{}
+	_ TaskBuilder = &CallNexusTaskBuilder{}
+	_ TaskBuilder = &CallSignalTaskBuilder{}
 	_ TaskBuilder = &DoTaskBuilder{}
 	_ TaskBuilder = &ForTaskBuilder{}
 	_ TaskBuilder = &ForkTaskBuilder{}
 	_ TaskBuilder = &ListenTaskBuilder{}
+	_ TaskBuilder = &LoopTaskBuilder{}
 	_ TaskBuilder = &RaiseTaskBuilder{}
 	_ TaskBuilder = &RunTaskBuilder{}
 	_ TaskBuilder = &SetTaskBuilder{}
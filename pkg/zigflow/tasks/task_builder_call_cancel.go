@@ -0,0 +1,200 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func init() {
+	activities = append(activities, terminateWorkflowActivity)
+}
+
+// CallCancel is the call type of a task that cancels or terminates another
+// workflow
+const CallCancel = "cancel"
+
+const (
+	// cancelModeCancel requests the workflow is cancelled, which it can handle
+	cancelModeCancel = "cancel"
+	// cancelModeTerminate terminates the workflow immediately
+	cancelModeTerminate = "terminate"
+)
+
+// cancelCall is the "with" of a cancel call task. The workflow and run IDs can
+// be runtime expressions. If the run ID isn't set, the workflow's current run
+// is cancelled.
+type cancelCall struct {
+	WorkflowID string `mapstructure:"workflowId"`
+	RunID      string `mapstructure:"runId"`
+	Mode       string `mapstructure:"mode"`
+	Reason     string `mapstructure:"reason"`
+}
+
+func NewCallCancelTaskBuilder(
+	temporalWorker worker.Worker,
+	task *model.CallFunction,
+	taskName string,
+	doc *model.Workflow,
+) (*CallCancelTaskBuilder, error) {
+	return &CallCancelTaskBuilder{
+		builder: builder[*model.CallFunction]{
+			doc:            doc,
+			name:           taskName,
+			task:           task,
+			temporalWorker: temporalWorker,
+		},
+	}, nil
+}
+
+type CallCancelTaskBuilder struct {
+	builder[*model.CallFunction]
+}
+
+func (t *CallCancelTaskBuilder) PostLoad() error {
+	_, err := t.cancelCall()
+	return err
+}
+
+func (t *CallCancelTaskBuilder) Build() (TemporalWorkflowFunc, error) {
+	call, err := t.cancelCall()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
+		logger := workflow.GetLogger(ctx)
+
+		target, err := t.mapTarget(ctx, call, state)
+		if err != nil {
+			logger.Error("Error mapping workflow to cancel", "name", t.name, "error", err)
+			return nil, err
+		}
+
+		logger.Debug("Cancelling workflow",
+			"name", t.name, "workflowId", target.WorkflowID, "runId", target.RunID, "mode", call.Mode,
+		)
+
+		if call.Mode == cancelModeTerminate {
+			err = workflow.ExecuteActivity(ctx, terminateWorkflowActivity, target.WorkflowID, target.RunID, call.Reason).Get(ctx, nil)
+		} else {
+			err = workflow.RequestCancelExternalWorkflow(ctx, target.WorkflowID, target.RunID).Get(ctx, nil)
+		}
+		if err != nil {
+			if temporal.IsCanceledError(err) {
+				return nil, nil
+			}
+
+			var notFound *temporal.UnknownExternalWorkflowExecutionError
+			if errors.As(err, &notFound) {
+				logger.Error("Workflow to cancel not found", "name", t.name, "workflowId", target.WorkflowID)
+				return nil, newNotFoundError(fmt.Sprintf("Workflow %s not found", target.WorkflowID), err, t.GetTaskName())
+			}
+
+			logger.Error("Error cancelling workflow", "name", t.name, "mode", call.Mode, "error", err)
+			return nil, fmt.Errorf("error cancelling workflow: %w", err)
+		}
+
+		return nil, nil
+	}, nil
+}
+
+// cancelCall parses and validates the task's "with"
+func (t *CallCancelTaskBuilder) cancelCall() (*cancelCall, error) {
+	var call cancelCall
+	if err := mapstructure.Decode(t.task.With, &call); err != nil {
+		return nil, fmt.Errorf("invalid cancel call for task %s: %w", t.GetTaskName(), err)
+	}
+
+	if call.WorkflowID == "" {
+		return nil, fmt.Errorf("cancel call for task %s must set the workflowId", t.GetTaskName())
+	}
+
+	switch call.Mode {
+	case "":
+		call.Mode = cancelModeCancel
+	case cancelModeCancel, cancelModeTerminate:
+	default:
+		return nil, fmt.Errorf("cancel call for task %s must have a mode of %s or %s", t.GetTaskName(), cancelModeCancel, cancelModeTerminate)
+	}
+
+	return &call, nil
+}
+
+// mapTarget interpolates the workflow and run IDs against the state. This is
+// evaluated as a side effect so it's deterministic.
+func (t *CallCancelTaskBuilder) mapTarget(ctx workflow.Context, call *cancelCall, state *utils.State) (*cancelCall, error) {
+	res, err := utils.TraverseAndEvaluateObj(
+		model.NewObjectOrRuntimeExpr(swUtil.DeepClone(map[string]any{
+			"workflowId": call.WorkflowID,
+			"runId":      call.RunID,
+		})),
+		state,
+		func(fn func() (any, error)) (any, error) {
+			return t.sideEffectWrapper(ctx, fn)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cancel call: %w", err)
+	}
+
+	workflowID, ok := res["workflowId"].(string)
+	if !ok || workflowID == "" {
+		return nil, newValidationError("Workflow id to cancel must be a non-empty string", nil, t.GetTaskName())
+	}
+	runID, ok := res["runId"].(string)
+	if !ok {
+		return nil, newValidationError("Run id to cancel must be a string", nil, t.GetTaskName())
+	}
+
+	return &cancelCall{
+		WorkflowID: workflowID,
+		RunID:      runID,
+	}, nil
+}
+
+// terminateWorkflowActivity terminates the workflow. There's no workflow API
+// to terminate another workflow, so this uses the worker's client.
+func terminateWorkflowActivity(ctx context.Context, workflowID, runID, reason string) error {
+	logger := activity.GetLogger(ctx)
+	logger.Debug("Terminating workflow", "workflowId", workflowID, "runId", runID)
+
+	if err := activity.GetClient(ctx).TerminateWorkflow(ctx, workflowID, runID, reason); err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			logger.Error("Workflow to terminate not found", "workflowId", workflowID)
+			return newNotFoundError(fmt.Sprintf("Workflow %s not found", workflowID), err, "")
+		}
+
+		logger.Error("Error terminating workflow", "workflowId", workflowID, "error", err)
+		return fmt.Errorf("error terminating workflow: %w", err)
+	}
+
+	return nil
+}
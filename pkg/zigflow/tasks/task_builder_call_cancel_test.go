@@ -0,0 +1,162 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestCallCancel(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: cancel
+  version: 0.0.1
+do:
+  - stop:
+      call: cancel
+      with:
+        workflowId: ${ "job-" + .input.jobId }`)
+	env := newTestEnvironment(t, doc)
+
+	env.OnRequestCancelExternalWorkflow(mock.Anything, "job-123", "").Return(nil).Once()
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"jobId": "123"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestCallCancelTerminate(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: cancel
+  version: 0.0.1
+do:
+  - stop:
+      call: cancel
+      with:
+        workflowId: ${ "job-" + .input.jobId }
+        runId: some-run
+        mode: terminate
+        reason: Job is stuck`)
+	env := newTestEnvironment(t, doc)
+
+	env.OnActivity(terminateWorkflowActivity, mock.Anything, "job-123", "some-run", "Job is stuck").Return(nil).Once()
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"jobId": "123"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestCallCancelNotFound(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: cancel
+  version: 0.0.1
+do:
+  - stop:
+      call: cancel
+      with:
+        workflowId: missing`)
+	env := newTestEnvironment(t, doc)
+
+	env.OnRequestCancelExternalWorkflow(mock.Anything, "missing", "").
+		Return(&temporal.UnknownExternalWorkflowExecutionError{})
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, ErrorTypeNotFound, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
+
+func TestCallCancelValidation(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Task  string
+		Error string
+	}{
+		{
+			Name: "valid",
+			Task: `call: cancel
+      with:
+        workflowId: job-123
+        mode: terminate`,
+		},
+		{
+			Name: "missing workflow id",
+			Task: `call: cancel
+      with:
+        mode: cancel`,
+			Error: "cancel call for task stop must set the workflowId",
+		},
+		{
+			Name: "unknown mode",
+			Task: `call: cancel
+      with:
+        workflowId: job-123
+        mode: pause`,
+			Error: "cancel call for task stop must have a mode of cancel or terminate",
+		},
+		{
+			Name: "invalid with",
+			Task: `call: cancel
+      with:
+        workflowId:
+          id: job-123`,
+			Error: "invalid cancel call for task stop",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: cancel
+  version: 0.0.1
+do:
+  - stop:
+      `+test.Task)
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			err = builder.PostLoad()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.Error)
+		})
+	}
+}
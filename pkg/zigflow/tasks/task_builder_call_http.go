@@ -24,14 +24,26 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/mrsimonemms/zigflow/pkg/tracing"
 	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
 	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/log"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
@@ -43,10 +55,36 @@ func init() {
 
 // @link: https://github.com/serverlessworkflow/specification/blob/main/dsl-reference.md#http-response
 type HTTPResponse struct {
-	Request    HTTPRequest       `json:"request"`
-	StatusCode int               `json:"statusCode"`
-	Headers    map[string]string `json:"headers,omitempty"`
-	Content    any               `json:"content,omitempty"`
+	Request    HTTPRequest `json:"request"`
+	StatusCode int         `json:"statusCode"`
+	// Headers are keyed by their canonical name (eg, Content-Type). Values
+	// are comma-separated strings, except for those which can't be combined
+	// (eg, Set-Cookie) which are always a list.
+	Headers map[string]any `json:"headers,omitempty"`
+	Content any            `json:"content,omitempty"`
+	// How long the server took to respond, in milliseconds
+	DurationMs int64 `json:"durationMs"`
+}
+
+// Content-Type sent with a string body if the task doesn't set one
+const defaultTextContentType = "text/plain; charset=utf-8"
+
+// The default maximum size of an HTTP response body. A value of 0 means there
+// is no limit.
+var httpMaxBodyBytes int64
+
+// SetHTTPMaxBodyBytes sets the default maximum size of an HTTP response body,
+// which can be overridden by the task's metadata
+func SetHTTPMaxBodyBytes(maxBytes int64) {
+	httpMaxBodyBytes = maxBytes
+}
+
+// listHeaders are response headers whose values can't be combined into a
+// single comma-separated string
+//
+// @link: https://www.rfc-editor.org/rfc/rfc9110#section-5.3
+var listHeaders = map[string]bool{
+	"Set-Cookie": true,
 }
 
 // @link: https://github.com/serverlessworkflow/specification/blob/main/dsl-reference.md#http-request
@@ -76,13 +114,48 @@ type CallHTTPTaskBuilder struct {
 	builder[*model.CallHTTP]
 }
 
+func (t *CallHTTPTaskBuilder) PostLoad() error {
+	if _, err := metadata.GetIdempotencyHeader(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid idempotency header metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	if _, err := metadata.GetMaxBodyBytes(t.task.Metadata, httpMaxBodyBytes); err != nil {
+		return fmt.Errorf("invalid max body bytes metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	if _, err := metadata.GetRetryOn(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid retry on metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	if _, err := metadata.GetHTTPOutputFormat(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid output format metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	if _, err := t.resolveAuthentication(); err != nil {
+		return err
+	}
+
+	_, err := localActivityOptions(t.GetTaskName(), t.task.Metadata)
+	return err
+}
+
 func (t *CallHTTPTaskBuilder) Build() (TemporalWorkflowFunc, error) {
+	localOpts, err := localActivityOptions(t.GetTaskName(), t.task.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := t.resolveAuthentication()
+	if err != nil {
+		return nil, err
+	}
+
 	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
 		logger := workflow.GetLogger(ctx)
-		logger.Debug("Calling HTTP endpoint", "name", t.name)
+		logger.Debug("Calling HTTP endpoint", "name", t.name, "localActivity", localOpts != nil)
 
 		var res any
-		if err := workflow.ExecuteActivity(ctx, callHTTPActivity, t.task, input, state).Get(ctx, &res); err != nil {
+		if err := executeActivity(ctx, localOpts, callHTTPActivity, task, input, state, t.secrets).Get(ctx, &res); err != nil {
 			if temporal.IsCanceledError(err) {
 				return nil, nil
 			}
@@ -91,20 +164,18 @@ func (t *CallHTTPTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 			return nil, fmt.Errorf("error calling http task: %w", err)
 		}
 
-		// Add the result to the state's data
-		logger.Debug("Setting data to the state", "key", t.name)
-		state.AddData(map[string]any{
-			t.name: res,
-		})
-
 		return res, nil
 	}, nil
 }
 
-func callHTTPAction(ctx context.Context, task *model.CallHTTP, timeout time.Duration, state *utils.State) (
+// callHTTPAction makes the HTTP call. The URL and request headers returned are
+// for logging and the response, so have any secret values masked. The duration
+// is how long the server took to respond.
+func callHTTPAction(ctx context.Context, task *model.CallHTTP, timeout time.Duration, state *utils.State, secrets *utils.Secrets) (
 	resp *http.Response,
 	method, url string,
 	reqHeaders map[string]string,
+	duration time.Duration,
 	err error,
 ) {
 	logger := activity.GetLogger(ctx)
@@ -114,25 +185,38 @@ func callHTTPAction(ctx context.Context, task *model.CallHTTP, timeout time.Dura
 		return resp,
 			method, url,
 			reqHeaders,
+			duration,
 			err
 	}
 
 	method = strings.ToUpper(args.Method)
-	url = args.Endpoint.String()
-	body := args.Body
+	url = secrets.Mask(args.Endpoint.String())
+	body, isText := requestBody(args.Body)
 
 	logger.Debug("Making HTTP call", "method", method, "url", url)
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, method, args.Endpoint.String(), bytes.NewBuffer(body))
 	if err != nil {
 		logger.Error("Error making HTTP request", "method", method, "url", url, "error", err)
-		return resp, method, url, reqHeaders, err
+		return resp, method, url, reqHeaders, duration, err
 	}
 
 	// Add in headers
 	reqHeaders = map[string]string{}
 	for k, v := range args.Headers {
 		req.Header.Add(k, v)
-		reqHeaders[k] = v
+		reqHeaders[k] = secrets.Mask(v)
+	}
+	if isText && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", defaultTextContentType)
+		reqHeaders["Content-Type"] = defaultTextContentType
+	}
+	if err := addAuthentication(req, args.Endpoint); err != nil {
+		logger.Error("Error adding authentication", "method", method, "url", url, "error", err)
+		return resp, method, url, reqHeaders, duration, err
+	}
+	if err := addIdempotencyKey(ctx, task, req, reqHeaders); err != nil {
+		logger.Error("Error adding idempotency key", "method", method, "url", url, "error", err)
+		return resp, method, url, reqHeaders, duration, err
 	}
 
 	// Add in query strings
@@ -152,23 +236,106 @@ func callHTTPAction(ctx context.Context, task *model.CallHTTP, timeout time.Dura
 		}
 	}
 
-	resp, err = client.Do(req)
+	// Trace the request and pass the trace context to the server
+	spanCtx, span := tracing.Tracer().Start(ctx, "HTTP "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("url.full", url),
+		),
+	)
+	defer span.End()
+	otel.GetTextMapPropagator().Inject(spanCtx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err = client.Do(req.WithContext(spanCtx))
+	duration = time.Since(start)
+	if err != nil {
+		// The error contains the unmasked URL
+		if masked := secrets.Mask(err.Error()); masked != err.Error() {
+			err = errors.New(masked)
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, method, url, reqHeaders, duration, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	return resp, method, url, reqHeaders, duration, err
+}
+
+// requestBody returns the body to send. A string body is sent as-is, rather
+// than as a JSON string, so APIs that expect text (eg, GraphQL or XML) can be
+// called. Anything else is sent as JSON.
+func requestBody(raw json.RawMessage) (body []byte, isText bool) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return raw, false
+	}
+
+	return []byte(text), true
+}
+
+// addIdempotencyKey sends a key which is the same for every retry of the HTTP
+// call, so the server can safely ignore duplicate requests. A header set by the
+// task takes precedence.
+func addIdempotencyKey(ctx context.Context, task *model.CallHTTP, req *http.Request, reqHeaders map[string]string) error {
+	header, err := metadata.GetIdempotencyHeader(task.Metadata)
 	if err != nil {
-		return resp, method, url, reqHeaders, err
+		return err
+	}
+	if header == "" || req.Header.Get(header) != "" {
+		return nil
 	}
 
-	return resp, method, url, reqHeaders, err
+	key := idempotencyKey(activity.GetInfo(ctx))
+	req.Header.Set(header, key)
+	reqHeaders[header] = key
+
+	return nil
+}
+
+// idempotencyKey generates a deterministic key for the activity. The activity
+// ID is unique within the workflow run and is reused when it's retried.
+func idempotencyKey(info activity.Info) string {
+	name := strings.Join([]string{info.WorkflowExecution.ID, info.WorkflowExecution.RunID, info.ActivityID}, "/")
+
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
 }
 
-func callHTTPActivity(ctx context.Context, task *model.CallHTTP, input any, state *utils.State) (any, error) {
+func callHTTPActivity(ctx context.Context, task *model.CallHTTP, input any, state *utils.State, secrets []string) (any, error) {
 	logger := activity.GetLogger(ctx)
 	logger.Debug("Running call HTTP activity")
 
 	state = state.AddActivityInfo(ctx)
 
+	if err := resolveStateOutputs(ctx, state); err != nil {
+		logger.Error("Error resolving offloaded outputs", "error", err)
+		return nil, err
+	}
+
+	maxBodyBytes, err := metadata.GetMaxBodyBytes(task.Metadata, httpMaxBodyBytes)
+	if err != nil {
+		logger.Error("Error getting max body bytes", "error", err)
+		return nil, err
+	}
+
+	retryOn, err := metadata.GetRetryOn(task.Metadata)
+	if err != nil {
+		logger.Error("Error getting retry on status codes", "error", err)
+		return nil, err
+	}
+
+	outputFormat, err := metadata.GetHTTPOutputFormat(task.Metadata)
+	if err != nil {
+		logger.Error("Error getting output format", "error", err)
+		return nil, err
+	}
+
 	info := activity.GetInfo(ctx)
+	masks := stateSecrets(secrets, state)
 
-	resp, method, url, reqHeaders, err := callHTTPAction(ctx, task, info.StartToCloseTimeout, state)
+	resp, method, url, reqHeaders, duration, err := callHTTPAction(ctx, task, info.StartToCloseTimeout, state, masks)
 	if err != nil {
 		logger.Error("Error making HTTP call", "method", method, "url", url, "error", err)
 		return nil, err
@@ -180,62 +347,26 @@ func callHTTPActivity(ctx context.Context, task *model.CallHTTP, input any, stat
 		}
 	}()
 
-	bodyRes, err := io.ReadAll(resp.Body)
+	bodyRes, err := readHTTPBody(resp, maxBodyBytes)
 	if err != nil {
 		logger.Error("Error reading HTTP body", "method", method, "url", url, "error", err)
 		return nil, err
 	}
 
-	// Try converting the body as JSON, returning as string if not possible
-	var content any
-	var bodyJSON map[string]any
-	if err := json.Unmarshal(bodyRes, &bodyJSON); err != nil {
-		// Log error
-		logger.Debug("Error converting body to JSON", "error", err)
+	content, decodeErr := decodeHTTPBody(resp.Header, bodyRes, outputFormat)
+	if decodeErr != nil {
+		// An unsuccessful status takes precedence
 		content = string(bodyRes)
-	} else {
-		content = bodyJSON
-	}
-
-	// Treat redirects as an error - if you have "redirect = true", this will be ignored
-	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
-		logger.Error("CallHTTP returned 3xx status", "statusCode", resp.StatusCode, "responseBody", content)
-		return nil, temporal.NewNonRetryableApplicationError(
-			"CallHTTP returned 3xx status code",
-			"CallHTTP error",
-			errors.New(resp.Status),
-			content,
-		)
 	}
 
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		// Client error - treat as non-retryable error as we need to fix it
-		logger.Error("CallHTTP returned 4xx error", "statusCode", resp.StatusCode, "responseBody", content)
-		return nil, temporal.NewNonRetryableApplicationError(
-			"CallHTTP returned 4xx status code",
-			"CallHTTP error",
-			errors.New(resp.Status),
-			content,
-		)
-	}
-
-	if resp.StatusCode >= 500 && resp.StatusCode < 600 {
-		// Server error - treat as retryable error as we can't fix it
-		logger.Error("CallHTTP returned 5xx error", "statusCode", resp.StatusCode, "responseBody", content)
-		return nil, temporal.NewApplicationError(
-			"CallHTTP returned 5xx error",
-			"CallHTTP error",
-			errors.New(resp.Status),
-			map[string]any{
-				"statusCode": resp.StatusCode,
-				"content":    content,
-			},
-		)
+	// The content of an unsuccessful response is logged and in the error
+	if err := checkHTTPStatus(logger, task.With.Redirect, retryOn, resp, masks.MaskValue(content)); err != nil {
+		return nil, err
 	}
 
-	respHeader := map[string]string{}
-	for k, v := range resp.Header {
-		respHeader[k] = strings.Join(v, ", ")
+	if decodeErr != nil {
+		logger.Error("Error decoding HTTP body", "method", method, "url", url, "error", decodeErr)
+		return nil, decodeErr
 	}
 
 	httpResponse := HTTPResponse{
@@ -245,11 +376,188 @@ func callHTTPActivity(ctx context.Context, task *model.CallHTTP, input any, stat
 			Headers: reqHeaders,
 		},
 		StatusCode: resp.StatusCode,
-		Headers:    respHeader,
+		Headers:    parseResponseHeaders(resp.Header),
 		Content:    content,
+		DurationMs: duration.Milliseconds(),
+	}
+
+	return offloadResult(ctx, parseOutput(task.With.Output, httpResponse, bodyRes))
+}
+
+// decodeHTTPBody decodes the response body in the output format. By default,
+// a JSON object is parsed and anything else is returned as a string.
+func decodeHTTPBody(header http.Header, body []byte, format string) (any, error) {
+	if format == metadata.OutputFormatContentType {
+		format = contentTypeFormat(header.Get("Content-Type"))
+	}
+
+	switch format {
+	case metadata.OutputFormatText:
+		return string(body), nil
+	case metadata.OutputFormatBytes:
+		return base64.StdEncoding.EncodeToString(body), nil
+	case metadata.OutputFormatJSON:
+		if len(body) == 0 {
+			return nil, nil
+		}
+
+		var content any
+		if err := json.Unmarshal(body, &content); err != nil {
+			return nil, temporal.NewNonRetryableApplicationError("CallHTTP response body is not valid JSON", "CallHTTP error", err)
+		}
+		return content, nil
+	default:
+		var content map[string]any
+		if err := json.Unmarshal(body, &content); err != nil {
+			return string(body), nil
+		}
+		return content, nil
+	}
+}
+
+// contentTypeFormat returns the output format for the response's content type.
+// Anything that isn't JSON or text is treated as binary.
+func contentTypeFormat(contentType string) string {
+	if contentType == "" {
+		return metadata.OutputFormatAuto
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return metadata.OutputFormatAuto
+	}
+
+	switch {
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		return metadata.OutputFormatJSON
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/xml",
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/x-www-form-urlencoded":
+		return metadata.OutputFormatText
+	default:
+		return metadata.OutputFormatBytes
+	}
+}
+
+// readHTTPBody reads the response body, erroring if it's larger than the
+// maximum size rather than loading it all into memory. A maximum of 0 means
+// there is no limit.
+func readHTTPBody(resp *http.Response, maxBytes int64) ([]byte, error) {
+	if maxBytes == 0 {
+		return io.ReadAll(resp.Body)
+	}
+
+	tooLarge := temporal.NewNonRetryableApplicationError(
+		fmt.Sprintf("CallHTTP response body exceeds the maximum size of %d bytes", maxBytes),
+		"CallHTTP error",
+		nil,
+	)
+
+	// Don't read the body if the server has told us it's too large
+	if resp.ContentLength > maxBytes {
+		return nil, tooLarge
+	}
+
+	// Read one byte more than allowed so we know if it's been exceeded
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, tooLarge
+	}
+
+	return body, nil
+}
+
+// checkHTTPStatus converts an unsuccessful status code into an error. A 3xx is
+// only an error if redirects aren't being followed - if they are, the client
+// has already followed them and a 3xx is the final response. A 304 Not
+// Modified is never an error.
+//
+// By default, only a 5xx can be retried. If retryOn is set, only those status
+// codes can be retried. A retryable error waits for any Retry-After header
+// before the next attempt.
+func checkHTTPStatus(logger log.Logger, redirect bool, retryOn []int, resp *http.Response, content any) error {
+	var msg string
+	var details any = content
+	retryable := false
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400 && !redirect && resp.StatusCode != http.StatusNotModified:
+		msg = "CallHTTP returned 3xx status code"
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		// Client error - treat as non-retryable error as we need to fix it
+		msg = "CallHTTP returned 4xx status code"
+	case resp.StatusCode >= 500 && resp.StatusCode < 600:
+		// Server error - treat as retryable error as we can't fix it
+		msg = "CallHTTP returned 5xx error"
+		details = map[string]any{
+			"statusCode": resp.StatusCode,
+			"content":    content,
+		}
+		retryable = true
+	default:
+		return nil
+	}
+
+	if retryOn != nil {
+		retryable = slices.Contains(retryOn, resp.StatusCode)
+	}
+
+	var delay time.Duration
+	if retryable {
+		delay = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+
+	logger.Error(msg, "statusCode", resp.StatusCode, "retryable", retryable, "retryAfter", delay, "responseBody", content)
+	return temporal.NewApplicationErrorWithOptions(msg, "CallHTTP error", temporal.ApplicationErrorOptions{
+		NonRetryable:   !retryable,
+		Cause:          errors.New(resp.Status),
+		Details:        []any{details},
+		NextRetryDelay: delay,
+	})
+}
+
+// retryAfter converts a Retry-After header into the time to wait before
+// retrying. This can either be a number of seconds or an HTTP date. Zero means
+// the activity's retry policy is used.
+//
+// @link: https://www.rfc-editor.org/rfc/rfc9110#section-10.2.3
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		return max(t.Sub(now), 0)
+	}
+
+	return 0
+}
+
+// parseResponseHeaders converts the headers into a structure that can be
+// used in runtime expressions
+func parseResponseHeaders(header http.Header) map[string]any {
+	headers := make(map[string]any, len(header))
+	for k, v := range header {
+		k = http.CanonicalHeaderKey(k)
+		if listHeaders[k] {
+			headers[k] = slices.Clone(v)
+		} else {
+			headers[k] = strings.Join(v, ", ")
+		}
 	}
 
-	return parseOutput(task.With.Output, httpResponse, bodyRes), err
+	return headers
 }
 
 // parseHTTPArguments note that I looked at the github.com/go-viper/mapstructure/v2.Decode
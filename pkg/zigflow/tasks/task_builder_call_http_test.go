@@ -0,0 +1,979 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestCallHTTPRedirects(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/moved", func(*http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusMovedPermanently, "")
+		resp.Header.Set("Location", "https://example.com/final")
+		return resp, nil
+	})
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/final", func(*http.Request) (*http.Response, error) {
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"hello": "world"})
+	})
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/cached", httpmock.NewStringResponder(http.StatusNotModified, ""))
+
+	tests := []struct {
+		Name       string
+		Endpoint   string
+		Redirect   bool
+		StatusCode int
+		Content    any
+		Error      bool
+	}{
+		{
+			Name:       "301 with redirect followed",
+			Endpoint:   "https://example.com/moved",
+			Redirect:   true,
+			StatusCode: http.StatusOK,
+			Content:    map[string]any{"hello": "world"},
+		},
+		{
+			Name:     "301 without redirect",
+			Endpoint: "https://example.com/moved",
+			Error:    true,
+		},
+		{
+			Name:       "304 without redirect",
+			Endpoint:   "https://example.com/cached",
+			StatusCode: http.StatusNotModified,
+			Content:    "",
+		},
+		{
+			Name:       "304 with redirect",
+			Endpoint:   "https://example.com/cached",
+			Redirect:   true,
+			StatusCode: http.StatusNotModified,
+			Content:    "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      export:
+        as: response
+      call: http
+      with:
+        method: get
+        endpoint: %s
+        redirect: %t
+        output: response`, test.Endpoint, test.Redirect))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Error {
+				assert.ErrorContains(t, env.GetWorkflowError(), "CallHTTP returned 3xx status code")
+				return
+			}
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+
+			response, ok := result["response"].(map[string]any)
+			assert.True(t, ok)
+			assert.Equal(t, float64(test.StatusCode), response["statusCode"])
+			assert.Equal(t, test.Content, response["content"])
+		})
+	}
+}
+
+func TestCallHTTPResponseHeaders(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/login", func(*http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, "")
+		resp.Header.Set("content-type", "text/plain")
+		resp.Header.Add("Set-Cookie", "session=abc123")
+		resp.Header.Add("Set-Cookie", "theme=dark")
+		resp.Header.Add("Vary", "Accept")
+		resp.Header.Add("Vary", "Accept-Encoding")
+		return resp, nil
+	})
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - login:
+      export:
+        as: login
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/login
+        output: response
+  - headers:
+      export:
+        as: headers
+      set:
+        contentType: ${ .output.login.headers["Content-Type"] }
+        cookies: ${ .output.login.headers["Set-Cookie"] }
+        vary: ${ .output.login.headers.Vary }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"contentType": "text/plain",
+		"cookies":     []any{"session=abc123", "theme=dark"},
+		"vary":        "Accept, Accept-Encoding",
+	}, result["headers"])
+}
+
+func TestCallHTTPDuration(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/slow", func(*http.Request) (*http.Response, error) {
+		time.Sleep(10 * time.Millisecond)
+		return httpmock.NewStringResponse(http.StatusOK, `{"ok":true}`), nil
+	})
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - response:
+      export:
+        as: response
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/slow
+        output: response
+  - content:
+      export:
+        as: content
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/slow`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+
+	duration, ok := result["response"]["durationMs"].(float64)
+	assert.True(t, ok)
+	assert.GreaterOrEqual(t, duration, float64(10))
+
+	// The content is unchanged
+	assert.Equal(t, map[string]any{"ok": true}, result["content"])
+}
+
+func TestCallHTTPMaxBodyBytes(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/body", httpmock.NewStringResponder(http.StatusOK, "0123456789"))
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/length", func(*http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, "0123456789")
+		resp.ContentLength = 10
+		return resp, nil
+	})
+
+	tests := []struct {
+		Name     string
+		Endpoint string
+		Metadata string
+		Default  int64
+		Error    bool
+	}{
+		{
+			Name:     "No limit",
+			Endpoint: "https://example.com/body",
+		},
+		{
+			Name:     "Within limit",
+			Endpoint: "https://example.com/body",
+			Metadata: "maxBodyBytes: 10",
+		},
+		{
+			Name:     "Body exceeds limit",
+			Endpoint: "https://example.com/body",
+			Metadata: "maxBodyBytes: 9",
+			Error:    true,
+		},
+		{
+			Name:     "Content length exceeds limit",
+			Endpoint: "https://example.com/length",
+			Metadata: "maxBodyBytes: 9",
+			Error:    true,
+		},
+		{
+			Name:     "Default exceeded",
+			Endpoint: "https://example.com/body",
+			Default:  5,
+			Error:    true,
+		},
+		{
+			Name:     "Metadata overrides default",
+			Endpoint: "https://example.com/body",
+			Metadata: "maxBodyBytes: 0",
+			Default:  5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			SetHTTPMaxBodyBytes(test.Default)
+			defer SetHTTPMaxBodyBytes(0)
+
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        %s
+      export:
+        as: response
+      call: http
+      with:
+        method: get
+        endpoint: %s`, test.Metadata, test.Endpoint))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Error {
+				assert.ErrorContains(t, env.GetWorkflowError(), "CallHTTP response body exceeds the maximum size")
+				return
+			}
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, "0123456789", result["response"])
+		})
+	}
+}
+
+func TestCallHTTPMaxBodyBytesValidation(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        maxBodyBytes: -1
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com`)
+
+	builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "invalid max body bytes metadata for task get: max body bytes must not be negative")
+}
+
+func TestCallHTTPOutputFormat(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	binary := []byte{0x89, 0x50, 0x4e, 0x47, 0x00, 0xff}
+	respond := func(contentType string, body []byte) httpmock.Responder {
+		return func(*http.Request) (*http.Response, error) {
+			resp := httpmock.NewBytesResponse(http.StatusOK, body)
+			resp.Header.Set("Content-Type", contentType)
+			return resp, nil
+		}
+	}
+
+	httpmock.RegisterResponder(
+		http.MethodGet, "https://example.com/xml", respond("application/xml; charset=utf-8", []byte(`<hello>world</hello>`)),
+	)
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/json", respond("application/json", []byte(`{"hello":"world"}`)))
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/list", respond("application/json", []byte(`["hello","world"]`)))
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/binary", respond("application/octet-stream", binary))
+
+	tests := []struct {
+		Name     string
+		Endpoint string
+		Format   string
+		Expected any
+		Error    string
+	}{
+		{
+			Name:     "Auto parses a JSON object",
+			Endpoint: "https://example.com/json",
+			Format:   "auto",
+			Expected: map[string]any{"hello": "world"},
+		},
+		{
+			Name:     "Auto leaves a JSON array as a string",
+			Endpoint: "https://example.com/list",
+			Format:   "auto",
+			Expected: `["hello","world"]`,
+		},
+		{
+			Name:     "JSON parses an array",
+			Endpoint: "https://example.com/list",
+			Format:   "json",
+			Expected: []any{"hello", "world"},
+		},
+		{
+			Name:     "JSON fails on XML",
+			Endpoint: "https://example.com/xml",
+			Format:   "json",
+			Error:    "CallHTTP response body is not valid JSON",
+		},
+		{
+			Name:     "Text leaves JSON as a string",
+			Endpoint: "https://example.com/json",
+			Format:   "text",
+			Expected: `{"hello":"world"}`,
+		},
+		{
+			Name:     "Text returns XML",
+			Endpoint: "https://example.com/xml",
+			Format:   "text",
+			Expected: `<hello>world</hello>`,
+		},
+		{
+			Name:     "Bytes base64 encodes the body",
+			Endpoint: "https://example.com/binary",
+			Format:   "bytes",
+			Expected: base64.StdEncoding.EncodeToString(binary),
+		},
+		{
+			Name:     "Content type returns XML as text",
+			Endpoint: "https://example.com/xml",
+			Format:   "contentType",
+			Expected: `<hello>world</hello>`,
+		},
+		{
+			Name:     "Content type parses JSON",
+			Endpoint: "https://example.com/list",
+			Format:   "contentType",
+			Expected: []any{"hello", "world"},
+		},
+		{
+			Name:     "Content type base64 encodes binary",
+			Endpoint: "https://example.com/binary",
+			Format:   "contentType",
+			Expected: base64.StdEncoding.EncodeToString(binary),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        outputFormat: %s
+      export:
+        as: response
+      call: http
+      with:
+        method: get
+        endpoint: %s`, test.Format, test.Endpoint))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Error != "" {
+				assert.ErrorContains(t, env.GetWorkflowError(), test.Error)
+				return
+			}
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result["response"])
+		})
+	}
+}
+
+func TestCallHTTPOutputFormatValidation(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        outputFormat: xml
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com`)
+
+	builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "invalid output format metadata for task get: output format must be")
+}
+
+func TestCallHTTPIdempotencyKey(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var keys map[string][]string
+	record := func(name string, failures int) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			keys[name] = append(keys[name], req.Header.Get("Idempotency-Key")+req.Header.Get("X-Request-Id"))
+			if len(keys[name]) <= failures {
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, ""), nil
+		}
+	}
+
+	tests := []struct {
+		Name     string
+		Metadata string
+		Headers  string
+		Assert   func(t *testing.T, keys map[string][]string)
+	}{
+		{
+			Name: "Stable across retries and unique per task",
+			Assert: func(t *testing.T, keys map[string][]string) {
+				assert.Len(t, keys["first"], 2)
+				assert.NotEmpty(t, keys["first"][0])
+				assert.Equal(t, keys["first"][0], keys["first"][1])
+
+				assert.Len(t, keys["second"], 1)
+				assert.NotEqual(t, keys["first"][0], keys["second"][0])
+			},
+		},
+		{
+			Name:     "Custom header",
+			Metadata: "idempotencyHeader: X-Request-Id",
+			Assert: func(t *testing.T, keys map[string][]string) {
+				assert.Len(t, keys["first"], 2)
+				assert.NotEmpty(t, keys["first"][0])
+				assert.Equal(t, keys["first"][0], keys["first"][1])
+			},
+		},
+		{
+			Name:     "Disabled",
+			Metadata: `idempotencyHeader: ""`,
+			Assert: func(t *testing.T, keys map[string][]string) {
+				assert.Equal(t, []string{"", ""}, keys["first"])
+			},
+		},
+		{
+			Name:    "Header set by task",
+			Headers: "idempotency-key: my-key",
+			Assert: func(t *testing.T, keys map[string][]string) {
+				assert.Equal(t, []string{"my-key", "my-key"}, keys["first"])
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			keys = map[string][]string{}
+			httpmock.RegisterResponder(http.MethodGet, "https://example.com/first", record("first", 1))
+			httpmock.RegisterResponder(http.MethodGet, "https://example.com/second", record("second", 0))
+
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - first:
+      metadata:
+        %s
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/first
+        headers:
+          %s
+  - second:
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/second`, test.Metadata, test.Headers))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			test.Assert(t, keys)
+		})
+	}
+}
+
+func TestCallHTTPRetryOn(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tests := []struct {
+		Name     string
+		Status   int
+		Metadata string
+		Calls    int
+		Error    string
+	}{
+		{
+			Name:   "5xx retried by default",
+			Status: http.StatusServiceUnavailable,
+			Calls:  2,
+		},
+		{
+			Name:   "4xx not retried by default",
+			Status: http.StatusTooManyRequests,
+			Calls:  1,
+			Error:  "CallHTTP returned 4xx status code",
+		},
+		{
+			Name:     "Listed 4xx retried",
+			Status:   http.StatusTooManyRequests,
+			Metadata: "retryOn: [429, 503]",
+			Calls:    2,
+		},
+		{
+			Name:     "Unlisted 5xx not retried",
+			Status:   http.StatusInternalServerError,
+			Metadata: "retryOn: [429, 503]",
+			Calls:    1,
+			Error:    "CallHTTP returned 5xx error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			calls := 0
+			httpmock.RegisterResponder(http.MethodGet, "https://example.com/retry", func(*http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					resp := httpmock.NewStringResponse(test.Status, "")
+					resp.Header.Set("Retry-After", "2")
+					return resp, nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, "done"), nil
+			})
+
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        %s
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/retry`, test.Metadata))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.Equal(t, test.Calls, calls)
+
+			if test.Error != "" {
+				assert.ErrorContains(t, env.GetWorkflowError(), test.Error)
+				return
+			}
+			assert.NoError(t, env.GetWorkflowError())
+		})
+	}
+}
+
+func TestCallHTTPRetryOnValidation(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - get:
+      metadata:
+        retryOn: [200]
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com`)
+
+	builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "invalid retry on metadata for task get: retry on status code 200 must be between 300 and 599")
+}
+
+func TestCallHTTPRetryAfter(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Status    int
+		RetryOn   []int
+		Retryable bool
+		Delay     time.Duration
+	}{
+		{
+			Name:      "Listed 429",
+			Status:    http.StatusTooManyRequests,
+			RetryOn:   []int{http.StatusTooManyRequests},
+			Retryable: true,
+			Delay:     2 * time.Minute,
+		},
+		{
+			Name:      "5xx by default",
+			Status:    http.StatusServiceUnavailable,
+			Retryable: true,
+			Delay:     2 * time.Minute,
+		},
+		{
+			Name:   "Ignored if not retryable",
+			Status: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp := httpmock.NewStringResponse(test.Status, "")
+			resp.Header.Set("Retry-After", "120")
+
+			err := checkHTTPStatus(log.NewStructuredLogger(slog.New(slog.DiscardHandler)), false, test.RetryOn, resp, nil)
+
+			var appErr *temporal.ApplicationError
+			assert.ErrorAs(t, err, &appErr)
+			assert.Equal(t, !test.Retryable, appErr.NonRetryable())
+			assert.Equal(t, test.Delay, appErr.NextRetryDelay())
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name   string
+		Header string
+		Delay  time.Duration
+	}{
+		{
+			Name: "Not set",
+		},
+		{
+			Name:   "Seconds",
+			Header: "120",
+			Delay:  2 * time.Minute,
+		},
+		{
+			Name:   "Date",
+			Header: now.Add(30 * time.Second).Format(http.TimeFormat),
+			Delay:  30 * time.Second,
+		},
+		{
+			Name:   "Date in the past",
+			Header: now.Add(-time.Minute).Format(http.TimeFormat),
+		},
+		{
+			Name:   "Negative seconds",
+			Header: "-1",
+		},
+		{
+			Name:   "Invalid",
+			Header: "soon",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Delay, retryAfter(test.Header, now))
+		})
+	}
+}
+
+func TestCallHTTPTextBody(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, "https://example.com/graphql", func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+			"body":        string(body),
+			"contentType": req.Header.Get("Content-Type"),
+		})
+	})
+
+	tests := []struct {
+		Name                string
+		Headers             string
+		Body                string
+		ExpectedBody        string
+		ExpectedContentType string
+	}{
+		{
+			Name:                "Templated GraphQL query",
+			Headers:             "content-type: application/graphql",
+			Body:                `'${ "query { user(id: \"" + .input.id + "\") { name } }" }'`,
+			ExpectedBody:        `query { user(id: "abc123") { name } }`,
+			ExpectedContentType: "application/graphql",
+		},
+		{
+			Name:                "Text defaults to plain text",
+			Headers:             "{}",
+			Body:                "<user><id>abc123</id></user>",
+			ExpectedBody:        "<user><id>abc123</id></user>",
+			ExpectedContentType: "text/plain; charset=utf-8",
+		},
+		{
+			Name:                "Object is sent as JSON",
+			Headers:             "content-type: application/json",
+			Body:                "{ query: '${ .input.id }' }",
+			ExpectedBody:        `{"query":"abc123"}`,
+			ExpectedContentType: "application/json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+do:
+  - post:
+      export:
+        as: response
+      call: http
+      with:
+        method: post
+        endpoint: https://example.com/graphql
+        headers:
+          %s
+        body: %s`, test.Headers, test.Body))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{"id": "abc123"}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.ExpectedBody, result["response"]["body"])
+			assert.Equal(t, test.ExpectedContentType, result["response"]["contentType"])
+		})
+	}
+}
+
+func TestCallHTTPAuthentication(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var authorization string
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/secure", func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return httpmock.NewStringResponse(http.StatusOK, ""), nil
+	})
+
+	tests := []struct {
+		Name           string
+		Authentication string
+		Expected       string
+	}{
+		{
+			Name: "Inline basic",
+			Authentication: `
+            basic:
+              username: ziggy
+              password: ${ .input.password }`,
+			Expected: "Basic emlnZ3k6c2VjcmV0",
+		},
+		{
+			Name: "Inline bearer",
+			Authentication: `
+            bearer:
+              token: ${ .input.password }`,
+			Expected: "Bearer secret",
+		},
+		{
+			Name: "Referenced",
+			Authentication: `
+            use: api`,
+			Expected: "Bearer secret-api",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			authorization = ""
+
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+use:
+  authentications:
+    api:
+      bearer:
+        token: ${ .input.password + "-api" }
+do:
+  - get:
+      call: http
+      with:
+        method: get
+        endpoint:
+          uri: https://example.com/secure
+          authentication:`+test.Authentication)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{"password": "secret"}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+			assert.Equal(t, test.Expected, authorization)
+
+			// A reference is resolved for the task, leaving the document unchanged
+			auth := (*doc.Do)[0].AsCallHTTPTask().With.Endpoint.EndpointConfig.Authentication
+			assert.Equal(t, auth.Use != nil, auth.AuthenticationPolicy == nil)
+		})
+	}
+}
+
+func TestCallHTTPAuthenticationValidation(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Authentication string
+		Error          string
+	}{
+		{
+			Name: "Known reference",
+			Authentication: `
+            use: api`,
+		},
+		{
+			Name: "Unknown reference",
+			Authentication: `
+            use: missing`,
+			Error: "unknown authentication missing for task get",
+		},
+		{
+			Name: "Unsupported policy",
+			Authentication: `
+            digest:
+              username: ziggy
+              password: secret`,
+			Error: "invalid authentication for task get: only basic and bearer authentication are supported",
+		},
+		{
+			Name: "Secret",
+			Authentication: `
+            bearer:
+              use: mySecret`,
+			Error: "invalid authentication for task get: bearer authentication secrets aren't supported",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: http
+  version: 0.0.1
+use:
+  authentications:
+    api:
+      bearer:
+        token: secret
+do:
+  - get:
+      call: http
+      with:
+        method: get
+        endpoint:
+          uri: https://example.com/secure
+          authentication:`+test.Authentication)
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			if test.Error != "" {
+				assert.ErrorContains(t, builder.PostLoad(), test.Error)
+				return
+			}
+			assert.NoError(t, builder.PostLoad())
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// CallNexus is the call type of a task that runs a Nexus operation
+const CallNexus = "nexus"
+
+// nexusCall is the "with" of a Nexus call task. The endpoint is the name of
+// the Nexus endpoint registered in Temporal, which routes the operation to
+// the service's handler.
+type nexusCall struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Service   string `mapstructure:"service"`
+	Operation string `mapstructure:"operation"`
+	Input     any    `mapstructure:"input"`
+}
+
+func NewCallNexusTaskBuilder(
+	temporalWorker worker.Worker,
+	task *model.CallFunction,
+	taskName string,
+	doc *model.Workflow,
+) (*CallNexusTaskBuilder, error) {
+	return &CallNexusTaskBuilder{
+		builder: builder[*model.CallFunction]{
+			doc:            doc,
+			name:           taskName,
+			task:           task,
+			temporalWorker: temporalWorker,
+		},
+	}, nil
+}
+
+type CallNexusTaskBuilder struct {
+	builder[*model.CallFunction]
+}
+
+func (t *CallNexusTaskBuilder) PostLoad() error {
+	if _, err := t.nexusCall(); err != nil {
+		return err
+	}
+
+	if _, err := t.timeout(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *CallNexusTaskBuilder) Build() (TemporalWorkflowFunc, error) {
+	call, err := t.nexusCall()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := t.timeout()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
+		logger := workflow.GetLogger(ctx)
+		logger.Debug("Calling Nexus operation",
+			"name", t.name, "endpoint", call.Endpoint, "service", call.Service, "operation", call.Operation,
+		)
+
+		opInput, err := t.mapInput(ctx, call.Input, state)
+		if err != nil {
+			logger.Error("Error mapping Nexus operation input", "name", t.name, "error", err)
+			return nil, err
+		}
+
+		client := workflow.NewNexusClient(call.Endpoint, call.Service)
+		future := client.ExecuteOperation(ctx, call.Operation, opInput, workflow.NexusOperationOptions{
+			ScheduleToCloseTimeout: timeout,
+			// Use the same summary as an activity
+			Summary: workflow.GetActivityOptions(ctx).Summary,
+		})
+
+		var res any
+		if err := future.Get(ctx, &res); err != nil {
+			if temporal.IsCanceledError(err) {
+				return nil, nil
+			}
+
+			logger.Error("Error calling Nexus operation", "name", t.name, "error", err)
+			return nil, fmt.Errorf("error calling nexus operation: %w", err)
+		}
+
+		return res, nil
+	}, nil
+}
+
+// nexusCall parses and validates the task's "with"
+func (t *CallNexusTaskBuilder) nexusCall() (*nexusCall, error) {
+	var call nexusCall
+	if err := mapstructure.Decode(t.task.With, &call); err != nil {
+		return nil, fmt.Errorf("invalid nexus call for task %s: %w", t.GetTaskName(), err)
+	}
+
+	for _, field := range []struct{ key, value string }{
+		{"endpoint", call.Endpoint},
+		{"service", call.Service},
+		{"operation", call.Operation},
+	} {
+		if field.value == "" {
+			return nil, fmt.Errorf("nexus call for task %s must set the %s", t.GetTaskName(), field.key)
+		}
+	}
+
+	return &call, nil
+}
+
+// timeout returns how long the operation can run for, including any retries.
+// If not set, the server's maximum is used.
+func (t *CallNexusTaskBuilder) timeout() (time.Duration, error) {
+	timeout, err := metadata.GetTimeout(t.task.Metadata, 0)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout metadata for task %s: %w", t.GetTaskName(), err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("timeout for nexus call task %s must not be negative", t.GetTaskName())
+	}
+
+	return timeout, nil
+}
+
+// mapInput interpolates the operation's input against the state. This is
+// evaluated as a side effect so it's deterministic. The input is wrapped in an
+// object so it can be of any type.
+func (t *CallNexusTaskBuilder) mapInput(ctx workflow.Context, input any, state *utils.State) (any, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	res, err := utils.TraverseAndEvaluateObj(
+		model.NewObjectOrRuntimeExpr(swUtil.DeepClone(map[string]any{"input": input})),
+		state,
+		func(fn func() (any, error)) (any, error) {
+			return t.sideEffectWrapper(ctx, fn)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing nexus operation input: %w", err)
+	}
+
+	return res["input"], nil
+}
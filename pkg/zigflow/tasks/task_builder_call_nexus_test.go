@@ -0,0 +1,178 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nexus-rpc/sdk-go/nexus"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallNexus(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: nexus
+  version: 0.0.1
+do:
+  - greet:
+      export:
+        as: greeting
+      call: nexus
+      with:
+        endpoint: greeting-endpoint
+        service: greeting
+        operation: say-hello
+        input:
+          name: ${ .input.name }`)
+	env := newTestEnvironment(t, doc)
+
+	service := nexus.NewService("greeting")
+	assert.NoError(t, service.Register(nexus.NewSyncOperation("say-hello",
+		func(_ context.Context, input map[string]any, _ nexus.StartOperationOptions) (map[string]any, error) {
+			return map[string]any{
+				"message": fmt.Sprintf("Hello %s", input["name"]),
+			}, nil
+		},
+	)))
+	env.RegisterNexusService(service)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"name": "Ziggy"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{"message": "Hello Ziggy"}, result["greeting"])
+}
+
+func TestCallNexusError(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: nexus
+  version: 0.0.1
+do:
+  - greet:
+      call: nexus
+      with:
+        endpoint: greeting-endpoint
+        service: greeting
+        operation: say-hello`)
+	env := newTestEnvironment(t, doc)
+
+	service := nexus.NewService("greeting")
+	assert.NoError(t, service.Register(nexus.NewSyncOperation("say-hello",
+		func(context.Context, any, nexus.StartOperationOptions) (any, error) {
+			return nil, nexus.HandlerErrorf(nexus.HandlerErrorTypeBadRequest, "no name given")
+		},
+	)))
+	env.RegisterNexusService(service)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.ErrorContains(t, env.GetWorkflowError(), "error calling nexus operation")
+}
+
+func TestCallNexusValidation(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Task  string
+		Error string
+	}{
+		{
+			Name: "valid",
+			Task: `call: nexus
+      with:
+        endpoint: greeting-endpoint
+        service: greeting
+        operation: say-hello`,
+		},
+		{
+			Name: "missing endpoint",
+			Task: `call: nexus
+      with:
+        service: greeting
+        operation: say-hello`,
+			Error: "nexus call for task greet must set the endpoint",
+		},
+		{
+			Name: "missing operation",
+			Task: `call: nexus
+      with:
+        endpoint: greeting-endpoint
+        service: greeting`,
+			Error: "nexus call for task greet must set the operation",
+		},
+		{
+			Name: "invalid with",
+			Task: `call: nexus
+      with:
+        endpoint: greeting-endpoint
+        service:
+          name: greeting
+        operation: say-hello`,
+			Error: "invalid nexus call for task greet",
+		},
+		{
+			Name: "negative timeout",
+			Task: `call: nexus
+      metadata:
+        timeout: -1s
+      with:
+        endpoint: greeting-endpoint
+        service: greeting
+        operation: say-hello`,
+			Error: "timeout for nexus call task greet must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: nexus
+  version: 0.0.1
+do:
+  - greet:
+      `+test.Task)
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			err = builder.PostLoad()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.Error)
+		})
+	}
+}
+
+func TestUnsupportedCallType(t *testing.T) {
+	_, err := NewTaskBuilder("greet", &model.CallFunction{Call: "unknown"}, nil, nil)
+
+	assert.EqualError(t, err, "unsupported call type 'unknown' for task 'greet'")
+}
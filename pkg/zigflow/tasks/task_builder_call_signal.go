@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// CallSignal is the call type of a task that sends a signal to another workflow
+const CallSignal = "signal"
+
+// signalCall is the "with" of a signal call task. The workflow and run IDs can
+// be runtime expressions. If the run ID isn't set, the workflow's current run
+// is signalled.
+type signalCall struct {
+	WorkflowID string `mapstructure:"workflowId"`
+	RunID      string `mapstructure:"runId"`
+	Signal     string `mapstructure:"signal"`
+	Input      any    `mapstructure:"input"`
+	Await      *bool  `mapstructure:"await"`
+}
+
+func NewCallSignalTaskBuilder(
+	temporalWorker worker.Worker,
+	task *model.CallFunction,
+	taskName string,
+	doc *model.Workflow,
+) (*CallSignalTaskBuilder, error) {
+	return &CallSignalTaskBuilder{
+		builder: builder[*model.CallFunction]{
+			doc:            doc,
+			name:           taskName,
+			task:           task,
+			temporalWorker: temporalWorker,
+		},
+	}, nil
+}
+
+type CallSignalTaskBuilder struct {
+	builder[*model.CallFunction]
+}
+
+func (t *CallSignalTaskBuilder) PostLoad() error {
+	_, err := t.signalCall()
+	return err
+}
+
+func (t *CallSignalTaskBuilder) Build() (TemporalWorkflowFunc, error) {
+	call, err := t.signalCall()
+	if err != nil {
+		return nil, err
+	}
+
+	// Default to waiting for the signal to be delivered
+	await := call.Await == nil || *call.Await
+
+	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
+		logger := workflow.GetLogger(ctx)
+
+		target, err := t.mapTarget(ctx, call, state)
+		if err != nil {
+			logger.Error("Error mapping signal target", "name", t.name, "error", err)
+			return nil, err
+		}
+
+		logger.Debug("Sending signal to workflow",
+			"name", t.name, "workflowId", target.WorkflowID, "runId", target.RunID, "signal", call.Signal, "await", await,
+		)
+
+		future := workflow.SignalExternalWorkflow(ctx, target.WorkflowID, target.RunID, call.Signal, target.Input)
+		if !await {
+			logger.Debug("Not waiting for signal to be delivered", "name", t.name)
+			return nil, nil
+		}
+
+		if err := future.Get(ctx, nil); err != nil {
+			if temporal.IsCanceledError(err) {
+				return nil, nil
+			}
+
+			var notFound *temporal.UnknownExternalWorkflowExecutionError
+			if errors.As(err, &notFound) {
+				logger.Error("Workflow to signal not found", "name", t.name, "workflowId", target.WorkflowID)
+				return nil, newNotFoundError(fmt.Sprintf("Workflow %s not found", target.WorkflowID), err, t.GetTaskName())
+			}
+
+			logger.Error("Error signalling workflow", "name", t.name, "error", err)
+			return nil, fmt.Errorf("error signalling workflow: %w", err)
+		}
+
+		return nil, nil
+	}, nil
+}
+
+// signalCall parses and validates the task's "with"
+func (t *CallSignalTaskBuilder) signalCall() (*signalCall, error) {
+	var call signalCall
+	if err := mapstructure.Decode(t.task.With, &call); err != nil {
+		return nil, fmt.Errorf("invalid signal call for task %s: %w", t.GetTaskName(), err)
+	}
+
+	for _, field := range []struct{ key, value string }{
+		{"workflowId", call.WorkflowID},
+		{"signal", call.Signal},
+	} {
+		if field.value == "" {
+			return nil, fmt.Errorf("signal call for task %s must set the %s", t.GetTaskName(), field.key)
+		}
+	}
+
+	return &call, nil
+}
+
+// mapTarget interpolates the workflow ID, run ID and input against the state.
+// This is evaluated as a side effect so it's deterministic. The result is a
+// copy, so the task's "with" is unchanged.
+func (t *CallSignalTaskBuilder) mapTarget(ctx workflow.Context, call *signalCall, state *utils.State) (*signalCall, error) {
+	res, err := utils.TraverseAndEvaluateObj(
+		model.NewObjectOrRuntimeExpr(swUtil.DeepClone(map[string]any{
+			"workflowId": call.WorkflowID,
+			"runId":      call.RunID,
+			"input":      call.Input,
+		})),
+		state,
+		func(fn func() (any, error)) (any, error) {
+			return t.sideEffectWrapper(ctx, fn)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signal call: %w", err)
+	}
+
+	workflowID, ok := res["workflowId"].(string)
+	if !ok || workflowID == "" {
+		return nil, newValidationError("Workflow id to signal must be a non-empty string", nil, t.GetTaskName())
+	}
+	runID, ok := res["runId"].(string)
+	if !ok {
+		return nil, newValidationError("Run id to signal must be a string", nil, t.GetTaskName())
+	}
+
+	return &signalCall{
+		WorkflowID: workflowID,
+		RunID:      runID,
+		Signal:     call.Signal,
+		Input:      res["input"],
+	}, nil
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestCallSignal(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Await   string
+		Awaited bool
+	}{
+		{
+			Name:    "Await delivery",
+			Awaited: true,
+		},
+		{
+			Name: "Don't await delivery",
+			Await: `
+        await: false`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: signal
+  version: 0.0.1
+do:
+  - approve:
+      call: signal
+      with:
+        workflowId: ${ "order-" + .input.orderId }
+        signal: approve
+        input:
+          approver: ${ .input.user }
+          items:
+            - ${ .input.orderId }`+test.Await)
+			env := newTestEnvironment(t, doc)
+
+			call := env.OnSignalExternalWorkflow(mock.Anything, "order-123", "", "approve", map[string]any{
+				"approver": "ziggy",
+				"items":    []any{"123"},
+			}).Return(nil)
+			if !test.Awaited {
+				// The workflow can complete before the signal is sent
+				call.Maybe()
+			}
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{"orderId": "123", "user": "ziggy"}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+			env.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCallSignalNotFound(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: signal
+  version: 0.0.1
+do:
+  - approve:
+      call: signal
+      with:
+        workflowId: missing
+        runId: some-run
+        signal: approve`)
+	env := newTestEnvironment(t, doc)
+
+	env.OnSignalExternalWorkflow(mock.Anything, "missing", "some-run", "approve", nil).
+		Return(&temporal.UnknownExternalWorkflowExecutionError{})
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, ErrorTypeNotFound, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+
+	var details ErrorEnvelope
+	assert.NoError(t, appErr.Details(&details))
+	assert.Equal(t, model.ErrorTypeCommunication, details.Type)
+	assert.Equal(t, 404, details.Status)
+	assert.Equal(t, "approve", details.Instance)
+}
+
+func TestCallSignalValidation(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Task  string
+		Error string
+	}{
+		{
+			Name: "valid",
+			Task: `call: signal
+      with:
+        workflowId: order-123
+        signal: approve`,
+		},
+		{
+			Name: "missing workflow id",
+			Task: `call: signal
+      with:
+        signal: approve`,
+			Error: "signal call for task approve must set the workflowId",
+		},
+		{
+			Name: "missing signal",
+			Task: `call: signal
+      with:
+        workflowId: order-123`,
+			Error: "signal call for task approve must set the signal",
+		},
+		{
+			Name: "invalid await",
+			Task: `call: signal
+      with:
+        workflowId: order-123
+        signal: approve
+        await: later`,
+			Error: "invalid signal call for task approve",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: signal
+  version: 0.0.1
+do:
+  - approve:
+      `+test.Task)
+
+			builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+
+			err = builder.PostLoad()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.Error)
+		})
+	}
+}
@@ -19,19 +19,58 @@ package tasks
 import (
 	"fmt"
 
+	"github.com/mrsimonemms/zigflow/pkg/tracing"
 	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
 	"github.com/rs/zerolog/log"
 	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.opentelemetry.io/otel/attribute"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
 )
 
+// Result envelope statuses
+const (
+	ResultEnvelopeStatusSuccess = "success"
+	ResultEnvelopeStatusFailure = "failure"
+)
+
+// ResultEnvelope is returned to the caller in place of the raw output or
+// error when the document sets the resultEnvelope metadata. This gives the
+// caller a consistent shape regardless of the result.
+type ResultEnvelope struct {
+	Status string         `json:"status"`
+	Output any            `json:"output,omitempty"`
+	Error  map[string]any `json:"error,omitempty"`
+}
+
+func newResultEnvelope(output any, err error) ResultEnvelope {
+	if err != nil {
+		return ResultEnvelope{
+			Status: ResultEnvelopeStatusFailure,
+			Error:  newCaughtError(err),
+		}
+	}
+
+	return ResultEnvelope{
+		Status: ResultEnvelopeStatusSuccess,
+		Output: output,
+	}
+}
+
 type DoTaskOpts struct {
+	// The worker's default activity options, which are used by every task
+	ActivityDefaults        ActivityDefaults
 	DisableRegisterWorkflow bool
 	Envvars                 map[string]any
-	Validator               *utils.Validator
+	// Register the workflow even if all its tasks are do tasks
+	ForceRegisterWorkflow bool
+	// The names of the envvars and data keys whose values are masked in the
+	// logs, the HTTP calls' responses and the state query
+	Secrets   []string
+	Validator *utils.Validator
 }
 
 func NewDoTaskBuilder(
@@ -51,6 +90,10 @@ func NewDoTaskBuilder(
 
 	return &DoTaskBuilder{
 		builder: builder[*model.DoTask]{
+			inheritedOptions: inheritedOptions{
+				activityDefaults: doOpts.ActivityDefaults,
+				secrets:          doOpts.Secrets,
+			},
 			doc:            doc,
 			name:           taskName,
 			task:           task,
@@ -62,7 +105,11 @@ func NewDoTaskBuilder(
 
 type DoTaskBuilder struct {
 	builder[*model.DoTask]
-	opts DoTaskOpts
+	description string
+	onFailure   *onFailureHook
+	opts        DoTaskOpts
+	parallel    bool
+	retryPolicy *temporal.RetryPolicy
 }
 
 type workflowFunc struct {
@@ -74,6 +121,7 @@ type workflowFunc struct {
 
 func (t *DoTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 	tasks := make([]workflowFunc, 0)
+	recorder, _ := t.temporalWorker.(BuildRecorder)
 
 	var hasNoDo bool
 	for _, task := range *t.task.Do {
@@ -84,18 +132,40 @@ func (t *DoTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 			hasNoDo = true
 		}
 
+		if err := validateTaskVersion(task.Key, task.Task); err != nil {
+			return nil, err
+		}
+
+		if err := validateTaskPriority(task.Key, task.Task); err != nil {
+			return nil, err
+		}
+
+		if err := validateTaskActivityOptions(task.Key, task.Task); err != nil {
+			return nil, err
+		}
+
+		if err := validateTaskSummary(task.Key, task.Task); err != nil {
+			return nil, err
+		}
+
 		// Build a task builder
 		l.Debug().Msg("Creating task builder")
-		builder, err := NewTaskBuilder(task.Key, task.Task, t.temporalWorker, t.doc)
+		builder, err := t.newTaskBuilder(task.Key, task.Task)
 		if err != nil {
-			return nil, fmt.Errorf("error creating task builder: %w", err)
+			return nil, fmt.Errorf("error creating task builder for task %s: %w", task.Key, err)
 		}
 
 		// Build the task and store it for use
 		l.Debug().Msg("Building task")
+		if recorder != nil {
+			recorder.BeginTask(t.GetTaskName(), task.Key, task.Task)
+		}
 		fn, err := builder.Build()
+		if recorder != nil {
+			recorder.EndTask()
+		}
 		if err != nil {
-			return nil, fmt.Errorf("error building task: %w", err)
+			return nil, fmt.Errorf("error building task %s: %w", task.Key, err)
 		}
 		if fn != nil {
 			tasks = append(tasks, workflowFunc{
@@ -107,27 +177,94 @@ func (t *DoTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 	}
 
 	// Execute the workflow
-	wf := t.workflowExecutor(tasks)
+	wf, err := t.wrapWorkflow(t.workflowExecutor(tasks))
+	if err != nil {
+		return nil, err
+	}
 
-	if !t.opts.DisableRegisterWorkflow {
-		if hasNoDo {
-			log.Debug().Str("name", t.GetTaskName()).Msg("Registering workflow")
-			t.temporalWorker.RegisterWorkflowWithOptions(wf, workflow.RegisterOptions{
-				Name: t.GetTaskName(),
-			})
-		}
+	if t.shouldRegisterWorkflow(hasNoDo) {
+		registerWorkflow(t.temporalWorker, t.GetTaskName(), wf)
+	}
+
+	return wf, nil
+}
+
+// shouldRegisterWorkflow returns whether the workflow is registered. By
+// default, a do task whose tasks are all do tasks isn't, as each of its tasks
+// is registered instead.
+func (t *DoTaskBuilder) shouldRegisterWorkflow(hasNoDo bool) bool {
+	if t.opts.DisableRegisterWorkflow {
+		return false
+	}
+
+	return hasNoDo || t.opts.ForceRegisterWorkflow
+}
+
+// registerWorkflow registers the workflow with the worker, so it can be
+// started by name
+func registerWorkflow(temporalWorker worker.Worker, name string, wf TemporalWorkflowFunc) {
+	log.Debug().Str("name", name).Msg("Registering workflow")
+	temporalWorker.RegisterWorkflowWithOptions(wf, workflow.RegisterOptions{
+		Name: name,
+	})
+	registeredWorkflows.Store(name, true)
+}
+
+// wrapWorkflow adds the cancel signal and on failure hook to the workflow and
+// sets how the tasks are run
+func (t *DoTaskBuilder) wrapWorkflow(wf TemporalWorkflowFunc) (TemporalWorkflowFunc, error) {
+	description, err := t.workflowDescription()
+	if err != nil {
+		return nil, err
+	}
+	t.description = description
+
+	signal, err := metadata.GetCancelSignal(t.task.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cancel signal metadata for task %s: %w", t.GetTaskName(), err)
+	}
+	if signal != "" {
+		wf = cancelOnSignal(signal, wf)
+	}
+
+	if t.parallel, err = t.isParallel(); err != nil {
+		return nil, err
+	}
+
+	if t.retryPolicy, err = t.activityRetryPolicy(); err != nil {
+		return nil, err
+	}
+
+	if t.onFailure, err = t.buildOnFailure(); err != nil {
+		return nil, err
 	}
 
 	return wf, nil
 }
 
 func (t *DoTaskBuilder) PostLoad() error {
+	if _, err := t.workflowDescription(); err != nil {
+		return err
+	}
+
+	if _, err := t.continueAsNewAfter(); err != nil {
+		return err
+	}
+
+	if _, err := metadata.GetCancelSignal(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid cancel signal metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	if _, err := t.isParallel(); err != nil {
+		return err
+	}
+
 	for _, task := range *t.task.Do {
 		l := log.With().Str("task", task.Key).Logger()
 
 		// Build a task builder
 		l.Debug().Msg("Creating prep task builder")
-		builder, err := NewTaskBuilder(task.Key, task.Task, t.temporalWorker, t.doc)
+		builder, err := t.newTaskBuilder(task.Key, task.Task)
 		if err != nil {
 			return fmt.Errorf("error creating task prep builder: %w", err)
 		}
@@ -147,58 +284,465 @@ func (t *DoTaskBuilder) validateInput(ctx workflow.Context, inputDef *model.Inpu
 
 	if inputDef != nil {
 		logger.Debug("Validating input against schema")
-		if err := swUtil.ValidateSchema(state.Input, inputDef.Schema, t.GetTaskName()); err != nil {
+		if err := utils.ValidateSchema(state.Input, inputDef.Schema, t.GetTaskName()); err != nil {
 			logger.Error("Input failed data validation", "error", err)
 
-			return temporal.NewNonRetryableApplicationError(
-				"Workflow input did not meet JSON schema specification",
-				"Validation",
-				err,
-				// There is additional detail useful in here
-				err.(*model.Error),
-			)
+			// The envelope lists the fields which failed validation
+			return newValidationError("Workflow input did not meet JSON schema specification", err, t.GetTaskName())
 		}
 	}
 
 	return nil
 }
 
+// validateOutput validates the workflow's result if there's a schema, so the
+// caller never receives a result which breaks the workflow's contract
+func (t *DoTaskBuilder) validateOutput(ctx workflow.Context, outputDef *model.Output, output any) error {
+	if outputDef == nil {
+		return nil
+	}
+
+	if err := utils.ValidateSchema(output, outputDef.Schema, t.GetTaskName()); err != nil {
+		workflow.GetLogger(ctx).Error("Output failed data validation", "error", err)
+
+		// The envelope lists the fields which failed validation
+		return newValidationError("Workflow output did not meet JSON schema specification", err, t.GetTaskName())
+	}
+
+	return nil
+}
+
 // workflowExecutor executes the workflow by iterating through the tasks in order
 func (t *DoTaskBuilder) workflowExecutor(tasks []workflowFunc) TemporalWorkflowFunc {
 	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
-		logger := workflow.GetLogger(ctx)
-		logger.Info("Running workflow", "workflow", t.GetTaskName())
-
-		if state == nil {
-			logger.Debug("Creating new state instance")
-			state = utils.NewState().AddWorkflowInfo(ctx)
-			state.Env = t.opts.Envvars
-			state.Input = input
-
-			// Validate input for the whole document
-			logger.Debug("Validating input against document")
-			if err := t.validateInput(ctx, t.doc.Input, state); err != nil {
-				logger.Debug("Document input validation error", "error", err)
+		// Only workflows started by a caller receive no state - child workflows
+		// always receive the parent's state. A top-level workflow that has been
+		// continued-as-new receives its previous state, but is still the caller's
+		info := workflow.GetInfo(ctx)
+		isCaller := state == nil || (info.ContinuedExecutionRunID != "" && info.ParentWorkflowExecution == nil)
+
+		// The description is shown in the Temporal UI whenever no task has
+		// its own details
+		if isCaller && t.description != "" {
+			workflow.SetCurrentDetails(ctx, t.description)
+		}
+
+		output, err := t.runWorkflow(ctx, tasks, input, state)
+
+		if isCaller && t.useResultEnvelope() && !temporal.IsCanceledError(err) && !workflow.IsContinueAsNewError(err) {
+			workflow.GetLogger(ctx).Debug("Wrapping workflow result in envelope")
+			return newResultEnvelope(output, err), nil
+		}
+
+		return output, err
+	}
+}
+
+// runWorkflow runs the tasks, creating the state if this is a new workflow
+func (t *DoTaskBuilder) runWorkflow(ctx workflow.Context, tasks []workflowFunc, input any, state *utils.State) (any, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Running workflow", "workflow", t.GetTaskName())
+
+	if state == nil {
+		logger.Debug("Creating new state instance")
+		state = utils.NewState().AddWorkflowInfo(ctx)
+		state.Env = t.opts.Envvars
+		state.Input = input
+
+		// Transform the input for the whole document
+		if t.doc.Input != nil && t.doc.Input.From != nil {
+			logger.Debug("Transforming document input")
+			transformed, err := t.evaluateTransform(ctx, t.doc.Input.From, state)
+			if err != nil {
+				logger.Debug("Document input transformation error", "error", err)
 				return nil, err
 			}
+			state.Input = transformed
+			input = transformed
 		}
 
-		timeout := defaultWorkflowTimeout
-		if t.doc.Timeout != nil && t.doc.Timeout.Timeout != nil && t.doc.Timeout.Timeout.After != nil {
-			timeout = utils.ToDuration(t.doc.Timeout.Timeout.After)
+		// Validate input for the whole document
+		logger.Debug("Validating input against document")
+		if err := t.validateInput(ctx, t.doc.Input, state); err != nil {
+			logger.Debug("Document input validation error", "error", err)
+			return nil, err
 		}
-		logger.Debug("Setting activity options", "startToCloseTimeout", timeout)
-		ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
-			StartToCloseTimeout: timeout,
-		})
+	}
 
-		// Iterate through the tasks to create the workflow
-		if err := t.iterateTasks(ctx, tasks, input, state); err != nil {
-			return nil, err
+	if err := registerStateQuery(ctx, state, t.secrets); err != nil {
+		return nil, fmt.Errorf("error registering state query: %w", err)
+	}
+
+	ctx = withLogSecrets(ctx, t.secrets, state)
+
+	timeout, err := t.activityDefaults.timeout(t.doc)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("Setting activity options", "startToCloseTimeout", timeout)
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: timeout,
+		RetryPolicy:         t.retryPolicy,
+		// Keep the priority of an inline do task for its child tasks
+		Priority: taskPriority(ctx),
+	})
+
+	// Iterate through the tasks to create the workflow
+	if err := t.runTasks(ctx, tasks, input, state); err != nil {
+		t.runOnFailure(ctx, input, state, err)
+		return nil, err
+	}
+
+	// Shape the result returned to the caller
+	if t.GetTaskName() == t.doc.Document.Name {
+		return t.workflowResult(ctx, state)
+	}
+
+	return state.Output, nil
+}
+
+// workflowResult transforms the output for the whole document and validates it
+func (t *DoTaskBuilder) workflowResult(ctx workflow.Context, state *utils.State) (any, error) {
+	logger := workflow.GetLogger(ctx)
+
+	output, err := t.transformOutput(ctx, t.doc.Output, state, state.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Validating output against document")
+	if err := t.validateOutput(ctx, t.doc.Output, output); err != nil {
+		logger.Debug("Document output validation error", "error", err)
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// activityRetryPolicy returns the document's default retry policy for
+// activities, or nil to use Temporal's default
+func (t *DoTaskBuilder) activityRetryPolicy() (*temporal.RetryPolicy, error) {
+	if t.doc == nil {
+		return nil, nil
+	}
+
+	policy, err := metadata.GetRetryPolicy(t.doc.Document.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry policy metadata for document %s: %w", t.doc.Document.Name, err)
+	}
+
+	return t.activityDefaults.retryPolicy(policy), nil
+}
+
+// continueAsNewAfter returns the history length after which the workflow
+// should continue-as-new. Zero means that this is disabled
+func (t *DoTaskBuilder) continueAsNewAfter() (int, error) {
+	if t.doc == nil {
+		return 0, nil
+	}
+
+	after, err := metadata.GetContinueAsNewAfter(t.doc.Document.Metadata)
+	if err != nil {
+		return 0, fmt.Errorf("invalid document continue as new metadata: %w", err)
+	}
+
+	return after, nil
+}
+
+// shouldContinueAsNew returns true if this workflow's history has reached the
+// continueAsNewAfter threshold. This only applies to the registered workflow
+// as inline do tasks share their history with their parent. No check is made
+// until a task has run to guarantee that each run makes progress.
+func (t *DoTaskBuilder) shouldContinueAsNew(ctx workflow.Context, hasRun bool) (bool, error) {
+	threshold, err := t.continueAsNewAfter()
+	if err != nil || !hasRun || threshold == 0 {
+		return false, err
+	}
+
+	info := workflow.GetInfo(ctx)
+	if info.WorkflowType.Name != t.GetTaskName() {
+		return false, nil
+	}
+
+	return info.GetCurrentHistoryLength() >= threshold, nil
+}
+
+// continueAsNew restarts the workflow with a fresh history. The state is
+// passed to the new run in full, so the data, environment variables, input and
+// any output already exported survive the boundary. The name of the next task
+// is recorded in the state so the new run resumes from it rather than from the
+// first task.
+func (t *DoTaskBuilder) continueAsNew(ctx workflow.Context, input any, state *utils.State, nextTask string) error {
+	info := workflow.GetInfo(ctx)
+
+	workflow.GetLogger(ctx).Info("Continuing workflow as new",
+		"historyLength", info.GetCurrentHistoryLength(),
+		"nextTask", nextTask,
+	)
+
+	state.ContinueFrom = nextTask
+
+	return workflow.NewContinueAsNewError(ctx, info.WorkflowType.Name, input, state)
+}
+
+// resumeFrom returns the task to resume from if this run was started by a
+// continue-as-new, clearing it from the state
+func (t *DoTaskBuilder) resumeFrom(ctx workflow.Context, state *utils.State) string {
+	resumeFrom := state.ContinueFrom
+	if resumeFrom == "" || workflow.GetInfo(ctx).WorkflowType.Name != t.GetTaskName() {
+		return ""
+	}
+
+	workflow.GetLogger(ctx).Debug("Resuming workflow after continue-as-new", "nextTask", resumeFrom)
+
+	state.ContinueFrom = ""
+	state.AddWorkflowInfo(ctx)
+
+	return resumeFrom
+}
+
+// useResultEnvelope returns true if the document opts into the result envelope
+func (t *DoTaskBuilder) useResultEnvelope() bool {
+	if t.doc == nil {
+		return false
+	}
+
+	v, ok := t.doc.Document.Metadata[metadata.MetadataResultEnvelope].(bool)
+	return ok && v
+}
+
+// taskFlowDirective returns the flow directive for the task. A directive
+// returned by the task, such as by a switch, takes precedence over "then".
+func taskFlowDirective(taskBase *model.TaskBase, output any) (*model.FlowDirective, any) {
+	if d, ok := output.(*flowDirective); ok {
+		return d.Then, nil
+	}
+
+	return taskBase.Then, output
+}
+
+// prepareTask readies the state for the task, returning false if the task
+// should be skipped
+func (t *DoTaskBuilder) prepareTask(ctx workflow.Context, task workflowFunc, state *utils.State) (bool, error) {
+	logger := workflow.GetLogger(ctx)
+
+	if ctx.Err() != nil {
+		logger.Debug("Skipping task as it has been cancelled", "name", task.Name)
+		return false, nil
+	}
+
+	logger.Debug("Check if task should run in this version", "task", task.Name)
+	if toRun, err := CheckTaskVersion(ctx, task.GetTask(), state); err != nil {
+		logger.Error("Error checking task version", "error", err, "name", task.Name)
+		return false, err
+	} else if !toRun {
+		logger.Debug("Skipping task as not in this version", "name", task.Name)
+		return false, nil
+	}
+
+	logger.Debug("Check if task should be run", "task", task.Name)
+	if toRun, err := task.ShouldRun(state); err != nil {
+		logger.Error("Error checking if statement", "error", err, "name", task.Name)
+		return false, err
+	} else if !toRun {
+		logger.Debug("Skipping task as if statement resolve as false", "name", task.Name)
+		addTaskSkipped(ctx, task, state)
+		return false, nil
+	}
+
+	logger.Debug("Parse metadata", "name", task.Name)
+	if err := task.ParseMetadata(ctx, state); err != nil {
+		logger.Error("Error parsing metadata", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// runAndStoreTask runs the task and adds its result to the state, returning
+// the task's flow directive and whether it ran
+func (t *DoTaskBuilder) runAndStoreTask(
+	ctx workflow.Context, task workflowFunc, input any, state *utils.State,
+) (*model.FlowDirective, bool, error) {
+	logger := workflow.GetLogger(ctx)
+
+	logger.Debug("Adding summary to activity context", "name", task.Name)
+	ao := workflow.GetActivityOptions(ctx)
+	ao.Summary = task.Name
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	output, ran, err := t.runTask(ctx, task, input, state)
+	if err != nil {
+		if !temporal.IsCanceledError(err) {
+			logger.Error("Error running task", "name", task.Name, "error", err)
+		}
+		return nil, ran, err
+	} else if !ran {
+		return nil, false, nil
+	}
+
+	then, output := taskFlowDirective(task.GetTask().GetBase(), output)
+
+	output, err = t.processOutput(ctx, task, state, output)
+	if err != nil {
+		logger.Error("Error processing task output", "name", task.Name, "error", err)
+		return nil, true, err
+	}
+
+	addTaskResult(ctx, task, state, output)
+
+	// Set the output - this is only set if there's an export.as on the task
+	state.AddOutput(task.GetTask(), output)
+
+	return then, true, nil
+}
+
+// runTask prepares and runs the task, returning whether it ran. If the task has
+// an input.from, the task receives the transformed input in place of the raw
+// input for its duration. The input is validated against the task's schema
+// after it's been transformed.
+//
+// Each task is traced in its own span, which is the parent of any activities
+// or child workflows it starts.
+func (t *DoTaskBuilder) runTask(
+	ctx workflow.Context, task workflowFunc, input any, state *utils.State,
+) (output any, ran bool, err error) {
+	logger := workflow.GetLogger(ctx)
+
+	ctx, span := tracing.StartWorkflowSpan(ctx, task.Name, attribute.String("zigflow.task.type", TaskType(task.GetTask())))
+	defer func() {
+		span.SetAttributes(attribute.Bool("zigflow.task.skipped", !ran))
+		span.End(err)
+	}()
+
+	if ran, err = t.prepareTask(ctx, task, state); err != nil || !ran {
+		return nil, ran, err
+	}
+
+	if ctx, err = withTaskPriority(ctx, task.GetTask()); err != nil {
+		return nil, true, err
+	}
+
+	if ctx, err = t.withTaskActivityOptions(ctx, task); err != nil {
+		return nil, true, err
+	}
+
+	ctx, clearDetails, err := t.withTaskSummary(ctx, task, state)
+	if err != nil {
+		return nil, true, err
+	}
+	defer clearDetails()
+
+	inputDef := task.GetTask().GetBase().Input
+	if inputDef != nil && inputDef.From != nil {
+		logger.Debug("Transforming task input", "name", task.Name)
+		transformed, err := t.evaluateTransform(ctx, inputDef.From, state)
+		if err != nil {
+			return nil, true, err
 		}
 
-		return state.Output, nil
+		original := state.Input
+		state.Input = transformed
+		defer func() {
+			state.Input = original
+		}()
+
+		input = transformed
 	}
+
+	// Check input for the task
+	logger.Debug("Validating input against task", "name", task.Name)
+	if err := t.validateInput(ctx, inputDef, state); err != nil {
+		logger.Debug("Task input validation error", "error", err)
+		return nil, true, err
+	}
+
+	logger.Info("Running task", "name", task.Name)
+	output, err = recordTaskMetrics(ctx, task, func() (any, error) {
+		return task.Func(ctx, input, state)
+	})
+
+	return output, true, err
+}
+
+// addTaskResult makes the task's result, after its output.as, available to
+// later tasks in the state's data, under the task's name. This is independent
+// of export.as, which controls what is output from the workflow. Set tasks are
+// excluded as they add their result to the data themselves.
+func addTaskResult(ctx workflow.Context, task workflowFunc, state *utils.State, output any) {
+	// The task may have been skipped previously, such as when looping with then
+	if isTaskSkipped(state.Data[task.GetTaskName()]) {
+		state.RemoveData(task.GetTaskName())
+	}
+
+	if _, ok := task.GetTask().(*model.SetTask); ok || output == nil {
+		return
+	}
+
+	workflow.GetLogger(ctx).Debug("Setting task result to the state", "key", task.GetTaskName())
+	state.AddData(map[string]any{
+		task.GetTaskName(): output,
+	})
+}
+
+// addTaskSkipped marks the task as skipped by its if statement in the state's
+// data, under the task's name. This lets later tasks tell a skipped task apart
+// from one which ran without a result.
+func addTaskSkipped(ctx workflow.Context, task workflowFunc, state *utils.State) {
+	workflow.GetLogger(ctx).Debug("Marking task as skipped in the state", "key", task.GetTaskName())
+	state.AddData(map[string]any{
+		task.GetTaskName(): map[string]any{
+			TaskSkippedKey: true,
+		},
+	})
+}
+
+// isTaskSkipped checks if the data is the marker set by addTaskSkipped
+func isTaskSkipped(data any) bool {
+	m, ok := data.(map[string]any)
+	return ok && len(m) == 1 && m[TaskSkippedKey] == true
+}
+
+// processOutput transforms the task's output with output.as, returning the
+// value to store in the state. An output offloaded by its activity stays a
+// reference, which is what the expression sees.
+func (t *DoTaskBuilder) processOutput(ctx workflow.Context, task workflowFunc, state *utils.State, output any) (any, error) {
+	return t.transformOutput(ctx, task.GetTask().GetBase().Output, state, output)
+}
+
+// transformOutput evaluates the output.as, if set. The raw output is
+// available to the expression as ".result".
+func (t *DoTaskBuilder) transformOutput(ctx workflow.Context, outputDef *model.Output, state *utils.State, output any) (any, error) {
+	if outputDef == nil || outputDef.As == nil {
+		return output, nil
+	}
+
+	workflow.GetLogger(ctx).Debug("Transforming output")
+
+	return t.evaluateTransform(ctx, outputDef.As, state.WithResult(output))
+}
+
+// evaluateTransform evaluates an input.from or output.as against the state
+func (t *DoTaskBuilder) evaluateTransform(ctx workflow.Context, from *model.ObjectOrRuntimeExpr, state *utils.State) (any, error) {
+	wrapper := func(fn func() (any, error)) (any, error) {
+		return t.sideEffectWrapper(ctx, fn)
+	}
+
+	var res any
+	var err error
+	switch v := from.AsStringOrMap().(type) {
+	case string:
+		res, err = utils.EvaluateString(v, state, wrapper)
+	case map[string]any:
+		res, err = utils.TraverseAndEvaluateObj(model.NewObjectOrRuntimeExpr(swUtil.DeepClone(v)), state, wrapper)
+	default:
+		return nil, newValidationError("Transform must be an object or runtime expression", nil, t.GetTaskName())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating transform: %w", err)
+	}
+
+	return res, nil
 }
 
 func (t *DoTaskBuilder) iterateTasks(
@@ -207,9 +751,12 @@ func (t *DoTaskBuilder) iterateTasks(
 	var nextTargetName *string
 	logger := workflow.GetLogger(ctx)
 
-	for _, task := range tasks {
-		taskBase := task.GetTask().GetBase()
+	if resumeFrom := t.resumeFrom(ctx, state); resumeFrom != "" {
+		nextTargetName = &resumeFrom
+	}
 
+	var hasRun bool
+	for _, task := range tasks {
 		state.AddData(map[string]any{
 			"task": map[string]any{
 				"name": task.GetTaskName(),
@@ -229,49 +776,27 @@ func (t *DoTaskBuilder) iterateTasks(
 			}
 		}
 
-		logger.Debug("Check if task should be run", "task", task.Name)
-		if toRun, err := task.ShouldRun(state); err != nil {
-			logger.Error("Error checking if statement", "error", err, "name", task.Name)
-			return err
-		} else if !toRun {
-			logger.Debug("Skipping task as if statement resolve as false", "name", task.Name)
-			continue
-		}
-
-		// Check input for the task
-		logger.Debug("Validating input against task", "name", task.Name)
-		if err := t.validateInput(ctx, taskBase.Input, state); err != nil {
-			logger.Debug("Task input validation error", "error", err)
+		if ok, err := t.shouldContinueAsNew(ctx, hasRun); err != nil {
 			return err
+		} else if ok {
+			return t.continueAsNew(ctx, input, state, task.Name)
 		}
 
-		logger.Debug("Parse metadata", "name", task.Name)
-		if err := task.ParseMetadata(ctx, state); err != nil {
-			logger.Error("Error parsing metadata", "error", err)
-			return err
-		}
-
-		logger.Debug("Adding summary to activity context", "name", task.Name)
-		ao := workflow.GetActivityOptions(ctx)
-		ao.Summary = task.Name
-		ctx = workflow.WithActivityOptions(ctx, ao)
-
-		logger.Info("Running task", "name", task.Name)
-		output, err := task.Func(ctx, input, state)
+		then, ran, err := t.runAndStoreTask(ctx, task, input, state)
 		if err != nil {
 			if temporal.IsCanceledError(err) {
 				logger.Debug("Task cancelled", "name", task.Name)
 				return nil
 			}
 
-			logger.Error("Error running task", "name", task.Name, "error", err)
 			return err
+		} else if !ran {
+			continue
 		}
 
-		// Set the output - this is only set if there's an export.as on the task
-		state.AddOutput(task.GetTask(), output)
+		hasRun = true
 
-		if then := taskBase.Then; then != nil {
+		if then != nil {
 			flowDirective := then.Value
 			if then.IsTermination() {
 				logger.Debug("Workflow to be terminated", "flow", flowDirective)
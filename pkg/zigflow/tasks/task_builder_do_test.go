@@ -0,0 +1,751 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestResultEnvelope(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Workflow string
+		Expected ResultEnvelope
+	}{
+		{
+			Name: "Success",
+			Workflow: `document:
+  dsl: 1.0.0
+  namespace: default
+  name: envelope
+  version: 0.0.1
+  metadata:
+    resultEnvelope: true
+do:
+  - step:
+      export:
+        as: data
+      set:
+        hello: world`,
+			Expected: ResultEnvelope{
+				Status: ResultEnvelopeStatusSuccess,
+				Output: map[string]any{
+					"data": map[string]any{
+						"hello": "world",
+					},
+				},
+			},
+		},
+		{
+			Name: "Failure",
+			Workflow: `document:
+  dsl: 1.0.0
+  namespace: default
+  name: envelope
+  version: 0.0.1
+  metadata:
+    resultEnvelope: true
+do:
+  - step:
+      raise:
+        error:
+          type: https://serverlessworkflow.io/spec/1.0.0/errors/runtime
+          status: 500
+          title: Some error`,
+			Expected: ResultEnvelope{
+				Status: ResultEnvelopeStatusFailure,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, test.Workflow)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result ResultEnvelope
+			assert.NoError(t, env.GetWorkflowResult(&result))
+
+			assert.Equal(t, test.Expected.Status, result.Status)
+			if test.Expected.Status == ResultEnvelopeStatusSuccess {
+				assert.Equal(t, test.Expected.Output, result.Output)
+				assert.Nil(t, result.Error)
+			} else {
+				assert.Nil(t, result.Output)
+				assert.NotEmpty(t, result.Error["message"])
+			}
+		})
+	}
+}
+
+func TestContinueAsNew(t *testing.T) {
+	tests := []struct {
+		Name          string
+		HistoryLength int
+		ExpectCAN     bool
+	}{
+		{
+			Name:          "Below threshold",
+			HistoryLength: 99,
+		},
+		{
+			Name:          "At threshold",
+			HistoryLength: 100,
+			ExpectCAN:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: continue
+  version: 0.0.1
+  metadata:
+    continueAsNewAfter: 100
+do:
+  - first:
+      export:
+        as: first
+      set:
+        hello: world
+  - second:
+      export:
+        as: second
+      set:
+        hello: again`)
+			env := newTestEnvironment(t, doc)
+			env.SetCurrentHistoryLength(test.HistoryLength)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			err := env.GetWorkflowError()
+			if !test.ExpectCAN {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, workflow.IsContinueAsNewError(err))
+
+			var canErr *workflow.ContinueAsNewError
+			assert.True(t, errors.As(err, &canErr))
+			assert.Equal(t, doc.Document.Name, canErr.WorkflowType.Name)
+
+			var input any
+			var state *utils.State
+			assert.NoError(t, converter.GetDefaultDataConverter().FromPayloads(canErr.Input, &input, &state))
+			assert.Equal(t, "second", state.ContinueFrom)
+			assert.Equal(t, map[string]any{
+				"first": map[string]any{
+					"hello": "world",
+				},
+			}, state.Output)
+		})
+	}
+}
+
+func TestContinueAsNewResume(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: continue
+  version: 0.0.1
+  metadata:
+    continueAsNewAfter: 100
+do:
+  - first:
+      export:
+        as: first
+      set:
+        hello: world
+  - second:
+      export:
+        as: second
+      set:
+        hello: again`)
+	env := newTestEnvironment(t, doc)
+
+	state := utils.NewState()
+	state.ContinueFrom = "second"
+	state.Output["first"] = map[string]any{"hello": "previous run"}
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, state)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"first": map[string]any{
+			"hello": "previous run",
+		},
+		"second": map[string]any{
+			"hello": "again",
+		},
+	}, result)
+}
+
+func TestContinueAsNewInvalid(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: continue
+  version: 0.0.1
+  metadata:
+    continueAsNewAfter: 10.5
+do:
+  - first:
+      set:
+        hello: world`)
+
+	builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+	assert.NoError(t, err)
+
+	assert.EqualError(t, builder.PostLoad(), "invalid document continue as new metadata: continue as new after must be a whole number")
+}
+
+func TestInputFrom(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Workflow string
+		Input    any
+		Expected map[string]any
+		Error    bool
+	}{
+		{
+			Name: "Document input from expression",
+			Workflow: `document:
+  dsl: 1.0.0
+  namespace: default
+  name: input
+  version: 0.0.1
+input:
+  from: ${ .input.payload }
+  schema:
+    format: json
+    document:
+      type: object
+      required:
+        - name
+do:
+  - step:
+      export:
+        as: result
+      set:
+        name: ${ .input.name }`,
+			Input: map[string]any{
+				"payload": map[string]any{
+					"name": "some-name",
+				},
+			},
+			Expected: map[string]any{
+				"result": map[string]any{
+					"name": "some-name",
+				},
+			},
+		},
+		{
+			Name: "Document schema validated after from",
+			Workflow: `document:
+  dsl: 1.0.0
+  namespace: default
+  name: input
+  version: 0.0.1
+input:
+  from: ${ .input.payload }
+  schema:
+    format: json
+    document:
+      type: object
+      required:
+        - name
+do:
+  - step:
+      set:
+        name: ${ .input.name }`,
+			Input: map[string]any{
+				"name": "some-name",
+			},
+			Error: true,
+		},
+		{
+			Name: "Task input from object",
+			Workflow: `document:
+  dsl: 1.0.0
+  namespace: default
+  name: input
+  version: 0.0.1
+do:
+  - step:
+      input:
+        from:
+          userName: ${ .input.user.name }
+        schema:
+          format: json
+          document:
+            type: object
+            required:
+              - userName
+      export:
+        as: step
+      set:
+        name: ${ .input.userName }
+  - after:
+      export:
+        as: after
+      set:
+        name: ${ .input.user.name }`,
+			Input: map[string]any{
+				"user": map[string]any{
+					"name": "some-name",
+				},
+			},
+			Expected: map[string]any{
+				"step": map[string]any{
+					"name": "some-name",
+				},
+				"after": map[string]any{
+					"name": "some-name",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, test.Workflow)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, test.Input, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			if test.Error {
+				assert.Error(t, env.GetWorkflowError())
+				return
+			}
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+}
+
+func TestInputValidationDetails(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: input
+  version: 0.0.1
+input:
+  schema:
+    format: json
+    document:
+      type: object
+      required:
+        - name
+      properties:
+        user:
+          type: object
+          properties:
+            age:
+              type: integer
+do:
+  - step:
+      set:
+        name: ${ .input.name }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{
+		"user": map[string]any{
+			"age": "old",
+		},
+	}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+
+	var appErr *temporal.ApplicationError
+	assert.ErrorAs(t, env.GetWorkflowError(), &appErr)
+	assert.Equal(t, "Validation", appErr.Type())
+
+	var details map[string]any
+	assert.NoError(t, appErr.Details(&details))
+	assert.Equal(t, float64(400), details["status"])
+	assert.ElementsMatch(t, []any{
+		map[string]any{"pointer": "/name", "message": "name is required"},
+		map[string]any{"pointer": "/user/age", "message": "Invalid type. Expected: integer, given: string"},
+	}, details["errors"])
+}
+
+func TestOutputAs(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Workflow string
+		Expected any
+	}{
+		{
+			Name: "Task output",
+			Workflow: `document:
+  dsl: 1.0.0
+  namespace: default
+  name: output
+  version: 0.0.1
+do:
+  - step:
+      output:
+        as:
+          fullName: ${ .result.first + " " + .result.last }
+      export:
+        as: user
+      set:
+        first: Some
+        last: Name`,
+			Expected: map[string]any{
+				"user": map[string]any{
+					"fullName": "Some Name",
+				},
+			},
+		},
+		{
+			Name: "Document output",
+			Workflow: `document:
+  dsl: 1.0.0
+  namespace: default
+  name: output
+  version: 0.0.1
+output:
+  as: ${ .result.user.id }
+do:
+  - step:
+      export:
+        as: user
+      set:
+        id: some-id
+        internal: value`,
+			Expected: "some-id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, test.Workflow)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+}
+
+func TestOutputSchema(t *testing.T) {
+	tests := []struct {
+		Name  string
+		ID    string
+		Error bool
+	}{
+		{
+			Name: "Conforming output",
+			ID:   "abc123",
+		},
+		{
+			Name:  "Non-conforming output",
+			ID:    "x",
+			Error: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: output
+  version: 0.0.1
+output:
+  as: ${ .result.user }
+  schema:
+    format: json
+    document:
+      type: object
+      required:
+        - id
+      properties:
+        id:
+          type: string
+          minLength: 3
+do:
+  - step:
+      export:
+        as: user
+      set:
+        id: %s`, test.ID))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Error {
+				var appErr *temporal.ApplicationError
+				assert.ErrorAs(t, env.GetWorkflowError(), &appErr)
+				assert.True(t, appErr.NonRetryable())
+				assert.ErrorContains(t, appErr, "Workflow output did not meet JSON schema specification")
+
+				var details map[string]any
+				assert.NoError(t, appErr.Details(&details))
+				assert.Equal(t, []any{
+					map[string]any{"pointer": "/id", "message": "String length must be greater than or equal to 3"},
+				}, details["errors"])
+				return
+			}
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, map[string]any{"id": test.ID}, result)
+		})
+	}
+}
+
+func TestTaskResultInData(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://example.com/user", func(*http.Request) (*http.Response, error) {
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"name": "Test"})
+	})
+
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: result
+  version: 0.0.1
+do:
+  - getUser:
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/user
+  - getUserName:
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/user
+      output:
+        as: ${ .result.name }
+  - nested:
+      do:
+        - step:
+            export:
+              as: greeting
+            set:
+              greeting: hello
+  - check:
+      if: ${ .data.getUser.name == "Test" }
+      export:
+        as: result
+      set:
+        user: ${ .data.getUser }
+        userName: ${ .data.getUserName }
+        nested: ${ .data.nested }
+        hasSetResult: ${ .data | has("check") }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+
+	// Only the exported results are output from the workflow
+	assert.Equal(t, map[string]any{
+		"greeting": map[string]any{"greeting": "hello"},
+		"result": map[string]any{
+			"user":         map[string]any{"name": "Test"},
+			"userName":     "Test",
+			"nested":       map[string]any{"greeting": map[string]any{"greeting": "hello"}},
+			"hasSetResult": false,
+		},
+	}, result)
+}
+
+func TestTaskSkippedInData(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: skip
+  version: 0.0.1
+do:
+  - skipped:
+      if: ${ false }
+      set:
+        hello: world
+  - ran:
+      if: ${ true }
+      do:
+        - step:
+            set:
+              hello: world
+  - check:
+      export:
+        as: result
+      set:
+        wasSkipped: ${ .data.skipped.__skipped == true }
+        wasRun: ${ .data.ran.__skipped != true }
+  - cleanup:
+      if: ${ .data.skipped.__skipped }
+      export:
+        as: cleanup
+      set:
+        done: true`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"result": map[string]any{
+			"wasSkipped": true,
+			"wasRun":     true,
+		},
+		"cleanup": map[string]any{
+			"done": true,
+		},
+	}, result)
+}
+
+func TestActivityRetryPolicy(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := map[string]int{}
+	httpmock.RegisterResponder(http.MethodGet, `=~^https://example.com/(\w+)`, func(req *http.Request) (*http.Response, error) {
+		calls[req.URL.Path]++
+		return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+	})
+
+	tests := []struct {
+		Name     string
+		Metadata string
+		Path     string
+		Calls    int
+		Error    string
+	}{
+		{
+			Name:     "Default applied",
+			Metadata: "retryPolicy: { maximumAttempts: 3, initialInterval: 1s }",
+			Path:     "/top",
+			Calls:    3,
+		},
+		{
+			Name:     "Default applied in inline do",
+			Metadata: "retryPolicy: { maximumAttempts: 2 }",
+			Path:     "/nested",
+			Calls:    2,
+		},
+		{
+			Name:     "Non-retryable error type",
+			Metadata: `retryPolicy: { nonRetryableErrorTypes: ["CallHTTP error"] }`,
+			Path:     "/top",
+			Calls:    1,
+		},
+		{
+			Name:     "Invalid",
+			Metadata: "retryPolicy: { maximumAttempts: -1 }",
+			Error:    "invalid retry policy metadata for document retry: retry policy maximumAttempts must be between 0 and",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			clear(calls)
+
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: retry
+  version: 0.0.1
+  metadata:
+    %s
+do:
+  - top:
+      if: ${ .input.path == "/top" }
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/top
+  - inline:
+      if: ${ .input.path == "/nested" }
+      do:
+        - nested:
+            call: http
+            with:
+              method: get
+              endpoint: https://example.com/nested`, test.Metadata))
+
+			if test.Error != "" {
+				builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+				assert.NoError(t, err)
+
+				_, err = builder.Build()
+				assert.ErrorContains(t, err, test.Error)
+				return
+			}
+
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{"path": test.Path}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.ErrorContains(t, env.GetWorkflowError(), "CallHTTP returned 5xx error")
+			assert.Equal(t, map[string]int{test.Path: test.Calls}, calls)
+		})
+	}
+}
@@ -94,7 +94,7 @@ func (t *ForTaskBuilder) createBuilder() (TaskBuilder, error) {
 	// Register the ForTask's Do as a child workflow
 	t.childWorkflowName = utils.GenerateChildWorkflowName("for", t.GetTaskName())
 
-	builder, err := NewTaskBuilder(t.childWorkflowName, &model.DoTask{Do: t.task.Do}, t.temporalWorker, t.doc)
+	builder, err := t.newTaskBuilder(t.childWorkflowName, &model.DoTask{Do: t.task.Do})
 	if err != nil {
 		log.Error().Str("task", t.childWorkflowName).Err(err).Msg("Error creating the for task builder")
 		return nil, fmt.Errorf("error creating the for task builder: %w", err)
@@ -198,14 +198,16 @@ func (t *ForTaskBuilder) iterator(ctx workflow.Context, key, value any, state *u
 	// Run the tasks
 	opts := workflow.ChildWorkflowOptions{
 		// key may be an integer or a string - use %v to let Go figure out how to represent it
-		WorkflowID: fmt.Sprintf("%s_for_%v", workflow.GetInfo(ctx).WorkflowExecution.ID, key),
+		WorkflowID:    fmt.Sprintf("%s_for_%v", t.childWorkflowIDPrefix(ctx), key),
+		Priority:      taskPriority(ctx),
+		StaticSummary: taskSummary(ctx),
 	}
 	childCtx := workflow.WithChildOptions(ctx, opts)
 
 	logger.Info("Triggering forked child workflow", "name", t.childWorkflowName)
 
 	var res map[string]any
-	if err := workflow.ExecuteChildWorkflow(childCtx, t.childWorkflowName, state.Input, state).Get(ctx, &res); err != nil {
+	if err := executeChildWorkflow(childCtx, t.childWorkflowName, state.Input, state).Get(ctx, &res); err != nil {
 		logger.Error("Error calling for workflow", "error", err, "workflow", t.childWorkflowName)
 		return nil, fmt.Errorf("error calling for workflow: %w", err)
 	}
@@ -51,11 +51,29 @@ type ForkTaskBuilder struct {
 	builder[*model.ForkTask]
 }
 
+// The prefix of a forked branch's child workflow name and ID
+const forkPrefix = "fork"
+
 type forkedTask struct {
 	task              *model.TaskItem
 	childWorkflowName string
 }
 
+// newForkedTask names the branch's child workflow. This is the name it's
+// registered with, and what's executed by the fork.
+func newForkedTask(taskName string, branch *model.TaskItem) *forkedTask {
+	return &forkedTask{
+		task:              branch,
+		childWorkflowName: utils.GenerateChildWorkflowName(forkPrefix, taskName, branch.Key),
+	}
+}
+
+// childWorkflowID returns the ID of the branch's child workflow, which is
+// unique to the branch within the fork task
+func (f *forkedTask) childWorkflowID(prefix string) string {
+	return utils.GenerateChildWorkflowID(prefix, forkPrefix, f.task.Key)
+}
+
 func (t *ForkTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 	forkedTasks, builders, err := t.buildOrPostLoad()
 	if err != nil {
@@ -111,12 +129,9 @@ func (t *ForkTaskBuilder) buildOrPostLoad() ([]*forkedTask, []TaskBuilder, error
 	builders := make([]TaskBuilder, 0)
 
 	for _, branch := range *t.task.Fork.Branches {
-		childWorkflowName := utils.GenerateChildWorkflowName("fork", t.GetTaskName(), branch.Key)
-
-		forkedTasks = append(forkedTasks, &forkedTask{
-			task:              branch,
-			childWorkflowName: childWorkflowName,
-		})
+		forked := newForkedTask(t.GetTaskName(), branch)
+		forkedTasks = append(forkedTasks, forked)
+		childWorkflowName := forked.childWorkflowName
 
 		if d := branch.AsDoTask(); d == nil {
 			// Single task - register this as a single task workflow
@@ -129,7 +144,7 @@ func (t *ForkTaskBuilder) buildOrPostLoad() ([]*forkedTask, []TaskBuilder, error
 			}
 		}
 
-		builder, err := NewTaskBuilder(childWorkflowName, branch.Task, t.temporalWorker, t.doc)
+		builder, err := t.newTaskBuilder(childWorkflowName, branch.Task)
 		if err != nil {
 			log.Error().Err(err).Msg("Error creating the forked task builder")
 			return nil, nil, fmt.Errorf("error creating the forked task builder: %w", err)
@@ -150,6 +165,9 @@ func (t *ForkTaskBuilder) exec(forkedTasks []*forkedTask) (TemporalWorkflowFunc,
 
 		ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
 			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         workflow.GetActivityOptions(ctx).RetryPolicy,
+			Priority:            taskPriority(ctx),
+			Summary:             taskSummary(ctx),
 		})
 
 		futures := &utils.CancellableFutures{}
@@ -159,9 +177,12 @@ func (t *ForkTaskBuilder) exec(forkedTasks []*forkedTask) (TemporalWorkflowFunc,
 		output := map[string]any{}
 
 		// Run the child workflows in parallel
+		idPrefix := t.childWorkflowIDPrefix(ctx)
 		for _, branch := range forkedTasks {
 			opts := workflow.ChildWorkflowOptions{
-				WorkflowID: fmt.Sprintf("%s_fork_%s", workflow.GetInfo(ctx).WorkflowExecution.ID, branch.task.Key),
+				WorkflowID:    branch.childWorkflowID(idPrefix),
+				Priority:      taskPriority(ctx),
+				StaticSummary: taskSummary(ctx),
 			}
 			if isCompeting {
 				// Allow cancellation without killing parent
@@ -176,7 +197,7 @@ func (t *ForkTaskBuilder) exec(forkedTasks []*forkedTask) (TemporalWorkflowFunc,
 			futures.Add(branch.childWorkflowName, utils.CancellableFuture{
 				Cancel:  cancelHandler,
 				Context: childCtx,
-				Future:  workflow.ExecuteChildWorkflow(childCtx, branch.childWorkflowName, input, childState),
+				Future:  executeChildWorkflow(childCtx, branch.childWorkflowName, input, childState),
 			})
 		}
 
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+// recordingWorker records the names of the registered workflows
+type recordingWorker struct {
+	testWorker
+
+	registered []string
+}
+
+func (w *recordingWorker) RegisterWorkflowWithOptions(wf any, opts workflow.RegisterOptions) {
+	w.registered = append(w.registered, opts.Name)
+	w.testWorker.RegisterWorkflowWithOptions(wf, opts)
+}
+
+func TestForkChildWorkflows(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: fork
+  version: 0.0.1
+do:
+  - split:
+      fork:
+        branches:
+          - first:
+              do:
+                - setFirst:
+                    set:
+                      first: true
+          - second:
+              set:
+                second: true`)
+
+	tests := []struct {
+		Name     string
+		Legacy   bool
+		IDPrefix string
+	}{
+		{
+			Name:     "Task name in ID",
+			IDPrefix: "default-test-workflow-id_split",
+		},
+		{
+			Name:     "Started before the task name was in the ID",
+			Legacy:   true,
+			IDPrefix: "default-test-workflow-id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var s testsuite.WorkflowTestSuite
+			env := s.NewTestWorkflowEnvironment()
+			w := &recordingWorker{testWorker: testWorker{env: env}}
+
+			builder, err := NewDoTaskBuilder(w, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+			assert.NoError(t, err)
+			assert.NoError(t, builder.PostLoad())
+			_, err = builder.Build()
+			assert.NoError(t, err)
+
+			// Workflows started before the change keep the old IDs
+			if test.Legacy {
+				env.OnGetVersion(childWorkflowIDChangeID, workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+			}
+
+			executed := map[string]string{}
+			env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, _ workflow.Context, _ converter.EncodedValues) {
+				executed[info.WorkflowType.Name] = info.WorkflowExecution.ID
+			})
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			assert.Equal(t, map[string]string{
+				utils.GenerateChildWorkflowName(forkPrefix, "split", "first"):  test.IDPrefix + "_fork_first",
+				utils.GenerateChildWorkflowName(forkPrefix, "split", "second"): test.IDPrefix + "_fork_second",
+			}, executed)
+
+			for name := range executed {
+				assert.Contains(t, w.registered, name)
+			}
+		})
+	}
+}
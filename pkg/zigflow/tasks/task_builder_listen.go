@@ -17,11 +17,13 @@
 package tasks
 
 import (
+	"encoding/json"
 	"fmt"
 	"slices"
 	"time"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
 	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"go.temporal.io/sdk/temporal"
@@ -37,6 +39,16 @@ const (
 	ListenTaskTypeUpdate ListenTaskType = "update"
 )
 
+// Keys in a listener's "with" that are added by Zigflow
+const (
+	// Expression a query evaluates against the state
+	listenSelectKey = "select"
+	// JSON schema an update's payload must match
+	listenSchemaKey = "schema"
+	// Expression that must be true for an update to be accepted
+	listenWhenKey = "when"
+)
+
 func NewListenTaskBuilder(
 	temporalWorker worker.Worker,
 	task *model.ListenTask,
@@ -57,23 +69,19 @@ type ListenTaskBuilder struct {
 	builder[*model.ListenTask]
 }
 
+// listenQueryAwaitChangeID is the workflow version change that started waiting
+// for the signals and updates of a listener that also has a query
+const listenQueryAwaitChangeID = "zigflow-listen-query-await"
+
 func (t *ListenTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 	events, isAll, err := t.listEvents()
 	if err != nil {
 		return nil, err
 	}
 
-	timeout := time.Minute
-	if timeoutInterface, ok := t.task.Metadata["timeout"]; ok {
-		if timeoutStr, ok := timeoutInterface.(string); !ok {
-			return nil, fmt.Errorf("timeout must be a string")
-		} else {
-			if dur, err := time.ParseDuration(timeoutStr); err != nil {
-				return nil, fmt.Errorf("error parsing timeout to duration: %w", err)
-			} else {
-				timeout = dur
-			}
-		}
+	timeout, err := metadata.GetTimeout(t.task.Metadata, time.Minute)
+	if err != nil {
+		return nil, err
 	}
 
 	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
@@ -82,7 +90,10 @@ func (t *ListenTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 
 		areAllComplete := make([]bool, 0)
 		areAnyComplete := false
-		await := true
+		// Only signals and updates block - a listener of just queries returns
+		// immediately
+		await := false
+		hasQuery := false
 
 		fn := func(key int) func() {
 			return func() {
@@ -96,29 +107,39 @@ func (t *ListenTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 
 		for i, event := range events {
 			if isAll {
-				areAllComplete = append(areAllComplete, false)
+				// Queries never complete, so don't wait for them
+				areAllComplete = append(areAllComplete, ListenTaskType(event.With.Type) == ListenTaskTypeQuery)
 			}
 
 			switch ListenTaskType(event.With.Type) {
 			case ListenTaskTypeQuery:
 				// Non-blocking
-				await = false
+				hasQuery = true
 				if err := t.configureQuery(ctx, event, state); err != nil {
 					return nil, fmt.Errorf("error setting signal: %w", err)
 				}
 			case ListenTaskTypeSignal:
 				// Blocking
+				await = true
 				t.configureSignal(ctx, event, state, fn(i))
 			case ListenTaskTypeUpdate:
 				// Blocking
+				await = true
 				if err := t.configureUpdate(ctx, event, state, fn(i)); err != nil {
 					return nil, fmt.Errorf("error setting signal: %w", err)
 				}
 			}
 		}
 
+		// Workflows started before this returned immediately if there was a
+		// query. The version is only checked for these listeners.
+		if await && hasQuery && workflow.GetVersion(ctx, listenQueryAwaitChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+			logger.Debug("Not waiting for listeners alongside a query", "task", t.GetTaskName())
+			await = false
+		}
+
 		if await {
-			if err := t.await(ctx, timeout, isAll, areAnyComplete, areAllComplete); err != nil {
+			if err := t.await(ctx, timeout, isAll, &areAnyComplete, areAllComplete); err != nil {
 				return nil, err
 			}
 		}
@@ -128,7 +149,7 @@ func (t *ListenTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 }
 
 func (t *ListenTaskBuilder) await(
-	ctx workflow.Context, timeout time.Duration, isAll, areAnyComplete bool, areAllComplete []bool,
+	ctx workflow.Context, timeout time.Duration, isAll bool, areAnyComplete *bool, areAllComplete []bool,
 ) error {
 	logger := workflow.GetLogger(ctx)
 
@@ -139,8 +160,8 @@ func (t *ListenTaskBuilder) await(
 			logger.Debug("Waiting for all listeners to complete", "status", areAllComplete)
 			return utils.SlicesEqual(areAllComplete, true)
 		} else {
-			logger.Debug("Waiting for first listening to complete", "state", areAnyComplete)
-			return areAnyComplete
+			logger.Debug("Waiting for first listening to complete", "state", *areAnyComplete)
+			return *areAnyComplete
 		}
 	})
 	if err != nil {
@@ -167,6 +188,10 @@ func (t *ListenTaskBuilder) configureQuery(
 	handler := func() (any, error) {
 		logger.Debug("New query received", "event", event.With.ID)
 
+		if selector, ok := event.With.Additional[listenSelectKey].(string); ok {
+			return t.processSelect(ctx, event, selector, state)
+		}
+
 		return t.processReply(ctx, event, state)
 	}
 
@@ -221,12 +246,68 @@ func (t *ListenTaskBuilder) configureUpdate(
 		event.With.ID,
 		handler,
 		workflow.UpdateHandlerOptions{
-			Validator: func(ctx workflow.Context, _ any) error {
-				return nil
+			Validator: func(ctx workflow.Context, data any) error {
+				return t.validateUpdate(ctx, event, state, data)
 			},
 		})
 }
 
+// validateUpdate rejects an update before it's accepted if the payload doesn't
+// match the schema or the when expression isn't true. The expression is
+// evaluated against a copy of the state with the payload in the data, as it
+// would be stored, so the state isn't changed.
+func (t *ListenTaskBuilder) validateUpdate(ctx workflow.Context, event *model.EventFilter, state *utils.State, data any) error {
+	logger := workflow.GetLogger(ctx)
+
+	schema, err := updateSchema(event)
+	if err != nil {
+		return err
+	}
+	if err := utils.ValidateSchema(data, schema, t.GetTaskName()); err != nil {
+		logger.Debug("Update rejected by schema", "event", event.With.ID, "error", err)
+		return newValidationError("Update did not meet JSON schema specification", err, t.GetTaskName())
+	}
+
+	when, ok := event.With.Additional[listenWhenKey].(string)
+	if !ok {
+		return nil
+	}
+
+	res, err := utils.EvaluateString(when, state.Clone().AddData(map[string]any{
+		event.With.ID: data,
+	}))
+	if err != nil {
+		logger.Error("Error evaluating update when", "event", event.With.ID, "error", err)
+		return err
+	}
+	if res != true {
+		logger.Debug("Update rejected by when", "event", event.With.ID)
+		return newValidationError("Update rejected by when expression", nil, t.GetTaskName())
+	}
+
+	return nil
+}
+
+// updateSchema returns the schema the update's payload must match, or nil
+func updateSchema(event *model.EventFilter) (*model.Schema, error) {
+	v, ok := event.With.Additional[listenSchemaKey]
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error converting listen task schema to json: %w", err)
+	}
+
+	var schema *model.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid listen task schema: %w", err)
+	}
+
+	return schema, nil
+}
+
 func (t *ListenTaskBuilder) listEvents() (events []*model.EventFilter, isAll bool, err error) {
 	listen := t.task.Listen
 	if listen.To == nil {
@@ -293,6 +374,24 @@ func (t *ListenTaskBuilder) processReply(ctx workflow.Context, event *model.Even
 	return nil, nil
 }
 
+// processSelect returns the query's projection of the current state. This is
+// evaluated on each query so reflects the latest state.
+func (t *ListenTaskBuilder) processSelect(
+	ctx workflow.Context, event *model.EventFilter, selector string, state *utils.State,
+) (any, error) {
+	logger := workflow.GetLogger(ctx)
+
+	res, err := utils.EvaluateString(selector, state)
+	if err != nil {
+		logger.Error("Error evaluating select", "event", event.With.ID, "error", err)
+		return nil, err
+	}
+
+	logger.Debug("Replied from event", "event", event.With.ID)
+
+	return res, nil
+}
+
 func (t *ListenTaskBuilder) validateEventFilter(event *model.EventFilter) error {
 	if event.With.ID == "" {
 		return fmt.Errorf("listen task id is not set")
@@ -311,5 +410,54 @@ func (t *ListenTaskBuilder) validateEventFilter(event *model.EventFilter) error
 		return fmt.Errorf("listen task type is not known: %s", event.With.Type)
 	}
 
+	if err := validateListenSelect(event); err != nil {
+		return err
+	}
+
+	return validateUpdateValidator(event)
+}
+
+// validateUpdateValidator checks that a schema or when is set on an update and
+// can be used
+func validateUpdateValidator(event *model.EventFilter) error {
+	_, hasSchema := event.With.Additional[listenSchemaKey]
+	when, hasWhen := event.With.Additional[listenWhenKey]
+	if !hasSchema && !hasWhen {
+		return nil
+	}
+
+	if ListenTaskType(event.With.Type) != ListenTaskTypeUpdate {
+		return fmt.Errorf("listen task schema and when are only supported by updates: %s", event.With.ID)
+	}
+	if _, err := updateSchema(event); err != nil {
+		return fmt.Errorf("%w: %s", err, event.With.ID)
+	}
+	if hasWhen {
+		if w, ok := when.(string); !ok || !model.IsStrictExpr(w) {
+			return fmt.Errorf("listen task when must be a runtime expression: %s", event.With.ID)
+		}
+	}
+
+	return nil
+}
+
+// validateListenSelect checks that a select is a runtime expression on a query.
+// A query returns either the select or the data, so both can't be set.
+func validateListenSelect(event *model.EventFilter) error {
+	v, ok := event.With.Additional[listenSelectKey]
+	if !ok {
+		return nil
+	}
+
+	if ListenTaskType(event.With.Type) != ListenTaskTypeQuery {
+		return fmt.Errorf("listen task select is only supported by queries: %s", event.With.ID)
+	}
+	if selector, ok := v.(string); !ok || !model.IsStrictExpr(selector) {
+		return fmt.Errorf("listen task select must be a runtime expression: %s", event.With.ID)
+	}
+	if _, ok := event.With.Additional["data"]; ok {
+		return fmt.Errorf("listen task cannot set both select and data: %s", event.With.ID)
+	}
+
 	return nil
 }
@@ -0,0 +1,429 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestListenQuerySelect(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: query
+  version: 0.0.1
+do:
+  - queryStatus:
+      listen:
+        to:
+          one:
+            with:
+              id: get_status
+              type: query
+              select: ${ .data.order.status }
+  - queryOrder:
+      listen:
+        to:
+          one:
+            with:
+              id: get_order
+              type: query
+              select: "${ .data.order | { id, items: (.items | length) } }"
+  - createOrder:
+      set:
+        order:
+          id: ${ .input.id }
+          status: pending
+          items:
+            - apple
+            - banana
+  - wait:
+      wait:
+        hours: 1
+  - completeOrder:
+      set:
+        order:
+          id: ${ .input.id }
+          status: complete
+          items:
+            - apple
+            - banana`)
+	env := newTestEnvironment(t, doc)
+
+	query := func(name string) any {
+		t.Helper()
+
+		res, err := env.QueryWorkflow(name)
+		assert.NoError(t, err)
+
+		var v any
+		assert.NoError(t, res.Get(&v))
+		return v
+	}
+
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, "pending", query("get_status"))
+		assert.Equal(t, map[string]any{"id": "order1", "items": float64(2)}, query("get_order"))
+	}, time.Minute)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"id": "order1"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, "complete", query("get_status"))
+}
+
+func TestListenValidation(t *testing.T) {
+	tests := []struct {
+		Name  string
+		With  string
+		Error string
+	}{
+		{
+			Name: "Valid",
+			With: `type: query
+              select: ${ .data }`,
+		},
+		{
+			Name: "Not an expression",
+			With: `type: query
+              select: .data`,
+			Error: "listen task select must be a runtime expression: event",
+		},
+		{
+			Name: "Not a query",
+			With: `type: update
+              select: ${ .data }`,
+			Error: "listen task select is only supported by queries: event",
+		},
+		{
+			Name: "With data",
+			With: `type: query
+              select: ${ .data }
+              data:
+                hello: world`,
+			Error: "listen task cannot set both select and data: event",
+		},
+		{
+			Name: "Update when",
+			With: `type: update
+              when: ${ .data.event > 0 }`,
+		},
+		{
+			Name: "Not an update",
+			With: `type: query
+              when: ${ .data.event > 0 }`,
+			Error: "listen task schema and when are only supported by updates: event",
+		},
+		{
+			Name: "When not an expression",
+			With: `type: update
+              when: true`,
+			Error: "listen task when must be a runtime expression: event",
+		},
+		{
+			Name: "Invalid schema",
+			With: `type: update
+              schema: number`,
+			Error: "invalid listen task schema",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: query
+  version: 0.0.1
+do:
+  - listener:
+      listen:
+        to:
+          one:
+            with:
+              id: event
+              `+test.With)
+
+			builder, err := NewDoTaskBuilder(nil, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc, DoTaskOpts{
+				DisableRegisterWorkflow: true,
+			})
+			assert.NoError(t, err)
+
+			_, err = builder.Build()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, test.Error)
+		})
+	}
+}
+
+func TestListenUpdateValidator(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Temperature any
+		Rejected    bool
+	}{
+		{
+			Name:        "Accepted",
+			Temperature: 39.5,
+		},
+		{
+			Name:        "Out of range",
+			Temperature: 60,
+			Rejected:    true,
+		},
+		{
+			Name:        "Not a number",
+			Temperature: "hot",
+			Rejected:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: update
+  version: 0.0.1
+do:
+  - callDoctor:
+      listen:
+        to:
+          one:
+            with:
+              id: temperature
+              type: update
+              schema:
+                format: json
+                document:
+                  type: number
+              when: ${ .data.temperature >= 30 and .data.temperature <= 45 }
+              data: ${ .data.temperature > 38 }`)
+			env := newTestEnvironment(t, doc)
+
+			var rejected error
+			var accepted bool
+			var result any
+			env.RegisterDelayedCallback(func() {
+				env.UpdateWorkflow("temperature", "", &testsuite.TestUpdateCallback{
+					OnReject: func(err error) {
+						rejected = err
+					},
+					OnAccept: func() {
+						accepted = true
+					},
+					OnComplete: func(res any, err error) {
+						assert.NoError(t, err)
+						result = res
+					},
+				}, test.Temperature)
+			}, time.Second)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Rejected {
+				assert.Error(t, rejected)
+				assert.False(t, accepted)
+				// Nothing else will complete the listener
+				assert.ErrorContains(t, env.GetWorkflowError(), "timeout")
+				return
+			}
+
+			assert.NoError(t, rejected)
+			assert.True(t, accepted)
+			assert.Equal(t, true, result)
+			assert.NoError(t, env.GetWorkflowError())
+		})
+	}
+}
+
+func TestListenQueryAndSignal(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Strategy string
+	}{
+		{
+			Name:     "All",
+			Strategy: "all",
+		},
+		{
+			Name:     "Any",
+			Strategy: "any",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: listen
+  version: 0.0.1
+do:
+  - approval:
+      metadata:
+        timeout: 1h
+      listen:
+        to:
+          `+test.Strategy+`:
+            - with:
+                id: get_status
+                type: query
+                select: ${ .data.status }
+            - with:
+                id: approve
+                type: signal
+  - result:
+      export:
+        as: result
+      set:
+        approval: ${ .data.approval }`)
+			env := newTestEnvironment(t, doc)
+
+			env.RegisterDelayedCallback(func() {
+				assert.False(t, env.IsWorkflowCompleted())
+
+				res, err := env.QueryWorkflow("get_status")
+				assert.NoError(t, err)
+
+				var status string
+				assert.NoError(t, res.Get(&status))
+				assert.Equal(t, "waiting", status)
+
+				env.SignalWorkflow("approve", map[string]any{"approved": true})
+			}, 10*time.Minute)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, utils.NewState().AddData(map[string]any{
+				"status": "waiting",
+			}))
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, map[string]any{
+				"result": map[string]any{
+					"approval": map[string]any{"approved": true},
+				},
+			}, result)
+		})
+	}
+}
+
+func TestListenQueryAndSignalBeforeAwait(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: listen
+  version: 0.0.1
+do:
+  - approval:
+      metadata:
+        timeout: 1h
+      listen:
+        to:
+          all:
+            - with:
+                id: get_status
+                type: query
+                select: ${ .data.status }
+            - with:
+                id: approve
+                type: signal
+  - result:
+      export:
+        as: result
+      set:
+        approval: ${ .data.approval }`)
+	env := newTestEnvironment(t, doc)
+
+	// Workflows started before the change don't wait for the signal
+	env.OnGetVersion(listenQueryAwaitChangeID, workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"result": map[string]any{
+			"approval": nil,
+		},
+	}, result)
+}
+
+func TestListenSignalBeforeListening(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: listen
+  version: 0.0.1
+do:
+  - setup:
+      wait:
+        minutes: 1
+  - approval:
+      metadata:
+        timeout: 1s
+      listen:
+        to:
+          one:
+            with:
+              id: approve
+              type: signal
+  - result:
+      export:
+        as: result
+      set:
+        approval: ${ .data.approval }`)
+	env := newTestEnvironment(t, doc)
+
+	// Sent before the workflow listens for it, as with signal with start
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("approve", map[string]any{"approved": true})
+	}, time.Second)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"result": map[string]any{
+			"approval": map[string]any{"approved": true},
+		},
+	}, result)
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// NewLoopTaskBuilder creates a builder for a do task with "while" metadata,
+// which repeats its tasks
+func NewLoopTaskBuilder(
+	temporalWorker worker.Worker,
+	task *model.DoTask,
+	taskName string,
+	doc *model.Workflow,
+) (*LoopTaskBuilder, error) {
+	// The loop is registered as the workflow instead of its tasks
+	do, err := NewDoTaskBuilder(temporalWorker, task, taskName, doc, DoTaskOpts{
+		DisableRegisterWorkflow: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoopTaskBuilder{
+		builder: builder[*model.DoTask]{
+			doc:            doc,
+			name:           taskName,
+			task:           task,
+			temporalWorker: temporalWorker,
+		},
+		do: do,
+	}, nil
+}
+
+type LoopTaskBuilder struct {
+	builder[*model.DoTask]
+
+	do *DoTaskBuilder
+}
+
+// setInheritedOptions also sets the options on the loop's tasks
+func (t *LoopTaskBuilder) setInheritedOptions(opts inheritedOptions) {
+	t.inheritedOptions = opts
+	t.do.setInheritedOptions(opts)
+}
+
+type loopOptions struct {
+	While         *model.RuntimeExpression
+	MaxIterations int
+	Delay         time.Duration
+}
+
+func (t *LoopTaskBuilder) Build() (TemporalWorkflowFunc, error) {
+	opts, err := t.loopOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := t.do.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	wf := func(ctx workflow.Context, input any, state *utils.State) (any, error) {
+		logger := workflow.GetLogger(ctx)
+
+		for iteration := 1; ; iteration++ {
+			logger.Debug("Running loop iteration", "task", t.GetTaskName(), "iteration", iteration)
+
+			output, err := fn(ctx, input, state)
+			if err != nil {
+				return nil, err
+			}
+
+			// The condition is checked after each iteration, so it can use
+			// the iteration's output
+			repeat, err := utils.CheckIfStatement(opts.While, state.WithResult(output))
+			if err != nil {
+				logger.Error("Error checking loop while", "task", t.GetTaskName(), "error", err)
+				return nil, fmt.Errorf("error checking loop while: %w", err)
+			}
+			if !repeat {
+				logger.Debug("Loop while responded false - stopping", "task", t.GetTaskName(), "iterations", iteration)
+				// The output is the state's output, so it can't be exported
+				// into itself
+				return swUtil.DeepCloneValue(output), nil
+			}
+
+			if iteration >= opts.MaxIterations {
+				logger.Error("Loop reached its maximum iterations", "task", t.GetTaskName(), "maxIterations", opts.MaxIterations)
+				return nil, temporal.NewNonRetryableApplicationError(
+					fmt.Sprintf("Loop task %s reached its maximum of %d iterations", t.GetTaskName(), opts.MaxIterations),
+					"Loop",
+					nil,
+				)
+			}
+
+			if opts.Delay > 0 {
+				if err := workflow.Sleep(ctx, opts.Delay); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// Register the loop in the same way as a do task
+	if slices.ContainsFunc(*t.task.Do, func(task *model.TaskItem) bool {
+		return task.AsDoTask() == nil
+	}) {
+		registerWorkflow(t.temporalWorker, t.GetTaskName(), wf)
+	}
+
+	return wf, nil
+}
+
+func (t *LoopTaskBuilder) PostLoad() error {
+	if _, err := t.loopOptions(); err != nil {
+		return err
+	}
+
+	return t.do.PostLoad()
+}
+
+// loopOptions parses the loop's metadata
+func (t *LoopTaskBuilder) loopOptions() (*loopOptions, error) {
+	while, err := metadata.GetWhile(t.task.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid while metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	maxIterations, err := metadata.GetMaxIterations(t.task.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max iterations metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	delay, err := metadata.GetIterationDelay(t.task.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iteration delay metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	return &loopOptions{
+		While:         model.NewExpr(while),
+		MaxIterations: maxIterations,
+		Delay:         delay,
+	}, nil
+}
+
+// isLoop returns true if the do task repeats its tasks
+func isLoop(task *model.DoTask) bool {
+	_, ok := task.Metadata[metadata.MetadataWhile]
+	return ok
+}
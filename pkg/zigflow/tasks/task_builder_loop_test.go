@@ -0,0 +1,157 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestLoopConditionBecomesFalse(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: loop
+  version: 0.0.1
+do:
+  - poll:
+      export:
+        as: poll
+      metadata:
+        while: ${ .result.status.count < 3 }
+        iterationDelay: 10s
+      do:
+        - status:
+            export:
+              as: status
+            set:
+              count: ${ (.data.count // 0) + 1 }
+  - result:
+      export:
+        as: count
+      set:
+        count: ${ .data.count }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"status": map[string]any{"count": float64(3)},
+	}, result["poll"])
+	assert.Equal(t, map[string]any{"count": float64(3)}, result["count"])
+}
+
+func TestLoopMaxIterations(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: loop
+  version: 0.0.1
+do:
+  - poll:
+      metadata:
+        while: ${ true }
+        maxIterations: 3
+      do:
+        - status:
+            set:
+              count: ${ (.data.count // 0) + 1 }
+  - unreachable:
+      set:
+        done: true`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+
+	err := env.GetWorkflowError()
+	assert.ErrorContains(t, err, "Loop task poll reached its maximum of 3 iterations")
+
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.True(t, appErr.NonRetryable())
+}
+
+func TestLoopValidation(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata map[string]any
+		Error    string
+	}{
+		{
+			Name: "valid",
+			Metadata: map[string]any{
+				"while":          "${ .data.ready != true }",
+				"maxIterations":  10,
+				"iterationDelay": "5s",
+			},
+		},
+		{
+			Name: "not an expression",
+			Metadata: map[string]any{
+				"while": "true",
+			},
+			Error: "invalid while metadata for task poll: while must be a runtime expression",
+		},
+		{
+			Name: "no iterations",
+			Metadata: map[string]any{
+				"while":         "${ true }",
+				"maxIterations": 0,
+			},
+			Error: "invalid max iterations metadata for task poll: max iterations must be at least 1",
+		},
+		{
+			Name: "negative delay",
+			Metadata: map[string]any{
+				"while":          "${ true }",
+				"iterationDelay": "-1s",
+			},
+			Error: "invalid iteration delay metadata for task poll: iteration delay must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			builder, err := NewTaskBuilder("poll", &model.DoTask{
+				TaskBase: model.TaskBase{
+					Metadata: test.Metadata,
+				},
+				Do: &model.TaskList{},
+			}, &testWorker{}, nil)
+			assert.NoError(t, err)
+			assert.IsType(t, &LoopTaskBuilder{}, builder)
+
+			err = builder.PostLoad()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+}
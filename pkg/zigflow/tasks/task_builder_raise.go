@@ -17,11 +17,11 @@
 package tasks
 
 import (
-	"context"
+	"errors"
 	"fmt"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
-	"github.com/serverlessworkflow/sdk-go/v3/impl/expr"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
@@ -53,86 +53,109 @@ const (
 	temporaErrlNonRetryable = "https://temporal.io/errors/nonretryable"
 )
 
-// Serverless Workflow native errors
-var raiseErrFuncMapping = map[string]func(error, string) *model.Error{
-	model.ErrorTypeAuthentication: model.NewErrAuthentication,
-	model.ErrorTypeValidation:     model.NewErrValidation,
-	model.ErrorTypeCommunication:  model.NewErrCommunication,
-	model.ErrorTypeAuthorization:  model.NewErrAuthorization,
-	model.ErrorTypeConfiguration:  model.NewErrConfiguration,
-	model.ErrorTypeExpression:     model.NewErrExpression,
-	model.ErrorTypeRuntime:        model.NewErrRuntime,
-	model.ErrorTypeTimeout:        model.NewErrTimeout,
-}
-
-var temporalErrMapping = map[string]func(error, string) error{
-	// Special Temporal types
-	goPanic: func(_ error, msg string) error {
-		panic(msg)
+// Special Temporal types, keyed by the error type
+var temporalErrMapping = map[string]func(*ErrorEnvelope) error{
+	goPanic: func(raised *ErrorEnvelope) error {
+		panic(raised.Instance)
 	},
-	temporaErrlNonRetryable: func(err error, msg string) error {
-		return temporal.NewNonRetryableApplicationError(msg, temporaErrlNonRetryable, err)
+	temporaErrlNonRetryable: func(raised *ErrorEnvelope) error {
+		return temporal.NewNonRetryableApplicationError(raised.Instance, temporaErrlNonRetryable, errors.New(raised.Message()), raised)
 	},
 }
 
+func (t *RaiseTaskBuilder) PostLoad() error {
+	if _, err := metadata.GetRetryable(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid retryable metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	_, err := t.errorDefinition()
+	return err
+}
+
 func (t *RaiseTaskBuilder) Build() (TemporalWorkflowFunc, error) {
-	return func(ctx workflow.Context, input any, state *utils.State) (_ any, err error) {
+	definition, err := t.errorDefinition()
+	if err != nil {
+		return nil, err
+	}
+
+	retryable, err := metadata.GetRetryable(t.task.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retryable metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
 		logger := workflow.GetLogger(ctx)
 		logger.Debug("Raising error")
 
-		gtx := context.Background()
-		info := workflow.GetInfo(ctx)
-		instanceID := info.WorkflowExecution.ID
-
-		var raiseErr *model.Error
-		var titleResult any = ""
-		var detailResult any = ""
-
-		if definition := t.task.Raise.Error.Definition; definition != nil {
-			if detail := definition.Title; detail != nil {
-				detailResult, err = expr.TraverseAndEvaluateObj(
-					detail.AsObjectOrRuntimeExpr(),
-					state,
-					t.GetTaskName(),
-					gtx,
-				)
-				if err != nil {
-					logger.Error("Error finding error definition", "error", err)
-					err = fmt.Errorf("error finding error definition: %w", err)
-					return nil, err
-				}
-			}
-
-			if title := definition.Title; title != nil {
-				titleResult, err = expr.TraverseAndEvaluateObj(
-					t.task.Raise.Error.Definition.Title.AsObjectOrRuntimeExpr(),
-					state,
-					t.GetTaskName(),
-					gtx,
-				)
-				if err != nil {
-					logger.Error("Error finding error title definition", "error", err)
-					err = fmt.Errorf("error finding error title definition: %w", err)
-					return nil, err
-				}
-			}
-
-			if raiseErrF, ok := raiseErrFuncMapping[definition.Type.String()]; ok {
-				raiseErr = raiseErrF(fmt.Errorf("%v", detailResult), instanceID)
-			} else if temporalErrF, ok := temporalErrMapping[definition.Title.String()]; ok {
-				return nil, temporalErrF(fmt.Errorf("%v", detailResult), instanceID)
-			} else {
-				raiseErr = definition
-				raiseErr.Detail = model.NewStringOrRuntimeExpr(fmt.Sprintf("%v", detailResult))
-				raiseErr.Instance = &model.JsonPointerOrRuntimeExpression{
-					Value: instanceID,
-				}
-			}
-
-			raiseErr.Title = model.NewStringOrRuntimeExpr(fmt.Sprintf("%v", titleResult))
-			raiseErr.Status = definition.Status
+		raised, err := t.interpolateError(definition, state)
+		if err != nil {
+			logger.Error("Error interpolating error definition", "error", err)
+			return nil, fmt.Errorf("error interpolating error definition: %w", err)
 		}
+		raised.Instance = workflow.GetInfo(ctx).WorkflowExecution.ID
 
-		return nil, raiseErr
+		if temporalErrF, ok := temporalErrMapping[raised.Type]; ok {
+			return nil, temporalErrF(raised)
+		}
+
+		// The error type is the DSL type so a catch can filter on it
+		return nil, temporal.NewApplicationErrorWithOptions(raised.Message(), raised.Type, temporal.ApplicationErrorOptions{
+			NonRetryable: !retryable,
+			Details:      []any{raised},
+		})
 	}, nil
 }
+
+// errorDefinition returns the error to raise, resolving any reference to the
+// document's reusable errors
+func (t *RaiseTaskBuilder) errorDefinition() (*model.Error, error) {
+	raiseErr := t.task.Raise.Error
+	if raiseErr.Definition != nil {
+		return raiseErr.Definition, nil
+	}
+
+	if raiseErr.Ref == nil {
+		return nil, fmt.Errorf("unknown error to raise in task %s", t.GetTaskName())
+	}
+
+	if t.doc != nil && t.doc.Use != nil {
+		if definition, ok := t.doc.Use.Errors[*raiseErr.Ref]; ok && definition != nil {
+			return definition, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown error %s to raise in task %s", *raiseErr.Ref, t.GetTaskName())
+}
+
+// interpolateError evaluates the title and detail against the state. The
+// definition is shared between workflow runs so must not be modified.
+func (t *RaiseTaskBuilder) interpolateError(definition *model.Error, state *utils.State) (*ErrorEnvelope, error) {
+	raised := &ErrorEnvelope{
+		Status: definition.Status,
+	}
+	if definition.Type != nil {
+		raised.Type = definition.Type.String()
+	}
+
+	fields := map[string]any{}
+	if definition.Title != nil {
+		fields["title"] = definition.Title.String()
+	}
+	if definition.Detail != nil {
+		fields["detail"] = definition.Detail.String()
+	}
+
+	res, err := utils.TraverseAndEvaluateObj(model.NewObjectOrRuntimeExpr(fields), state)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := res["title"]; ok {
+		raised.Title = fmt.Sprintf("%v", v)
+	}
+	if v, ok := res["detail"]; ok {
+		raised.Detail = fmt.Sprintf("%v", v)
+	}
+
+	return raised, nil
+}
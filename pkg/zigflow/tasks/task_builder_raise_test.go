@@ -0,0 +1,227 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestRaise(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Error    string
+		Expected *ErrorEnvelope
+	}{
+		{
+			Name: "Interpolated definition",
+			Error: `
+          type: https://serverlessworkflow.io/spec/1.0.0/errors/validation
+          status: 400
+          title: ${ "Invalid user " + .input.userId }
+          detail: ${ .input.reason }`,
+			Expected: &ErrorEnvelope{
+				Type:     "https://serverlessworkflow.io/spec/1.0.0/errors/validation",
+				Status:   400,
+				Title:    "Invalid user abc123",
+				Detail:   "Name is required",
+				Instance: "default-test-workflow-id",
+			},
+		},
+		{
+			Name:  "Reference",
+			Error: " notFound",
+			Expected: &ErrorEnvelope{
+				Type:     "https://example.com/errors/not-found",
+				Status:   404,
+				Title:    "Not found",
+				Instance: "default-test-workflow-id",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: raise
+  version: 0.0.1
+use:
+  errors:
+    notFound:
+      type: https://example.com/errors/not-found
+      status: 404
+      title: Not found
+do:
+  - fail:
+      raise:
+        error:`+test.Error)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{
+				"userId": "abc123",
+				"reason": "Name is required",
+			}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			var appErr *temporal.ApplicationError
+			assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+			assert.Equal(t, test.Expected.Type, appErr.Type())
+			assert.Equal(t, test.Expected.Message(), appErr.Message())
+
+			var details ErrorEnvelope
+			assert.NoError(t, appErr.Details(&details))
+			assert.Equal(t, *test.Expected, details)
+		})
+	}
+}
+
+func TestRaiseCaught(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: raise
+  version: 0.0.1
+do:
+  - attempt:
+      export:
+        as: result
+      try:
+        - fail:
+            raise:
+              error:
+                type: https://serverlessworkflow.io/spec/1.0.0/errors/validation
+                status: 400
+                detail: ${ "Invalid user " + .input.userId }
+      catch:
+        do:
+          - recover:
+              export:
+                as: caught
+              set:
+                type: ${ .data.error.type }
+                detail: ${ .data.error.details.detail }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"userId": "abc123"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]any{
+		"result": map[string]any{
+			"caught": map[string]any{
+				"type":   "https://serverlessworkflow.io/spec/1.0.0/errors/validation",
+				"detail": "Invalid user abc123",
+			},
+		},
+	}, result)
+}
+
+func TestRaiseRetryable(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Metadata     string
+		NonRetryable bool
+	}{
+		{
+			Name: "Default",
+		},
+		{
+			Name: "Retryable",
+			Metadata: `
+      metadata:
+        retryable: true`,
+		},
+		{
+			Name: "Non-retryable",
+			Metadata: `
+      metadata:
+        retryable: false`,
+			NonRetryable: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: raise
+  version: 0.0.1
+do:
+  - fail:`+test.Metadata+`
+      raise:
+        error:
+          type: https://serverlessworkflow.io/spec/1.0.0/errors/validation
+          status: 400`)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			var appErr *temporal.ApplicationError
+			assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+			assert.Equal(t, test.NonRetryable, appErr.NonRetryable())
+		})
+	}
+}
+
+func TestRaiseRetryableValidation(t *testing.T) {
+	builder, err := NewRaiseTaskBuilder(nil, &model.RaiseTask{
+		TaskBase: model.TaskBase{
+			Metadata: map[string]any{
+				metadata.MetadataRetryable: "no",
+			},
+		},
+	}, "fail", nil)
+	assert.NoError(t, err)
+
+	assert.EqualError(t, builder.PostLoad(), "invalid retryable metadata for task fail: retryable must be a boolean")
+}
+
+func TestRaiseReferenceValidation(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: raise
+  version: 0.0.1
+use:
+  errors:
+    notFound:
+      type: https://example.com/errors/not-found
+      status: 404
+do:
+  - fail:
+      raise:
+        error: paymentDeclined`)
+
+	builder, err := NewDoTaskBuilder(&testWorker{}, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "unknown error paymentDeclined to raise in task fail")
+}
@@ -18,14 +18,23 @@ package tasks
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	swUtil "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
 )
 
+// registeredWorkflows are the names of the workflows registered by the do
+// task builders. A run task's workflow name that's set at runtime must be one
+// of these, so a typo fails the task rather than starting a child workflow
+// that no worker can run.
+var registeredWorkflows sync.Map
+
 func NewRunTaskBuilder(
 	temporalWorker worker.Worker,
 	task *model.RunTask,
@@ -50,6 +59,13 @@ func (t *RunTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
 		logger := workflow.GetLogger(ctx)
 
+		if t.task.Run.Script != nil {
+			return t.runScript(ctx, input, state)
+		}
+		if t.task.Run.Container != nil {
+			return t.runContainer(ctx, input, state)
+		}
+
 		if t.task.Run.Workflow == nil {
 			return nil, fmt.Errorf("unsupported run task: %s", t.GetTaskName())
 		}
@@ -65,24 +81,123 @@ func (t *RunTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 	}, nil
 }
 
+func (t *RunTaskBuilder) PostLoad() error {
+	if t.task.Run.Script != nil {
+		if err := t.validateScript(); err != nil {
+			return err
+		}
+	}
+	if t.task.Run.Container != nil {
+		if err := t.validateContainer(); err != nil {
+			return err
+		}
+	}
+	if _, err := metadata.GetOutputFormat(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid output format metadata for task %s: %w", t.GetTaskName(), err)
+	}
+	if _, err := metadata.GetWorkflowID(t.task.Metadata); err != nil {
+		return fmt.Errorf("error validating run task %s: %w", t.GetTaskName(), err)
+	}
+	if _, err := metadata.GetWorkflowIDReusePolicy(t.task.Metadata); err != nil {
+		return fmt.Errorf("error validating run task %s: %w", t.GetTaskName(), err)
+	}
+	if _, err := metadata.GetParentClosePolicy(t.task.Metadata); err != nil {
+		return fmt.Errorf("error validating run task %s: %w", t.GetTaskName(), err)
+	}
+	return nil
+}
+
+// parentClosePolicy returns the parent close policy from the metadata. If
+// not set, a child workflow that isn't awaited is abandoned so it outlives its
+// parent, otherwise Temporal's default of terminating it is used.
+func (t *RunTaskBuilder) parentClosePolicy(await bool) (enums.ParentClosePolicy, error) {
+	policy, err := metadata.GetParentClosePolicy(t.task.Metadata)
+	if err != nil {
+		return policy, newValidationError("Invalid parent close policy", err, t.GetTaskName())
+	}
+
+	if policy == enums.PARENT_CLOSE_POLICY_UNSPECIFIED && !await {
+		policy = enums.PARENT_CLOSE_POLICY_ABANDON
+	}
+
+	return policy, nil
+}
+
+// childWorkflowOptions sets the workflow ID and reuse policy from the metadata.
+// The workflow ID is evaluated as a side effect so it's deterministic.
+func (t *RunTaskBuilder) childWorkflowOptions(ctx workflow.Context, state *utils.State) (workflow.ChildWorkflowOptions, error) {
+	opts := workflow.ChildWorkflowOptions{
+		Priority:      taskPriority(ctx),
+		StaticSummary: taskSummary(ctx),
+	}
+
+	policy, err := metadata.GetWorkflowIDReusePolicy(t.task.Metadata)
+	if err != nil {
+		return opts, newValidationError("Invalid workflow id reuse policy", err, t.GetTaskName())
+	}
+	opts.WorkflowIDReusePolicy = policy
+
+	id, err := metadata.GetWorkflowID(t.task.Metadata)
+	if err != nil {
+		return opts, newValidationError("Invalid workflow id", err, t.GetTaskName())
+	}
+	if id == "" {
+		// Let Temporal generate the ID
+		return opts, nil
+	}
+
+	res, err := utils.EvaluateString(id, state, func(fn func() (any, error)) (any, error) {
+		return t.sideEffectWrapper(ctx, fn)
+	})
+	if err != nil {
+		return opts, fmt.Errorf("error parsing child workflow id: %w", err)
+	}
+
+	workflowID, ok := res.(string)
+	if !ok || workflowID == "" {
+		return opts, newValidationError("Child workflow id must be a non-empty string", nil, t.GetTaskName())
+	}
+	opts.WorkflowID = workflowID
+
+	return opts, nil
+}
+
 func (t *RunTaskBuilder) runWorkflow(ctx workflow.Context, input any, state *utils.State) (any, error) {
 	logger := workflow.GetLogger(ctx)
 	logger.Debug("Running a child workflow", "task", t.GetTaskName())
 
 	await := *t.task.Run.Await
 
-	opts := workflow.ChildWorkflowOptions{}
-	if !await {
-		opts.ParentClosePolicy = enums.PARENT_CLOSE_POLICY_ABANDON
+	opts, err := t.childWorkflowOptions(ctx, state)
+	if err != nil {
+		logger.Error("Error creating child workflow options", "error", err)
+		return nil, err
+	}
+	opts.ParentClosePolicy, err = t.parentClosePolicy(await)
+	if err != nil {
+		logger.Error("Error getting parent close policy", "error", err)
+		return nil, err
 	}
 
 	ctx = workflow.WithChildOptions(ctx, opts)
 
-	future := workflow.ExecuteChildWorkflow(ctx, t.task.Run.Workflow.Name, input, state)
+	childInput, childState, err := t.mapInput(ctx, input, state)
+	if err != nil {
+		logger.Error("Error mapping child workflow input", "error", err)
+		return nil, err
+	}
+
+	name, err := t.workflowName(ctx, state)
+	if err != nil {
+		logger.Error("Error getting child workflow name", "error", err)
+		return nil, err
+	}
+
+	future := executeChildWorkflow(ctx, name, childInput, childState)
 
 	if !await {
 		logger.Warn("Not waiting for child workspace response", "task", t.GetTaskName())
-		return nil, nil
+		return t.childReference(ctx, future)
 	}
 
 	var res any
@@ -94,3 +209,77 @@ func (t *RunTaskBuilder) runWorkflow(ctx workflow.Context, input any, state *uti
 
 	return res, nil
 }
+
+// workflowName returns the name of the child workflow. This can be a runtime
+// expression, which is evaluated as a side effect so it's deterministic. A
+// name set at runtime must be registered by this worker.
+func (t *RunTaskBuilder) workflowName(ctx workflow.Context, state *utils.State) (string, error) {
+	name := t.task.Run.Workflow.Name
+	if !model.IsStrictExpr(name) {
+		return name, nil
+	}
+
+	res, err := utils.EvaluateString(name, state, func(fn func() (any, error)) (any, error) {
+		return t.sideEffectWrapper(ctx, fn)
+	})
+	if err != nil {
+		return "", fmt.Errorf("error parsing child workflow name: %w", err)
+	}
+
+	resolved, ok := res.(string)
+	if !ok || resolved == "" {
+		return "", newValidationError("Child workflow name must be a non-empty string", nil, t.GetTaskName())
+	}
+	if _, ok := registeredWorkflows.Load(resolved); !ok {
+		return "", newValidationError(fmt.Sprintf("Child workflow %s is not registered", resolved), nil, t.GetTaskName())
+	}
+
+	workflow.GetLogger(ctx).Debug("Resolved child workflow name", "task", t.GetTaskName(), "name", resolved)
+
+	return resolved, nil
+}
+
+// childReference waits for a fire-and-forget child workflow to start and
+// returns a reference to it in place of the result, so later tasks can find
+// the child workflow.
+func (t *RunTaskBuilder) childReference(ctx workflow.Context, future workflow.ChildWorkflowFuture) (map[string]any, error) {
+	var execution workflow.Execution
+	if err := future.GetChildWorkflowExecution().Get(ctx, &execution); err != nil {
+		workflow.GetLogger(ctx).Error("Error starting child workflow", "error", err)
+		return nil, fmt.Errorf("error starting child workflow: %w", err)
+	}
+
+	return map[string]any{
+		"workflowId": execution.ID,
+		"runId":      execution.RunID,
+	}, nil
+}
+
+// mapInput interpolates the run.workflow.input against the state, which is
+// given to the child workflow as its input. If no input is set, the parent's
+// input and state are passed through unchanged.
+func (t *RunTaskBuilder) mapInput(ctx workflow.Context, input any, state *utils.State) (any, *utils.State, error) {
+	if t.task.Run.Workflow.Input == nil {
+		return input, state, nil
+	}
+
+	logger := workflow.GetLogger(ctx)
+	logger.Debug("Mapping child workflow input", "task", t.GetTaskName())
+
+	mapped, err := utils.TraverseAndEvaluateObj(
+		model.NewObjectOrRuntimeExpr(swUtil.DeepClone(t.task.Run.Workflow.Input)),
+		state,
+		func(fn func() (any, error)) (any, error) {
+			return t.sideEffectWrapper(ctx, fn)
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing child workflow input: %w", err)
+	}
+
+	// The child workflow's state should reflect the input it receives
+	childState := state.Clone()
+	childState.Input = mapped
+
+	return mapped, childState, nil
+}
@@ -0,0 +1,564 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestRunWorkflowInput(t *testing.T) {
+	parentInput := map[string]any{
+		"user": map[string]any{
+			"id":   "some-id",
+			"name": "some-name",
+		},
+		"other": "value",
+	}
+
+	tests := []struct {
+		Name        string
+		ParentInput any
+		Input       string
+		Expected    any
+	}{
+		{
+			Name:     "No input forwards parent input",
+			Expected: parentInput,
+		},
+		{
+			Name:        "No input forwards parent array input",
+			ParentInput: []any{"a", map[string]any{"b": "c"}},
+			Expected:    []any{"a", map[string]any{"b": "c"}},
+		},
+		{
+			Name: "Mapped input",
+			Input: `
+          input:
+            userId: ${ .input.user.id }`,
+			Expected: map[string]any{
+				"userId": "some-id",
+			},
+		},
+		{
+			Name: "Mapped array and object input",
+			Input: `
+          input:
+            ids:
+              - ${ .input.user.id }
+              - static
+            user: ${ .input.user }
+            names: ${ [ .input.user.name, .input.other ] }`,
+			Expected: map[string]any{
+				"ids": []any{"some-id", "static"},
+				"user": map[string]any{
+					"id":   "some-id",
+					"name": "some-name",
+				},
+				"names": []any{"some-name", "value"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: parent
+  version: 0.0.1
+do:
+  - child:
+      run:
+        workflow:
+          namespace: default
+          name: child
+          version: 0.0.1`+test.Input)
+			env := newTestEnvironment(t, doc)
+
+			var childInput, childStateInput any
+			env.RegisterWorkflowWithOptions(func(ctx workflow.Context, input any, state *utils.State) (any, error) {
+				childInput = input
+				childStateInput = state.Input
+				return nil, nil
+			}, workflow.RegisterOptions{Name: "child"})
+
+			input := test.ParentInput
+			if input == nil {
+				input = parentInput
+			}
+
+			env.ExecuteWorkflow(doc.Document.Name, input, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			assert.Equal(t, test.Expected, childInput)
+			assert.Equal(t, test.Expected, childStateInput)
+		})
+	}
+}
+
+func TestRunWorkflowResult(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Await bool
+	}{
+		{
+			Name:  "Awaited",
+			Await: true,
+		},
+		{
+			Name: "Fire and forget",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: parent
+  version: 0.0.1
+do:
+  - child:
+      run:
+        await: %t
+        workflow:
+          namespace: default
+          name: child
+          version: 0.0.1
+  - result:
+      export:
+        as: child
+      set:
+        data: ${ .data.child }`, test.Await))
+			env := newTestEnvironment(t, doc)
+
+			env.RegisterWorkflowWithOptions(func(ctx workflow.Context, _ any, _ *utils.State) (any, error) {
+				return map[string]any{"hello": "world"}, nil
+			}, workflow.RegisterOptions{Name: "child"})
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]map[string]map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+
+			data := result["child"]["data"]
+			if test.Await {
+				assert.Equal(t, map[string]any{"hello": "world"}, data)
+			} else {
+				assert.NotEmpty(t, data["workflowId"])
+				assert.NotEmpty(t, data["runId"])
+				assert.NotContains(t, data, "hello")
+			}
+		})
+	}
+}
+
+func TestRunWorkflowID(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: parent
+  version: 0.0.1
+do:
+  - child:
+      metadata:
+        workflowId: ${ "order-" + .input.orderId }
+        workflowIdReusePolicy: RejectDuplicate
+      run:
+        workflow:
+          namespace: default
+          name: child
+          version: 0.0.1`)
+	env := newTestEnvironment(t, doc)
+
+	var childID string
+	env.RegisterWorkflowWithOptions(func(ctx workflow.Context, _ any, _ *utils.State) (any, error) {
+		childID = workflow.GetInfo(ctx).WorkflowExecution.ID
+		return nil, nil
+	}, workflow.RegisterOptions{Name: "child"})
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"orderId": "1234"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, "order-1234", childID)
+}
+
+func TestRunWorkflowIDReusePolicyValidation(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: parent
+  version: 0.0.1
+do:
+  - child:
+      metadata:
+        workflowIdReusePolicy: SometimesDuplicate
+      run:
+        workflow:
+          namespace: default
+          name: child
+          version: 0.0.1`)
+
+	builder, err := NewDoTaskBuilder(nil, &model.DoTask{Do: doc.Do}, doc.Document.Name, doc)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "invalid workflow id reuse policy")
+}
+
+func TestRunParentClosePolicy(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Policy   string
+		Await    bool
+		Expected enums.ParentClosePolicy
+		Error    bool
+	}{
+		{
+			Name:     "Awaited by default",
+			Await:    true,
+			Expected: enums.PARENT_CLOSE_POLICY_UNSPECIFIED,
+		},
+		{
+			Name:     "Fire and forget by default",
+			Expected: enums.PARENT_CLOSE_POLICY_ABANDON,
+		},
+		{
+			Name:     "Fire and forget terminated",
+			Policy:   "terminate",
+			Expected: enums.PARENT_CLOSE_POLICY_TERMINATE,
+		},
+		{
+			Name:     "Fire and forget cancelled",
+			Policy:   "request-cancel",
+			Expected: enums.PARENT_CLOSE_POLICY_REQUEST_CANCEL,
+		},
+		{
+			Name:     "Awaited and abandoned",
+			Policy:   "abandon",
+			Await:    true,
+			Expected: enums.PARENT_CLOSE_POLICY_ABANDON,
+		},
+		{
+			Name:     "Temporal name",
+			Policy:   "RequestCancel",
+			Expected: enums.PARENT_CLOSE_POLICY_REQUEST_CANCEL,
+		},
+		{
+			Name:   "Invalid",
+			Policy: "ignore",
+			Error:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			task := &model.RunTask{}
+			if test.Policy != "" {
+				task.Metadata = map[string]any{
+					"parentClosePolicy": test.Policy,
+				}
+			}
+
+			builder, err := NewRunTaskBuilder(nil, task, "child", nil)
+			assert.NoError(t, err)
+
+			policy, err := builder.parentClosePolicy(test.Await)
+			if test.Error {
+				assert.ErrorContains(t, err, "Invalid parent close policy")
+				assert.ErrorContains(t, builder.PostLoad(), "invalid parent close policy")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, builder.PostLoad())
+			assert.Equal(t, test.Expected, policy)
+		})
+	}
+}
+
+func TestRunWorkflowDynamicName(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Handler  any
+		Expected any
+		Error    string
+	}{
+		{
+			Name:    "First workflow",
+			Handler: "standardOrder",
+			Expected: map[string]any{
+				"handledBy": "standard",
+			},
+		},
+		{
+			Name:    "Second workflow",
+			Handler: "expressOrder",
+			Expected: map[string]any{
+				"handledBy": "express",
+			},
+		},
+		{
+			Name:    "Not registered",
+			Handler: "missingOrder",
+			Error:   "Child workflow missingOrder is not registered",
+		},
+		{
+			Name:    "Empty",
+			Handler: "",
+			Error:   "Child workflow name must be a non-empty string",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: orders
+  version: 0.0.1
+do:
+  - dispatch:
+      do:
+        - handle:
+            export:
+              as: result
+            run:
+              workflow:
+                namespace: default
+                name: ${ .input.handler }
+                version: 0.0.1
+  - standardOrder:
+      do:
+        - handle:
+            export:
+              as: handledBy
+            set:
+              handledBy: standard
+  - expressOrder:
+      do:
+        - handle:
+            export:
+              as: handledBy
+            set:
+              handledBy: express`)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow("dispatch", map[string]any{"handler": test.Handler}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if test.Error != "" {
+				assert.ErrorContains(t, env.GetWorkflowError(), test.Error)
+				return
+			}
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, map[string]any{"handledBy": test.Expected}, result["result"])
+		})
+	}
+}
+
+func TestRunInlineWorkflow(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: orders
+  version: 0.0.1
+  metadata:
+    workflows:
+      greet:
+        - hello:
+            export:
+              as: greeting
+            set:
+              greeting: ${ "Hello " + .input.name }
+do:
+  - first:
+      export:
+        as: first
+      run:
+        workflow:
+          namespace: default
+          name: greet
+          version: 0.0.1
+          input:
+            name: ${ .input.first }
+  - second:
+      export:
+        as: second
+      run:
+        workflow:
+          namespace: default
+          name: greet
+          version: 0.0.1
+          input:
+            name: ${ .input.second }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"first": "Ziggy", "second": "Temporal"}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	// The child workflow receives the parent's state, so only check its greeting
+	assert.Equal(t, map[string]any{
+		"greeting": map[string]any{"greeting": "Hello Ziggy"},
+	}, result["first"])
+	// The second child workflow also receives the first one's result
+	assert.Equal(t, map[string]any{"greeting": "Hello Temporal"}, result["second"].(map[string]any)["greeting"])
+}
+
+func TestInlineWorkflowValidation(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Workflows string
+		Error     string
+	}{
+		{
+			Name: "Valid",
+			Workflows: `
+      greet:
+        - hello:
+            set:
+              hello: world`,
+		},
+		{
+			Name: "Same name as the document",
+			Workflows: `
+      orders:
+        - hello:
+            set:
+              hello: world`,
+			Error: "inline workflow orders has the same name as the document or a do task",
+		},
+		{
+			Name: "Same name as a do task",
+			Workflows: `
+      dispatch:
+        - hello:
+            set:
+              hello: world`,
+			Error: "inline workflow dispatch has the same name as the document or a do task",
+		},
+		{
+			Name: "No tasks",
+			Workflows: `
+      greet: []`,
+			Error: "invalid workflows metadata: workflow greet must have at least one task",
+		},
+		{
+			Name:      "Not an object",
+			Workflows: ` greet`,
+			Error:     "invalid workflows metadata: workflows must be an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: orders
+  version: 0.0.1
+  metadata:
+    workflows:`+test.Workflows+`
+do:
+  - dispatch:
+      do:
+        - hello:
+            set:
+              hello: world`)
+
+			builders, err := NewInlineWorkflowBuilders(&testWorker{}, doc)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, builders, 1)
+		})
+	}
+}
+
+func TestRunInlineWorkflowArgs(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: orders
+  version: 0.0.1
+  metadata:
+    workflows:
+      summarise:
+        - summary:
+            export:
+              as: summary
+            set:
+              count: ${ .input.items | length }
+              customer: ${ .input.customer.name }
+              currency: ${ .data.currency }
+do:
+  - prepare:
+      set:
+        currency: GBP
+  - child:
+      export:
+        as: child
+      run:
+        workflow:
+          namespace: default
+          name: summarise
+          version: 0.0.1
+          input:
+            items: ${ .input.items }
+            customer:
+              name: ${ .input.name }`)
+	env := newTestEnvironment(t, doc)
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{
+		"name":  "Ziggy",
+		"items": []any{"a", "b", "c"},
+	}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result map[string]any
+	assert.NoError(t, env.GetWorkflowResult(&result))
+	// The child receives the mapped input and a copy of the parent's state
+	assert.Equal(t, map[string]any{
+		"count":    float64(3),
+		"customer": "Ziggy",
+		"currency": "GBP",
+	}, result["child"].(map[string]any)["summary"])
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
 	swUtils "github.com/serverlessworkflow/sdk-go/v3/impl/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"go.temporal.io/sdk/worker"
@@ -46,6 +47,52 @@ type SetTaskBuilder struct {
 	builder[*model.SetTask]
 }
 
+func (t *SetTaskBuilder) PostLoad() error {
+	if _, _, err := metadata.GetUnset(t.task.Metadata); err != nil {
+		return fmt.Errorf("invalid unset metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	switch t.mergeStrategy() {
+	case metadata.MergeShallow, metadata.MergeDeep:
+		return nil
+	default:
+		return fmt.Errorf("unknown merge strategy for task %s: %v", t.GetTaskName(), t.task.Metadata[metadata.MetadataMerge])
+	}
+}
+
+// unsetData removes data from the state. This happens after the set object is
+// evaluated, so the removed data can still be used in its expressions. The
+// workflow information is kept if all the data is removed.
+func (t *SetTaskBuilder) unsetData(ctx workflow.Context, state *utils.State) error {
+	all, keys, err := metadata.GetUnset(t.task.Metadata)
+	if err != nil {
+		return fmt.Errorf("invalid unset metadata for task %s: %w", t.GetTaskName(), err)
+	}
+
+	logger := workflow.GetLogger(ctx)
+	if all {
+		logger.Debug("Removing all data from the state")
+		state.ClearData().AddWorkflowInfo(ctx)
+	} else if len(keys) > 0 {
+		logger.Debug("Removing data from the state", "keys", keys)
+		state.RemoveData(keys...)
+	}
+
+	return nil
+}
+
+// mergeStrategy returns how the result is added to the state - by default, the
+// top-level keys are replaced
+func (t *SetTaskBuilder) mergeStrategy() string {
+	v, ok := t.task.Metadata[metadata.MetadataMerge]
+	if !ok {
+		return metadata.MergeShallow
+	}
+
+	s, _ := v.(string)
+	return s
+}
+
 func (t *SetTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
 		logger := workflow.GetLogger(ctx)
@@ -65,35 +112,19 @@ func (t *SetTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 			return nil, fmt.Errorf("error parsing set object :%w", err)
 		}
 
+		if err := t.unsetData(ctx, state); err != nil {
+			return nil, err
+		}
+
 		// Add the result to the state's data
-		logger.Debug("Setting data to the state")
-		state.AddData(result)
+		if t.mergeStrategy() == metadata.MergeDeep {
+			logger.Debug("Deep merging data to the state")
+			state.MergeData(result)
+		} else {
+			logger.Debug("Setting data to the state")
+			state.AddData(result)
+		}
 
 		return result, nil
 	}, nil
 }
-
-// sideEffectWrapper creates a wrapper function for the Runtime Expression traversal to ensure that
-// the generated values are set deterministically. For many things, this might be considered overkill
-// as input/envvars/state are likely to be determinstic. However, as this also supports things like
-// generation of UUIDs, there could be non-deterministic values being set.
-func (t *SetTaskBuilder) sideEffectWrapper(ctx workflow.Context, fn func() (any, error)) (any, error) {
-	var val any
-	var sideEffectErr error
-	err := workflow.SideEffect(ctx, func(ctx workflow.Context) any {
-		res, err := fn()
-		if err != nil {
-			sideEffectErr = err
-			return nil
-		}
-		return res
-	}).Get(&val)
-	if err != nil {
-		return nil, fmt.Errorf("error running side effect: %w", err)
-	}
-	if sideEffectErr != nil {
-		return nil, fmt.Errorf("error running runtime expression: %w", sideEffectErr)
-	}
-
-	return val, nil
-}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMerge(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Merge    string
+		Expected map[string]any
+	}{
+		{
+			Name:  "Shallow",
+			Merge: "shallow",
+			Expected: map[string]any{
+				"address": map[string]any{
+					"city": "London",
+				},
+			},
+		},
+		{
+			Name:  "Deep",
+			Merge: "deep",
+			Expected: map[string]any{
+				"name": "Test",
+				"address": map[string]any{
+					"country": "UK",
+					"city":    "London",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: set
+  version: 0.0.1
+do:
+  - first:
+      set:
+        user:
+          name: Test
+          address:
+            country: UK
+  - second:
+      metadata:
+        merge: %s
+      set:
+        user:
+          address:
+            city: London
+  - result:
+      export:
+        as: result
+      set:
+        user: ${ .data.user }`, test.Merge))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result["result"]["user"])
+		})
+	}
+}
+
+func TestSetMergeValidation(t *testing.T) {
+	builder, err := NewSetTaskBuilder(nil, &model.SetTask{
+		TaskBase: model.TaskBase{
+			Metadata: map[string]any{
+				"merge": "sideways",
+			},
+		},
+	}, "set", nil)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "unknown merge strategy")
+}
+
+func TestSetUnset(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Unset    string
+		Expected map[string]any
+	}{
+		{
+			Name:  "Keys",
+			Unset: "[secret, missing]",
+			Expected: map[string]any{
+				"consumed": "s3cr3t",
+				"keep":     true,
+			},
+		},
+		{
+			Name:  "All",
+			Unset: "true",
+			Expected: map[string]any{
+				"consumed": "s3cr3t",
+			},
+		},
+		{
+			Name:  "None",
+			Unset: "false",
+			Expected: map[string]any{
+				"consumed": "s3cr3t",
+				"keep":     true,
+				"secret":   "s3cr3t",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, fmt.Sprintf(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: set
+  version: 0.0.1
+do:
+  - first:
+      set:
+        keep: true
+        secret: s3cr3t
+  - second:
+      metadata:
+        unset: %s
+      set:
+        consumed: ${ .data.secret }
+  - result:
+      export:
+        as: result
+      set:
+        data: ${ .data | del(.workflow, .task) }
+        hasWorkflow: ${ .data.workflow != null }`, test.Unset))
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result["result"]["data"])
+			assert.Equal(t, true, result["result"]["hasWorkflow"])
+		})
+	}
+}
+
+func TestSetUnsetValidation(t *testing.T) {
+	builder, err := NewSetTaskBuilder(nil, &model.SetTask{
+		TaskBase: model.TaskBase{
+			Metadata: map[string]any{
+				"unset": "secret",
+			},
+		},
+	}, "set", nil)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, builder.PostLoad(), "invalid unset metadata for task set: unset must be a boolean or a list of keys")
+}
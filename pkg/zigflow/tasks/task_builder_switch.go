@@ -18,6 +18,8 @@ package tasks
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
@@ -45,51 +47,113 @@ type SwitchTaskBuilder struct {
 	builder[*model.SwitchTask]
 }
 
-func (t *SwitchTaskBuilder) Build() (TemporalWorkflowFunc, error) {
-	hasDefault := false
+// workflowNames returns the names of the do tasks in the document, which are
+// available to run as child workflows
+func (t *SwitchTaskBuilder) workflowNames() []string {
+	names := make([]string, 0)
+	if t.doc == nil {
+		return names
+	}
+
+	utils.WalkTasks(t.doc.Do, func(_ string, task *model.TaskItem) {
+		if task.AsDoTask() != nil {
+			names = append(names, task.Key)
+		}
+	})
+
+	return names
+}
+
+type switchCase struct {
+	Name string
+	Case model.SwitchCase
+}
+
+// orderCases returns the cases in declaration order with the default case
+// (the one without a "when") moved to the end so it's always evaluated last.
+// If there are multiple matching cases, the first declared always wins.
+func (t *SwitchTaskBuilder) orderCases() ([]switchCase, error) {
+	cases := make([]switchCase, 0)
+	var defaultCase *switchCase
+
 	for i, switchItem := range t.task.Switch {
-		for name, item := range switchItem {
+		// Each item should only have one case, but sort them in case there
+		// are more so they're always evaluated in the same order
+		for _, name := range slices.Sorted(maps.Keys(switchItem)) {
+			item := switchItem[name]
 			if item.When == nil {
-				if hasDefault {
+				if defaultCase != nil {
 					return nil, fmt.Errorf("multiple switch statements without when: %s.%d.%s", t.GetTaskName(), i, name)
 				}
-				hasDefault = true
+				defaultCase = &switchCase{Name: name, Case: item}
+				continue
 			}
+			cases = append(cases, switchCase{Name: name, Case: item})
 		}
 	}
 
+	if defaultCase != nil {
+		cases = append(cases, *defaultCase)
+	}
+
+	return cases, nil
+}
+
+// Build evaluates each case in turn, running the first that matches. If no
+// case matches and there is no default, nothing is run and the workflow
+// continues to the next task, as per the specification. The cases are
+// evaluated against the full state, so can use the results of earlier tasks.
+//
+// If the "then" names a do task, that is executed as a child workflow.
+// Otherwise, it's treated as a flow directive and the parent do list jumps to
+// the sibling task or terminates.
+func (t *SwitchTaskBuilder) Build() (TemporalWorkflowFunc, error) {
+	cases, err := t.orderCases()
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := t.workflowNames()
+
 	return func(ctx workflow.Context, input any, state *utils.State) (any, error) {
 		logger := workflow.GetLogger(ctx)
 
-		for _, switchItem := range t.task.Switch {
-			for name, item := range switchItem {
-				logger.Debug("Checking if we should run this switch statement", "task", t.GetTaskName(), "condition", name)
+		for _, c := range cases {
+			name, item := c.Name, c.Case
+			logger.Debug("Checking if we should run this switch statement", "task", t.GetTaskName(), "condition", name)
 
-				if shouldRun, err := utils.CheckIfStatement(item.When, state); err != nil {
-					return nil, err
-				} else if !shouldRun {
-					logger.Debug("Skipping switch statement task", "task", t.GetTaskName(), "condition", name)
-					continue
-				}
+			if shouldRun, err := utils.CheckIfStatement(item.When, state); err != nil {
+				return nil, err
+			} else if !shouldRun {
+				logger.Debug("Skipping switch statement task", "task", t.GetTaskName(), "condition", name)
+				continue
+			}
 
-				then := item.Then
-				if then == nil || then.IsTermination() {
-					logger.Debug("Skipping task as then is termination or not set")
-					return nil, nil
-				}
+			then := item.Then
+			if then == nil || then.Value == string(model.FlowDirectiveContinue) {
+				logger.Debug("Skipping task as then is continue or not set")
+				return nil, nil
+			}
 
-				logger.Info("Executing switch statement's task as a child workflow", "task", t.GetTaskName(), "condition", name)
-				var res any
-				if err := workflow.ExecuteChildWorkflow(ctx, then.Value, input, state).Get(ctx, &res); err != nil {
-					logger.Error("Error executing child switch workflow", "task", t.GetTaskName(), "condition", name)
-					return nil, err
-				}
+			if then.IsEnum() || !slices.Contains(workflows, then.Value) {
+				// Let the parent's do list handle the flow
+				logger.Debug("Switch statement targets a flow directive", "task", t.GetTaskName(), "then", then.Value)
+				return &flowDirective{Then: then}, nil
+			}
 
-				// Stop it executing anything else
-				return nil, nil
+			logger.Info("Executing switch statement's task as a child workflow", "task", t.GetTaskName(), "condition", name)
+			var res any
+			if err := executeChildWorkflow(ctx, then.Value, input, state).Get(ctx, &res); err != nil {
+				logger.Error("Error executing child switch workflow", "task", t.GetTaskName(), "condition", name)
+				return nil, err
 			}
+
+			// Stop it executing anything else
+			return nil, nil
 		}
 
+		logger.Debug("No switch statement matched and no default set - continuing", "task", t.GetTaskName())
+
 		return nil, nil
 	}, nil
 }
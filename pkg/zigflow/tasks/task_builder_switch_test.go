@@ -0,0 +1,318 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwitchDefaultCase(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Cases    string
+		Value    string
+		Expected string
+	}{
+		{
+			Name: "Default declared first is not matched before conditionals",
+			Cases: `
+        - fallback:
+            then: defaultTask
+        - matched:
+            when: ${ .input.value == "match" }
+            then: matchedTask`,
+			Value:    "match",
+			Expected: "matched",
+		},
+		{
+			Name: "Default declared first runs when nothing matches",
+			Cases: `
+        - fallback:
+            then: defaultTask
+        - matched:
+            when: ${ .input.value == "match" }
+            then: matchedTask`,
+			Value:    "other",
+			Expected: "default",
+		},
+		{
+			Name: "No match and no default continues",
+			Cases: `
+        - matched:
+            when: ${ .input.value == "match" }
+            then: matchedTask`,
+			Value:    "other",
+			Expected: "none",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: switch
+  version: 0.0.1
+do:
+  - check:
+      switch:`+test.Cases+`
+  - noneTask:
+      export:
+        as: ran
+      set:
+        task: none
+      then: end
+  - defaultTask:
+      export:
+        as: ran
+      set:
+        task: default
+      then: end
+  - matchedTask:
+      export:
+        as: ran
+      set:
+        task: matched
+      then: end`)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{"value": test.Value}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result["ran"]["task"])
+		})
+	}
+}
+
+func TestSwitchFlowDirective(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Value    string
+		Expected map[string]any
+	}{
+		{
+			Name:  "Jump to sibling task",
+			Value: "jump",
+			Expected: map[string]any{
+				"target": map[string]any{"ran": "target"},
+			},
+		},
+		{
+			Name:     "End",
+			Value:    "end",
+			Expected: map[string]any{},
+		},
+		{
+			Name:  "Continue",
+			Value: "continue",
+			Expected: map[string]any{
+				"skipped": map[string]any{"ran": "skipped"},
+				"target":  map[string]any{"ran": "target"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: switch
+  version: 0.0.1
+do:
+  - check:
+      switch:
+        - jump:
+            when: ${ .input.value == "jump" }
+            then: target
+        - end:
+            when: ${ .input.value == "end" }
+            then: end
+        - default:
+            then: continue
+  - skipped:
+      export:
+        as: skipped
+      set:
+        ran: skipped
+  - target:
+      export:
+        as: target
+      set:
+        ran: target`)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, map[string]any{"value": test.Value}, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+}
+
+func TestSwitchOnTaskResult(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Status   string
+		Expected map[string]any
+	}{
+		{
+			Name:   "Approved",
+			Status: "approved",
+			Expected: map[string]any{
+				"outcome": map[string]any{"approved": true},
+			},
+		},
+		{
+			Name:   "Rejected",
+			Status: "rejected",
+			Expected: map[string]any{
+				"outcome": map[string]any{"approved": false},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder(http.MethodGet, "https://example.com/approval", func(*http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"status": test.Status})
+			})
+
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: switch
+  version: 0.0.1
+do:
+  - getApproval:
+      call: http
+      with:
+        method: get
+        endpoint: https://example.com/approval
+  - check:
+      switch:
+        - approved:
+            when: ${ .data.getApproval.status == "approved" }
+            then: approve
+        - default:
+            then: reject
+  - approve:
+      export:
+        as: outcome
+      set:
+        approved: true
+      then: end
+  - reject:
+      export:
+        as: outcome
+      set:
+        approved: false`)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+}
+
+func TestSwitchMultipleMatches(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Cases    string
+		Expected string
+	}{
+		{
+			Name: "First declared case wins",
+			Cases: `        - second:
+            when: ${ .input.value > 1 }
+            then: setSecond
+        - first:
+            when: ${ .input.value > 0 }
+            then: setFirst`,
+			Expected: "second",
+		},
+		{
+			Name: "Multiple cases in an item are sorted",
+			Cases: `        - second:
+            when: ${ .input.value > 1 }
+            then: setSecond
+          first:
+            when: ${ .input.value > 0 }
+            then: setFirst`,
+			Expected: "first",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			// Run it several times as map iteration is random
+			for range 10 {
+				doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: switch
+  version: 0.0.1
+do:
+  - check:
+      switch:
+`+test.Cases+`
+  - setFirst:
+      export:
+        as: winner
+      set:
+        name: first
+      then: end
+  - setSecond:
+      export:
+        as: winner
+      set:
+        name: second`)
+				env := newTestEnvironment(t, doc)
+
+				env.ExecuteWorkflow(doc.Document.Name, map[string]any{"value": 2}, nil)
+
+				assert.True(t, env.IsWorkflowCompleted())
+				assert.NoError(t, env.GetWorkflowError())
+
+				var result map[string]map[string]any
+				assert.NoError(t, env.GetWorkflowResult(&result))
+				assert.Equal(t, test.Expected, result["winner"]["name"])
+			}
+		})
+	}
+}
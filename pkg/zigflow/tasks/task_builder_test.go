@@ -22,7 +22,11 @@ import (
 	"github.com/mrsimonemms/zigflow/pkg/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
 )
 
 // mockTask implements model.Task for testing purposes
@@ -103,3 +107,201 @@ func TestShouldRun(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTaskTypes(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: task-types
+  version: 0.0.1
+  metadata:
+    workflows:
+      notify:
+        - supported:
+            set:
+              hello: world
+        - emitNotification:
+            emit:
+              event:
+                with:
+                  source: https://example.com
+                  type: com.example.notification
+do:
+  - supported:
+      set:
+        hello: world
+  - emitEvent:
+      emit:
+        event:
+          with:
+            source: https://example.com
+            type: com.example.event
+  - parent:
+      do:
+        - grpc:
+            call: grpc
+            with:
+              proto:
+                endpoint: https://example.com/greet.proto
+              service:
+                name: Greeter
+                host: localhost
+              method: SayHello
+  - attempt:
+      try:
+        - set:
+            set:
+              hello: world
+      catch:
+        do:
+          - emitFailure:
+              emit:
+                event:
+                  with:
+                    source: https://example.com
+                    type: com.example.failure
+  - branches:
+      fork:
+        branches:
+          - emitBranch:
+              emit:
+                event:
+                  with:
+                    source: https://example.com
+                    type: com.example.branch`)
+
+	res := ValidateTaskTypes(doc)
+
+	keys := make([]string, 0)
+	for _, r := range res {
+		keys = append(keys, r.Key)
+	}
+
+	assert.Equal(t, []string{
+		"emitEvent",
+		"parent.grpc",
+		"attempt.catch.emitFailure",
+		"branches.emitBranch",
+		"workflows.notify.emitNotification",
+	}, keys)
+	assert.Contains(t, res[0].Message, "unsupported task type")
+}
+
+func TestParseMetadataSearchAttributes(t *testing.T) {
+	doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: searchAttributes
+  version: 0.0.1
+do:
+  - user:
+      metadata:
+        searchAttributes:
+          userId:
+            type: int
+            value: ${ .input.userId }
+          source:
+            type: keyword
+            value: zigflow
+      set:
+        hello: world`)
+	env := newTestEnvironment(t, doc)
+
+	env.OnUpsertTypedSearchAttributes(temporal.NewSearchAttributes(
+		temporal.NewSearchAttributeKeyInt64("userId").ValueSet(42),
+		temporal.NewSearchAttributeKeyKeyword("source").ValueSet("zigflow"),
+	)).Return(nil).Once()
+
+	env.ExecuteWorkflow(doc.Document.Name, map[string]any{"userId": 42}, nil)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+// sideEffectCounter counts the side effects recorded by the workflow
+type sideEffectCounter struct {
+	interceptor.WorkerInterceptorBase
+
+	count int
+}
+
+func (c *sideEffectCounter) InterceptWorkflow(
+	_ workflow.Context, next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	return &sideEffectInbound{WorkflowInboundInterceptorBase: interceptor.WorkflowInboundInterceptorBase{Next: next}, counter: c}
+}
+
+type sideEffectInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+
+	counter *sideEffectCounter
+}
+
+func (i *sideEffectInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	return i.Next.Init(&sideEffectOutbound{
+		WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound},
+		counter:                         i.counter,
+	})
+}
+
+type sideEffectOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+
+	counter *sideEffectCounter
+}
+
+func (o *sideEffectOutbound) SideEffect(ctx workflow.Context, f func(ctx workflow.Context) any) converter.EncodedValue {
+	o.counter.count++
+	return o.Next.SideEffect(ctx, f)
+}
+
+func TestParseMetadataSideEffects(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Metadata string
+		Expected int
+	}{
+		{
+			Name:     "No metadata",
+			Metadata: "{}",
+		},
+		{
+			Name:     "No search attributes",
+			Metadata: "{ description: Says hello, priority: 1 }",
+		},
+		{
+			// Interpolating the attributes and checking they're registered
+			Name:     "Search attributes",
+			Metadata: "{ searchAttributes: { source: { type: keyword, value: zigflow } } }",
+			Expected: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: sideEffects
+  version: 0.0.1
+do:
+  - step:
+      metadata: `+test.Metadata+`
+      wait:
+        seconds: 1`)
+			env := newTestEnvironment(t, doc)
+
+			counter := &sideEffectCounter{}
+			env.SetWorkerOptions(worker.Options{
+				Interceptors: []interceptor.WorkerInterceptor{counter},
+			})
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+			assert.Equal(t, test.Expected, counter.count)
+		})
+	}
+}
@@ -17,15 +17,26 @@
 package tasks
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"time"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
 	"github.com/rs/zerolog/log"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
 )
 
+// defaultCatchAs is the state data key the caught error is stored under if
+// catch.as is not set
+const defaultCatchAs = "error"
+
 func NewTryTaskBuilder(
 	temporalWorker worker.Worker,
 	task *model.TryTask,
@@ -72,6 +83,20 @@ func (t *TryTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 }
 
 func (t *TryTaskBuilder) PostLoad() error {
+	if retry := t.task.Catch.Retry; retry != nil && retry.Ref != "" {
+		var retries map[string]*model.RetryPolicy
+		if t.doc != nil && t.doc.Use != nil {
+			retries = t.doc.Use.Retries
+		}
+
+		if err := retry.ResolveReference(retries); err != nil {
+			return fmt.Errorf("error resolving retry policy for %s: %w", t.GetTaskName(), err)
+		}
+	}
+	if err := t.validateRetryDurations(); err != nil {
+		return err
+	}
+
 	for taskType, list := range t.getTasks() {
 		_, builder, err := t.createBuilder(taskType, list)
 		if err != nil {
@@ -91,22 +116,34 @@ func (t *TryTaskBuilder) exec() (TemporalWorkflowFunc, error) {
 	return func(ctx workflow.Context, input any, state *utils.State) (output any, err error) {
 		logger := workflow.GetLogger(ctx)
 
-		opts := workflow.ChildWorkflowOptions{
-			WorkflowID: fmt.Sprintf("%s_try", workflow.GetInfo(ctx).WorkflowExecution.ID),
-		}
-		childCtx := workflow.WithChildOptions(ctx, opts)
-
 		var res map[string]any
-		if err := workflow.ExecuteChildWorkflow(childCtx, t.tryChildWorkflowName, state.Input, state).Get(ctx, &res); err != nil {
+		if err := t.runTry(ctx, state, &res); err != nil {
+			if !t.catches(err) {
+				logger.Debug("Error doesn't match the catch filter", "error", err)
+				return nil, err
+			}
+
 			logger.Warn("Workflow failed, catching the error", "tryWorkflow", t.tryChildWorkflowName, "catchWorkflow", t.catchChildWorkflowName)
 			// The try workflow has failed - let's run the catch workflow
 			opts := workflow.ChildWorkflowOptions{
-				WorkflowID: fmt.Sprintf("%s_catch", workflow.GetInfo(ctx).WorkflowExecution.ID),
+				WorkflowID:    fmt.Sprintf("%s_catch", t.childWorkflowIDPrefix(ctx)),
+				Priority:      taskPriority(ctx),
+				StaticSummary: taskSummary(ctx),
 			}
 
 			childCtx := workflow.WithChildOptions(ctx, opts)
 
-			if err := workflow.ExecuteChildWorkflow(childCtx, t.catchChildWorkflowName, state.Input, state).Get(ctx, &res); err != nil {
+			// Make the caught error available to the catch tasks
+			catchAs := t.task.Catch.As
+			if catchAs == "" {
+				catchAs = defaultCatchAs
+			}
+			logger.Debug("Adding caught error to state", "key", catchAs)
+			state.AddData(map[string]any{
+				catchAs: newCaughtError(err),
+			})
+
+			if err := executeChildWorkflow(childCtx, t.catchChildWorkflowName, state.Input, state).Get(ctx, &res); err != nil {
 				// Everything has failed
 				logger.Error("Error calling try workflow", "error", err)
 				return nil, fmt.Errorf("error calling catcg workflow: %w", err)
@@ -117,10 +154,180 @@ func (t *TryTaskBuilder) exec() (TemporalWorkflowFunc, error) {
 	}, nil
 }
 
-func (t *TryTaskBuilder) getTasks() map[string]*model.TaskList {
-	return map[string]*model.TaskList{
-		"try":   t.task.Try,
-		"catch": t.task.Catch.Do,
+// runTry runs the try workflow. If there is a retry policy, the try workflow
+// is run again until it succeeds or the retry limits are reached.
+func (t *TryTaskBuilder) runTry(ctx workflow.Context, state *utils.State, res *map[string]any) error {
+	logger := workflow.GetLogger(ctx)
+
+	retry := t.task.Catch.Retry
+	workflowID := fmt.Sprintf("%s_try", t.childWorkflowIDPrefix(ctx))
+	start := workflow.Now(ctx)
+
+	for attempt := 1; ; attempt++ {
+		opts := workflow.ChildWorkflowOptions{
+			WorkflowID:    workflowID,
+			Priority:      taskPriority(ctx),
+			StaticSummary: taskSummary(ctx),
+		}
+		if attempt > 1 {
+			opts.WorkflowID = fmt.Sprintf("%s_%d", workflowID, attempt)
+		}
+		childCtx := workflow.WithChildOptions(ctx, opts)
+
+		err := executeChildWorkflow(childCtx, t.tryChildWorkflowName, state.Input, state).Get(ctx, res)
+		if err == nil || retry == nil || temporal.IsCanceledError(err) || !t.catches(err) {
+			return err
+		}
+
+		if ok, retryErr := t.canRetry(retry, attempt, workflow.Now(ctx).Sub(start)); retryErr != nil {
+			return retryErr
+		} else if !ok {
+			logger.Warn("Try retries exhausted", "task", t.GetTaskName(), "attempts", attempt)
+			return err
+		}
+
+		delay, delayErr := t.retryDelay(ctx, retry, attempt)
+		if delayErr != nil {
+			return delayErr
+		}
+
+		logger.Info("Retrying try workflow", "task", t.GetTaskName(), "attempt", attempt, "delay", delay, "error", err)
+		if err := workflow.Sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// catches checks the error matches the catch.errors.with filter. Every field
+// set on the filter must match the error, so an empty filter catches anything.
+// The type matches either the Temporal error type or the envelope's type.
+func (t *TryTaskBuilder) catches(err error) bool {
+	filter := t.task.Catch.Errors.With
+	if filter == nil {
+		return true
+	}
+
+	errType, _ := newCaughtError(err)["type"].(string)
+
+	var envelope ErrorEnvelope
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) && appErr.HasDetails() {
+		// An error without an envelope only matches on the type
+		_ = appErr.Details(&envelope)
+	}
+
+	switch {
+	case filter.Type != "" && filter.Type != errType && filter.Type != envelope.Type:
+		return false
+	case filter.Status != 0 && filter.Status != envelope.Status:
+		return false
+	case filter.Instance != "" && filter.Instance != envelope.Instance:
+		return false
+	case filter.Title != "" && filter.Title != envelope.Title:
+		return false
+	case filter.Details != "" && filter.Details != envelope.Detail:
+		return false
+	}
+
+	return true
+}
+
+// canRetry checks the retry limits. The attempt count is the number of retries
+// allowed after the initial attempt.
+func (t *TryTaskBuilder) canRetry(retry *model.RetryPolicy, attempt int, elapsed time.Duration) (bool, error) {
+	limit := retry.Limit
+
+	if limit.Duration != nil {
+		maxDuration, err := utils.ToDuration(limit.Duration)
+		if err != nil {
+			return false, fmt.Errorf("invalid retry limit duration: %w", err)
+		}
+		if elapsed >= maxDuration {
+			return false, nil
+		}
+	}
+
+	maxRetries := 0
+	if limit.Attempt != nil {
+		maxRetries = limit.Attempt.Count
+	}
+	if maxRetries == 0 && limit.Duration == nil {
+		maxRetries = defaultTryRetryAttempts
+	}
+
+	return maxRetries == 0 || attempt <= maxRetries, nil
+}
+
+// validateRetryDurations checks the retry policy's durations can be converted
+func (t *TryTaskBuilder) validateRetryDurations() error {
+	retry := t.task.Catch.Retry
+	if retry == nil {
+		return nil
+	}
+
+	durations := []*model.Duration{retry.Delay, retry.Limit.Duration}
+	if retry.Jitter != nil {
+		durations = append(durations, retry.Jitter.From, retry.Jitter.To)
+	}
+	for _, d := range durations {
+		if _, err := utils.ToDuration(d); err != nil {
+			return fmt.Errorf("invalid retry policy for %s: %w", t.GetTaskName(), err)
+		}
+	}
+
+	return nil
+}
+
+// retryDelay calculates how long to wait before the next attempt
+func (t *TryTaskBuilder) retryDelay(ctx workflow.Context, retry *model.RetryPolicy, attempt int) (time.Duration, error) {
+	delay, err := utils.ToDuration(retry.Delay)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retry delay: %w", err)
+	}
+
+	if backoff := retry.Backoff; backoff != nil {
+		switch {
+		case backoff.Linear != nil:
+			delay *= time.Duration(attempt)
+		case backoff.Exponential != nil:
+			delay *= time.Duration(math.Pow(2, float64(attempt-1)))
+		}
+	}
+
+	if jitter := retry.Jitter; jitter != nil && jitter.From != nil && jitter.To != nil {
+		from, err := utils.ToDuration(jitter.From)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retry jitter: %w", err)
+		}
+		to, err := utils.ToDuration(jitter.To)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retry jitter: %w", err)
+		}
+
+		if to > from {
+			// Random values must be generated as a side effect to remain deterministic
+			var j time.Duration
+			if err := workflow.SideEffect(ctx, func(ctx workflow.Context) any {
+				//nolint:gosec // Cryptographic randomness not required for jitter
+				return from + time.Duration(rand.Int64N(int64(to-from)))
+			}).Get(&j); err != nil {
+				return 0, fmt.Errorf("error generating retry jitter: %w", err)
+			}
+			delay += j
+		}
+	}
+
+	return delay, nil
+}
+
+// getTasks returns the try and catch task lists, in that order, so the child
+// workflows are always registered in the same order
+func (t *TryTaskBuilder) getTasks() iter.Seq2[string, *model.TaskList] {
+	return func(yield func(string, *model.TaskList) bool) {
+		if !yield("try", t.task.Try) {
+			return
+		}
+		yield("catch", t.task.Catch.Do)
 	}
 }
 
@@ -136,7 +343,7 @@ func (t *TryTaskBuilder) createBuilder(
 
 	childWorkflowName = utils.GenerateChildWorkflowName(taskType, t.GetTaskName())
 
-	b, err := NewTaskBuilder(childWorkflowName, &model.DoTask{Do: list}, t.temporalWorker, t.doc)
+	b, err := t.newTaskBuilder(childWorkflowName, &model.DoTask{Do: list})
 	if err != nil {
 		l.Error().Msg("Error creating the for task builder")
 		err = fmt.Errorf("error creating the for task builder: %w", err)
@@ -147,3 +354,50 @@ func (t *TryTaskBuilder) createBuilder(
 
 	return
 }
+
+// newCaughtError serialises the error into a JSON-compatible map so it can be
+// stored in the state. Only the first detail of an ApplicationError is used
+// and any detail that cannot be decoded is omitted rather than failing the
+// workflow.
+func newCaughtError(err error) map[string]any {
+	caught := map[string]any{
+		"type":    "Error",
+		"message": err.Error(),
+		"details": nil,
+	}
+
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		caught["type"] = appErr.Type()
+		caught["message"] = appErr.Message()
+
+		if appErr.HasDetails() {
+			var details any
+			if err := appErr.Details(&details); err == nil {
+				caught["details"] = toJSONValue(details)
+			}
+		}
+	} else if temporal.IsCanceledError(err) {
+		caught["type"] = ErrorTypeCanceled
+	} else if temporal.IsTimeoutError(err) {
+		caught["type"] = "Timeout"
+	}
+
+	return caught
+}
+
+// toJSONValue converts the value to the types that the JSON decoder would
+// generate. Anything that cannot be converted is returned as nil.
+func toJSONValue(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var res any
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil
+	}
+
+	return res
+}
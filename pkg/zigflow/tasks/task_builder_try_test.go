@@ -0,0 +1,267 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestNewCaughtError(t *testing.T) {
+	type detail struct {
+		Field string `json:"field"`
+		Count int    `json:"count"`
+	}
+
+	tests := []struct {
+		Name     string
+		Err      error
+		Expected map[string]any
+	}{
+		{
+			Name: "Application error with details",
+			Err: temporal.NewApplicationError("some message", "SomeType", detail{
+				Field: "value",
+				Count: 3,
+			}),
+			Expected: map[string]any{
+				"type":    "SomeType",
+				"message": "some message",
+				"details": map[string]any{
+					"field": "value",
+					"count": float64(3),
+				},
+			},
+		},
+		{
+			Name: "Wrapped application error without details",
+			Err:  fmt.Errorf("wrapped: %w", temporal.NewNonRetryableApplicationError("inner", "Validation", nil)),
+			Expected: map[string]any{
+				"type":    "Validation",
+				"message": "inner",
+				"details": nil,
+			},
+		},
+		{
+			Name: "Application error with unserialisable details",
+			Err:  temporal.NewApplicationError("bad details", "BadType", func() {}),
+			Expected: map[string]any{
+				"type":    "BadType",
+				"message": "bad details",
+				"details": nil,
+			},
+		},
+		{
+			Name: "Generic error",
+			Err:  errors.New("generic"),
+			Expected: map[string]any{
+				"type":    "Error",
+				"message": "generic",
+				"details": nil,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, newCaughtError(test.Err))
+		})
+	}
+}
+
+func TestTryRetry(t *testing.T) {
+	tests := []struct {
+		Name             string
+		FailuresBefore   int
+		ExpectedAttempts int
+		ExpectedOutput   map[string]any
+	}{
+		{
+			Name:             "Succeeds on second attempt",
+			FailuresBefore:   1,
+			ExpectedAttempts: 2,
+			ExpectedOutput: map[string]any{
+				"result": map[string]any{
+					"status": map[string]any{
+						"status": "tried",
+					},
+				},
+			},
+		},
+		{
+			Name:             "Retries exhausted runs catch",
+			FailuresBefore:   10,
+			ExpectedAttempts: 3,
+			ExpectedOutput: map[string]any{
+				"result": map[string]any{
+					"status": map[string]any{
+						"status": "caught",
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: retry
+  version: 0.0.1
+do:
+  - attempt:
+      export:
+        as: result
+      try:
+        - flaky:
+            run:
+              workflow:
+                namespace: default
+                name: flaky
+                version: 0.0.1
+        - tried:
+            export:
+              as: status
+            set:
+              status: tried
+      catch:
+        retry:
+          delay:
+            seconds: 5
+          backoff:
+            exponential: {}
+          limit:
+            attempt:
+              count: 2
+        do:
+          - caught:
+              export:
+                as: status
+              set:
+                status: caught`)
+			env := newTestEnvironment(t, doc)
+
+			attempts := 0
+			env.RegisterWorkflowWithOptions(func(ctx workflow.Context, _ any, _ *utils.State) (any, error) {
+				attempts++
+				if attempts <= test.FailuresBefore {
+					return nil, temporal.NewNonRetryableApplicationError("flaky failure", "Flaky", nil)
+				}
+				return nil, nil
+			}, workflow.RegisterOptions{Name: "flaky"})
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+			assert.Equal(t, test.ExpectedAttempts, attempts)
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.ExpectedOutput, result)
+		})
+	}
+}
+
+func TestTryCatchErrorFilter(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Filter string
+		Caught bool
+	}{
+		{
+			Name:   "Matching type and status",
+			Filter: "type: https://example.com/errors/payment-declined\n            status: 402",
+			Caught: true,
+		},
+		{
+			Name:   "Matching title",
+			Filter: "title: Payment declined",
+			Caught: true,
+		},
+		{
+			Name:   "Different type",
+			Filter: "type: https://example.com/errors/not-found",
+		},
+		{
+			Name:   "Different status",
+			Filter: "type: https://example.com/errors/payment-declined\n            status: 500",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			doc := loadWorkflow(t, `document:
+  dsl: 1.0.0
+  namespace: default
+  name: filter
+  version: 0.0.1
+do:
+  - attempt:
+      export:
+        as: result
+      try:
+        - pay:
+            raise:
+              error:
+                type: https://example.com/errors/payment-declined
+                status: 402
+                title: Payment declined
+      catch:
+        errors:
+          with:
+            `+test.Filter+`
+        do:
+          - caught:
+              export:
+                as: status
+              set:
+                status: caught`)
+			env := newTestEnvironment(t, doc)
+
+			env.ExecuteWorkflow(doc.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+
+			if !test.Caught {
+				var appErr *temporal.ApplicationError
+				assert.True(t, errors.As(env.GetWorkflowError(), &appErr))
+				assert.Equal(t, "https://example.com/errors/payment-declined", appErr.Type())
+				return
+			}
+
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, map[string]any{
+				"result": map[string]any{
+					"status": map[string]any{
+						"status": "caught",
+					},
+				},
+			}, result)
+		})
+	}
+}
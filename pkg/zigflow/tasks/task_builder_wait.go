@@ -18,6 +18,7 @@ package tasks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mrsimonemms/zigflow/pkg/utils"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
@@ -47,10 +48,19 @@ type WaitTaskBuilder struct {
 }
 
 func (t *WaitTaskBuilder) Build() (TemporalWorkflowFunc, error) {
-	return func(ctx workflow.Context, _ any, _ *utils.State) (any, error) {
+	return func(ctx workflow.Context, _ any, state *utils.State) (any, error) {
 		logger := workflow.GetLogger(ctx)
 
-		duration := utils.ToDuration(t.task.Wait)
+		duration, err := t.duration(ctx, state)
+		if err != nil {
+			logger.Error("Error calculating wait duration", "error", err)
+			return nil, err
+		}
+
+		if duration <= 0 {
+			logger.Debug("Wait time has already passed - not sleeping", "duration", duration.String())
+			return nil, nil
+		}
 
 		logger.Debug("Sleeping", "duration", duration.String())
 
@@ -66,3 +76,51 @@ func (t *WaitTaskBuilder) Build() (TemporalWorkflowFunc, error) {
 		return nil, nil
 	}, nil
 }
+
+// PostLoad checks the wait is a duration, an RFC3339 timestamp or a runtime
+// expression. Only the wait task can be an absolute time, so this isn't
+// checked by the document's validation.
+func (t *WaitTaskBuilder) PostLoad() error {
+	wait := t.task.Wait
+	if _, err := utils.ToDuration(wait); err == nil {
+		return nil
+	}
+
+	value := wait.AsExpression()
+	if model.IsStrictExpr(value) {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return fmt.Errorf("wait for task %s must be a duration or RFC3339 timestamp: %s", t.GetTaskName(), value)
+	}
+
+	return nil
+}
+
+// duration returns how long to sleep for. The wait can either be a duration or
+// an absolute RFC3339 timestamp, which may be a runtime expression. For a
+// timestamp, this is the time until then and is negative if it has passed.
+func (t *WaitTaskBuilder) duration(ctx workflow.Context, state *utils.State) (time.Duration, error) {
+	if duration, err := utils.ToDuration(t.task.Wait); err == nil {
+		return duration, nil
+	}
+
+	res, err := utils.EvaluateString(t.task.Wait.AsExpression(), state, func(fn func() (any, error)) (any, error) {
+		return t.sideEffectWrapper(ctx, fn)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error parsing wait expression: %w", err)
+	}
+
+	value, ok := res.(string)
+	if !ok {
+		return 0, newValidationError("Wait must resolve to a string", nil, t.GetTaskName())
+	}
+
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, newValidationError("Wait must be a duration or RFC3339 timestamp", err, t.GetTaskName())
+	}
+
+	return until.Sub(workflow.Now(ctx)), nil
+}
@@ -30,13 +30,20 @@ import (
 func TestWaitTaskBuilder(t *testing.T) {
 	tests := []struct {
 		Name     string
-		Duration model.DurationInline
+		Duration *model.Duration
+		Expected time.Duration
 	}{
 		{
 			Name: "10 second delay",
-			Duration: model.DurationInline{
+			Duration: &model.Duration{Value: model.DurationInline{
 				Seconds: 10,
-			},
+			}},
+			Expected: 10 * time.Second,
+		},
+		{
+			Name:     "ISO 8601 duration",
+			Duration: model.NewDurationExpr("PT1M30S"),
+			Expected: 90 * time.Second,
 		},
 	}
 
@@ -48,12 +55,8 @@ func TestWaitTaskBuilder(t *testing.T) {
 			start := time.Now().UTC()
 			env.SetStartTime(start)
 
-			dur := &model.Duration{
-				Value: test.Duration,
-			}
-
 			w, err := tasks.NewWaitTaskBuilder(nil, &model.WaitTask{
-				Wait: dur,
+				Wait: test.Duration,
 			}, test.Name, nil)
 			assert.NoError(t, err)
 
@@ -67,9 +70,109 @@ func TestWaitTaskBuilder(t *testing.T) {
 			assert.NoError(t, env.GetWorkflowError())
 
 			got := env.Now().UTC()
-			want := start.Add(utils.ToDuration(dur))
+			want := start.Add(test.Expected)
 
 			assert.True(t, got.Equal(want))
 		})
 	}
 }
+
+func TestWaitTaskBuilderUntil(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Wait     string
+		Input    any
+		Expected time.Time
+	}{
+		{
+			Name:     "Timestamp",
+			Wait:     "2025-01-02T09:00:00Z",
+			Expected: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Name: "Runtime expression",
+			Wait: "${ .input.resumeAt }",
+			Input: map[string]any{
+				"resumeAt": "2025-01-01T13:30:00+01:00",
+			},
+			Expected: time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			Name:     "Time has passed",
+			Wait:     "2024-12-31T09:00:00Z",
+			Expected: start,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var s testsuite.WorkflowTestSuite
+			env := s.NewTestWorkflowEnvironment()
+			env.SetStartTime(start)
+
+			w, err := tasks.NewWaitTaskBuilder(nil, &model.WaitTask{
+				Wait: model.NewDurationExpr(test.Wait),
+			}, test.Name, nil)
+			assert.NoError(t, err)
+
+			wf, err := w.Build()
+			assert.NoError(t, err)
+
+			env.RegisterWorkflow(wf)
+
+			state := utils.NewState()
+			state.Input = test.Input
+
+			env.ExecuteWorkflow(wf, nil, state)
+
+			assert.NoError(t, env.GetWorkflowError())
+			assert.True(t, env.Now().UTC().Equal(test.Expected), "expected %s, got %s", test.Expected, env.Now().UTC())
+		})
+	}
+}
+
+func TestWaitTaskBuilderPostLoad(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Wait  *model.Duration
+		Error string
+	}{
+		{
+			Name: "Inline",
+			Wait: &model.Duration{Value: model.DurationInline{Seconds: 10}},
+		},
+		{
+			Name: "ISO 8601 duration",
+			Wait: model.NewDurationExpr("PT5S"),
+		},
+		{
+			Name: "Timestamp",
+			Wait: model.NewDurationExpr("2025-01-02T09:00:00Z"),
+		},
+		{
+			Name: "Runtime expression",
+			Wait: model.NewDurationExpr("${ .input.resumeAt }"),
+		},
+		{
+			Name:  "Invalid",
+			Wait:  model.NewDurationExpr("tomorrow"),
+			Error: "wait for task wait must be a duration or RFC3339 timestamp: tomorrow",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w, err := tasks.NewWaitTaskBuilder(nil, &model.WaitTask{Wait: test.Wait}, "wait", nil)
+			assert.NoError(t, err)
+
+			err = w.PostLoad()
+			if test.Error == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.Error)
+			}
+		})
+	}
+}
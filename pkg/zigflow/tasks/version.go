@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// CheckTaskVersion uses Temporal's patching to decide if the task should run.
+// This allows a DSL to change without breaking any workflows that are already
+// running.
+//
+// The recommended pattern is:
+//  1. When adding a task, set metadata.version to a new change ID. Workflows
+//     started before the deploy skip the task, new workflows run it.
+//  2. When replacing a task, give the old task the same change ID with
+//     "replaced: true" so it only runs for workflows started before the deploy.
+//  3. Once no workflows from before the deploy remain, delete any replaced
+//     tasks and set "minSupported: 1" on the new task.
+//
+// The version is stored in the state at ".data.__versions.<changeId>" so it
+// can be used in "if" statements.
+func CheckTaskVersion(ctx workflow.Context, task model.Task, state *utils.State) (bool, error) {
+	version, err := metadata.GetVersion(task.GetBase().Metadata)
+	if err != nil {
+		return false, temporal.NewNonRetryableApplicationError("Invalid version metadata", "Version", err)
+	}
+	if version == nil {
+		// No versioning - always run
+		return true, nil
+	}
+
+	v := workflow.GetVersion(
+		ctx,
+		version.ChangeID,
+		workflow.Version(version.MinSupported),
+		workflow.Version(version.MaxSupported),
+	)
+
+	workflow.GetLogger(ctx).Debug("Task version", "changeId", version.ChangeID, "version", v)
+
+	versions, ok := state.Data[TaskVersionsKey].(map[string]any)
+	if !ok {
+		versions = map[string]any{}
+	}
+	versions[version.ChangeID] = int(v)
+	state.AddData(map[string]any{
+		TaskVersionsKey: versions,
+	})
+
+	if version.Replaced {
+		return v == workflow.DefaultVersion, nil
+	}
+
+	return v != workflow.DefaultVersion, nil
+}
+
+// validateTaskVersion checks that the version metadata can be parsed
+func validateTaskVersion(taskName string, task model.Task) error {
+	if _, err := metadata.GetVersion(task.GetBase().Metadata); err != nil {
+		return fmt.Errorf("invalid version metadata for task %s: %w", taskName, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestCheckTaskVersion(t *testing.T) {
+	doc := `document:
+  dsl: 1.0.0
+  namespace: default
+  name: version
+  version: 0.0.1
+do:
+  - setup:
+      set:
+        versions: v1
+  - old:
+      metadata:
+        version:
+          changeId: replace-step
+          replaced: true
+      export:
+        as: old
+      set:
+        ran: true
+  - new:
+      metadata:
+        version: replace-step
+      export:
+        as: new
+      set:
+        version: ${ .data.__versions["replace-step"] }
+        versions: ${ .data.versions }`
+
+	tests := []struct {
+		Name           string
+		ExistingRun    bool
+		ExpectedOutput map[string]any
+	}{
+		{
+			Name: "New workflow",
+			ExpectedOutput: map[string]any{
+				"new": map[string]any{
+					"version": float64(1),
+					// The workflow's own data isn't overwritten
+					"versions": "v1",
+				},
+			},
+		},
+		{
+			Name:        "Workflow started before change",
+			ExistingRun: true,
+			ExpectedOutput: map[string]any{
+				"old": map[string]any{
+					"ran": true,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			wf := loadWorkflow(t, doc)
+			env := newTestEnvironment(t, wf)
+
+			if test.ExistingRun {
+				env.OnGetVersion("replace-step", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+			}
+
+			env.ExecuteWorkflow(wf.Document.Name, nil, nil)
+
+			assert.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+
+			var result map[string]any
+			assert.NoError(t, env.GetWorkflowResult(&result))
+			assert.Equal(t, test.ExpectedOutput, result)
+		})
+	}
+}
@@ -19,34 +19,73 @@ package zigflow
 import (
 	"fmt"
 
+	"github.com/mrsimonemms/zigflow/pkg/utils"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
 	"github.com/mrsimonemms/zigflow/pkg/zigflow/tasks"
 	"github.com/rs/zerolog/log"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
 )
 
-func NewWorkflow(temporalWorker worker.Worker, doc *model.Workflow, envvars map[string]any) error {
+// NewWorkflow builds the document's workflows and registers them, and their
+// activities, with the worker. The envvars are available to runtime
+// expressions as .env. The names of the registered workflows are returned in
+// the order they were registered. The activity defaults are used by every task,
+// unless the document or task overrides them.
+func NewWorkflow(
+	temporalWorker worker.Worker,
+	doc *model.Workflow,
+	envvars map[string]any,
+	activityDefaults tasks.ActivityDefaults,
+) ([]string, error) {
 	workflowName := doc.Document.Name
 	l := log.With().Str("workflowName", workflowName).Logger()
 
+	envvars, err := metadata.ResolveEnvvars(doc, envvars)
+	if err != nil {
+		l.Error().Err(err).Msg("Error resolving envvars")
+		return nil, fmt.Errorf("error resolving envvars: %w", err)
+	}
+
+	secrets, err := configureSecrets(doc, envvars)
+	if err != nil {
+		l.Error().Err(err).Msg("Error configuring secrets")
+		return nil, fmt.Errorf("error configuring secrets: %w", err)
+	}
+
+	opts := tasks.DoTaskOpts{
+		// Pass the envvars - this will be passed to the state object
+		Envvars:          envvars,
+		Secrets:          secrets,
+		ActivityDefaults: activityDefaults,
+	}
+
+	registrar := &namingWorker{Worker: temporalWorker, names: make([]string, 0)}
+
+	l.Debug().Msg("Building inline workflows")
+	if err := buildInlineWorkflows(registrar, doc, opts); err != nil {
+		l.Debug().Err(err).Msg("Error building inline workflows")
+		return nil, err
+	}
+
 	l.Debug().Msg("Creating new Do builder")
 	doBuilder, err := tasks.NewDoTaskBuilder(
-		temporalWorker,
+		registrar,
 		&model.DoTask{Do: doc.Do},
 		workflowName,
 		doc,
-		// Pass the envvars - this will be passed to the state object
-		tasks.DoTaskOpts{Envvars: envvars},
+		opts,
 	)
 	if err != nil {
 		l.Error().Err(err).Msg("Error creating Do builder")
-		return fmt.Errorf("error creating do builder: %w", err)
+		return nil, fmt.Errorf("error creating do builder: %w", err)
 	}
 
 	l.Debug().Msg("Building workflow")
 	if _, err := doBuilder.Build(); err != nil {
 		l.Debug().Err(err).Msg("Error building workflow")
-		return fmt.Errorf("error building workflow: %w", err)
+		return nil, fmt.Errorf("error building workflow: %w", err)
 	}
 
 	for _, a := range tasks.ActivitiesList() {
@@ -54,9 +93,69 @@ func NewWorkflow(temporalWorker worker.Worker, doc *model.Workflow, envvars map[
 		temporalWorker.RegisterActivity(a)
 	}
 
+	return registrar.names, nil
+}
+
+// buildInlineWorkflows builds the document's inline workflows, which registers
+// them with the worker. These are built first so a run task can start them.
+func buildInlineWorkflows(temporalWorker worker.Worker, doc *model.Workflow, opts tasks.DoTaskOpts) error {
+	builders, err := tasks.NewInlineWorkflowBuilders(temporalWorker, doc, opts)
+	if err != nil {
+		return fmt.Errorf("error creating inline workflows: %w", err)
+	}
+
+	for _, b := range builders {
+		if _, err := b.Build(); err != nil {
+			return fmt.Errorf("error building inline workflow %s: %w", b.GetTaskName(), err)
+		}
+	}
+
 	return nil
 }
 
+// namingWorker records the names of the workflows registered with the worker.
+// The build events are passed on if the worker is a BuildRecorder.
+type namingWorker struct {
+	worker.Worker
+
+	names []string
+}
+
+func (n *namingWorker) RegisterWorkflowWithOptions(w any, options workflow.RegisterOptions) {
+	n.names = append(n.names, options.Name)
+	n.Worker.RegisterWorkflowWithOptions(w, options)
+}
+
+func (n *namingWorker) BeginTask(workflowName, taskName string, task model.Task) {
+	if recorder, ok := n.Worker.(tasks.BuildRecorder); ok {
+		recorder.BeginTask(workflowName, taskName, task)
+	}
+}
+
+func (n *namingWorker) EndTask() {
+	if recorder, ok := n.Worker.(tasks.BuildRecorder); ok {
+		recorder.EndTask()
+	}
+}
+
+// configureSecrets returns the names of the document's secrets, which the
+// tasks mask. Any secret envvars are also masked in the logs, as their values
+// are already known.
+func configureSecrets(doc *model.Workflow, envvars map[string]any) ([]string, error) {
+	secrets, err := metadata.GetSecrets(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range secrets {
+		if v, ok := envvars[name].(string); ok {
+			utils.LogSecrets.Add(v)
+		}
+	}
+
+	return secrets, nil
+}
+
 func newWorkflowPostLoad(doc *model.Workflow) error {
 	workflowName := doc.Document.Name
 	l := log.With().Str("workflowName", workflowName).Logger()
@@ -77,5 +176,17 @@ func newWorkflowPostLoad(doc *model.Workflow) error {
 		return fmt.Errorf("error post loading workflow: %w", err)
 	}
 
+	inline, err := tasks.NewInlineWorkflowBuilders(nil, doc)
+	if err != nil {
+		l.Error().Err(err).Msg("Error creating inline workflow prep builders")
+		return fmt.Errorf("error creating inline workflow prep builders: %w", err)
+	}
+	for _, b := range inline {
+		if err := b.PostLoad(); err != nil {
+			l.Error().Err(err).Str("inlineWorkflow", b.GetTaskName()).Msg("Error post loading inline workflow")
+			return fmt.Errorf("error post loading inline workflow %s: %w", b.GetTaskName(), err)
+		}
+	}
+
 	return nil
 }
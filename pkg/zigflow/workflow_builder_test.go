@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zigflow_test
+
+import (
+	"testing"
+
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/tasks"
+	"github.com/serverlessworkflow/sdk-go/v3/model"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestNewWorkflowNames(t *testing.T) {
+	var doc *model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: names
+  version: 0.0.1
+do:
+  - main:
+      do:
+        - attempt:
+            try:
+              - step:
+                  set:
+                    hello: world
+            catch:
+              do:
+                - recover:
+                    set:
+                      hello: world
+  - other:
+      do:
+        - step:
+            set:
+              hello: world`), &doc))
+
+	recorder := zigflow.NewRecordingWorker()
+
+	names, err := zigflow.NewWorkflow(recorder, doc, map[string]any{}, tasks.ActivityDefaults{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"workflow_try_attempt", "workflow_catch_attempt", "main", "other"}, names)
+	assert.Equal(t, recorder.Workflows, names)
+}
+
+func TestNewWorkflowInlineWorkflows(t *testing.T) {
+	var doc *model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: inline
+  version: 0.0.1
+  metadata:
+    workflows:
+      notify:
+        - step:
+            set:
+              hello: world
+      charge:
+        - nested:
+            do:
+              - step:
+                  set:
+                    hello: world
+do:
+  - step:
+      run:
+        workflow:
+          namespace: default
+          name: charge
+          version: 0.0.1`), &doc))
+
+	names, err := zigflow.NewWorkflow(zigflow.NewRecordingWorker(), doc, map[string]any{}, tasks.ActivityDefaults{})
+	assert.NoError(t, err)
+
+	// Inline workflows are registered first, sorted by name
+	assert.Equal(t, []string{"nested", "charge", "notify", "inline"}, names)
+}
+
+func TestNewWorkflowBuildError(t *testing.T) {
+	var doc *model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(`document:
+  dsl: 1.0.0
+  namespace: default
+  name: broken
+  version: 0.0.1
+do:
+  - main:
+      do:
+        - step:
+            call: unknown`), &doc))
+
+	_, err := zigflow.NewWorkflow(zigflow.NewRecordingWorker(), doc, map[string]any{}, tasks.ActivityDefaults{})
+	assert.EqualError(
+		t,
+		err,
+		"error building workflow: error building task main: error creating task builder for task step: unsupported call type 'unknown' for task 'step'",
+	)
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 Zigflow authors <https://github.com/mrsimonemms/zigflow/graphs/contributors>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signalwithstart
+
+import (
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/mrsimonemms/zigflow/tests/e2e/utils"
+)
+
+var testCase = utils.TestCase{
+	Name:         "signal-with-start",
+	WorkflowPath: "workflow.yaml",
+	Signal: &zigflow.Signal{
+		Name: "approve",
+		Data: map[string]any{"approved": true},
+	},
+	ExpectedOutput: map[string]any{
+		"result": map[string]any{
+			"approved": true,
+		},
+	},
+	Test: utils.SignalWithStartToCompletion,
+}
+
+func init() {
+	utils.AddTestCase(testCase)
+}
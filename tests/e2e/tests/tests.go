@@ -21,4 +21,5 @@ import (
 	_ "github.com/mrsimonemms/zigflow/tests/e2e/tests/fork"
 	_ "github.com/mrsimonemms/zigflow/tests/e2e/tests/fork-compete"
 	_ "github.com/mrsimonemms/zigflow/tests/e2e/tests/set"
+	_ "github.com/mrsimonemms/zigflow/tests/e2e/tests/signal-with-start"
 )
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/mrsimonemms/golang-helpers/temporal"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow"
+	"github.com/mrsimonemms/zigflow/pkg/zigflow/metadata"
 	zlog "github.com/rs/zerolog/log"
 	"github.com/serverlessworkflow/sdk-go/v3/model"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,7 @@ type TestCase struct {
 	Workflow       *model.Workflow
 	Input          map[string]any
 	ExpectedOutput map[string]any
+	Signal         *zigflow.Signal
 	Test           func(t *testing.T, test TestCase)
 }
 
@@ -44,8 +47,11 @@ func RunToCompletion(t *testing.T, test TestCase) {
 	assert.NoError(t, err)
 	defer c.Close()
 
+	taskQueue, err := metadata.GetTaskQueue(test.Workflow)
+	assert.NoError(t, err)
+
 	workflowOptions := client.StartWorkflowOptions{
-		TaskQueue: test.Workflow.Document.Namespace,
+		TaskQueue: taskQueue,
 	}
 
 	wCtx := context.Background()
@@ -57,3 +63,29 @@ func RunToCompletion(t *testing.T, test TestCase) {
 	assert.NoError(t, we.Get(wCtx, &result))
 	assert.Equal(t, test.ExpectedOutput, result)
 }
+
+// SignalWithStartToCompletion sends the test's signal as the workflow starts,
+// then runs to completion and matches the output
+func SignalWithStartToCompletion(t *testing.T, test TestCase) {
+	c, err := temporal.NewConnectionWithEnvvars(
+		temporal.WithZerolog(&zlog.Logger),
+	)
+	assert.NoError(t, err)
+	defer c.Close()
+
+	taskQueue, err := metadata.GetTaskQueue(test.Workflow)
+	assert.NoError(t, err)
+
+	workflowOptions := client.StartWorkflowOptions{
+		TaskQueue: taskQueue,
+	}
+
+	wCtx := context.Background()
+
+	we, err := zigflow.SignalWithStart(wCtx, c, workflowOptions, test.Workflow.Document.Name, *test.Signal, test.Input)
+	assert.NoError(t, err)
+
+	var result map[string]any
+	assert.NoError(t, we.Get(wCtx, &result))
+	assert.Equal(t, test.ExpectedOutput, result)
+}